@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+	cp "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/logs"
+	"k8s.io/klog/v2"
+)
+
+func main() {
+	bindAddress := pflag.CommandLine.String("bind-address", ":9443",
+		"Address on which the webhook serves HTTPS")
+	tlsCertFile := pflag.CommandLine.String("tls-cert-file", "",
+		"File containing the TLS certificate of the webhook server")
+	tlsKeyFile := pflag.CommandLine.String("tls-private-key-file", "",
+		"File containing the TLS private key of the webhook server")
+	kubeconfig := pflag.CommandLine.String("kubeconfig", "",
+		"Path to the kubeconfig of the cluster holding the MachineClass Secrets, the in-cluster config is used if empty")
+	preflightValidation := pflag.CommandLine.Bool("preflight-validation", false,
+		"Check through the IaaS API that the resources referenced by a MachineClass exist")
+	lenientProviderSpec := pflag.CommandLine.Bool("lenient-provider-spec", false,
+		"Admit MachineClasses with unknown ProviderSpec fields with a warning instead of rejecting them")
+
+	flag.InitFlags()
+	logs.InitLogs()
+	defer logs.FlushLogs()
+
+	if *tlsCertFile == "" || *tlsKeyFile == "" {
+		klog.Fatalf("--tls-cert-file and --tls-private-key-file are required")
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	if err != nil {
+		klog.Fatalf("failed to load kubeconfig: %v", err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		klog.Fatalf("failed to create kubernetes client: %v", err)
+	}
+
+	getSecret := func(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return secret, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate-machineclass", webhook.NewHandler(cp.NewMachineClassValidator(*preflightValidation, *lenientProviderSpec), getSecret))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:              *bindAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("failed to shut down webhook server: %v", err)
+		}
+	}()
+
+	klog.Infof("Serving MachineClass webhook on %s", *bindAddress)
+	if err := server.ListenAndServeTLS(*tlsCertFile, *tlsKeyFile); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		klog.Fatalf("failed to run webhook server: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	_ "github.com/gardener/machine-controller-manager/pkg/util/reflector/prometheus" // for reflector metric registration
 	_ "github.com/gardener/machine-controller-manager/pkg/util/workqueue/prometheus" // for workqueue metric registration
 	"github.com/spf13/pflag"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
 	cp "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider"
 	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/spi"
 	"k8s.io/component-base/cli/flag"
@@ -18,11 +19,45 @@ func main() {
 	s := options.NewMCServer()
 	s.AddFlags(pflag.CommandLine)
 
+	csiDriverNames := pflag.CommandLine.StringSlice("csi-driver-names", cp.DefaultCSIDriverNames,
+		"CSI driver names whose persistent volumes are STACKIT block storage volumes, used to wait for volume detachment during drain")
+
+	schedulingTimeout := pflag.CommandLine.Duration("scheduling-timeout", cp.DefaultSchedulingTimeout,
+		"Duration for which CreateMachine waits for a new server to leave CREATING state, a server going into ERROR state meanwhile is replaced, 0 disables the wait")
+	imageCacheTTL := pflag.CommandLine.Duration("image-cache-ttl", cp.DefaultImageCacheTTL,
+		"Duration for which an image resolved from the image selector of a MachineClass is reused")
+	exhaustedZoneTTL := pflag.CommandLine.Duration("exhausted-zone-ttl", cp.DefaultExhaustedZoneTTL,
+		"Duration for which an availability zone without capacity for a machine type is tried last")
+	preflightValidation := pflag.CommandLine.Bool("preflight-validation", true,
+		"Check through the IaaS API that the resources referenced by a MachineClass exist before creating servers")
+	lenientProviderSpec := pflag.CommandLine.Bool("lenient-provider-spec", false,
+		"Log unknown ProviderSpec fields as warnings instead of rejecting the MachineClass")
+	orphanCollectionInterval := pflag.CommandLine.Duration("orphan-collection-interval", cp.DefaultOrphanCollectionInterval,
+		"Interval in which volumes, NICs and public IPs left behind by deleted machines are collected, e.g. 10m, 0 disables the collection")
+	orphanGracePeriod := pflag.CommandLine.Duration("orphan-grace-period", cp.DefaultOrphanGracePeriod,
+		"Duration for which a resource must be orphaned before it is deleted")
+	orphanCollectionDryRun := pflag.CommandLine.Bool("orphan-collection-dry-run", false,
+		"Only log and count orphaned resources instead of deleting them")
+	retryMutatingCalls := pflag.CommandLine.Bool("retry-mutating-calls", false,
+		"Also retry mutating IaaS calls on rate limiting and server errors, a retried create may leave duplicate resources behind")
+
 	flag.InitFlags()
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
-	provider := cp.NewProvider(&spi.PluginSPIImpl{})
+	retryConfig := client.DefaultRetryConfig()
+	retryConfig.RetryMutating = *retryMutatingCalls
+
+	provider := cp.NewProvider(&spi.PluginSPIImpl{},
+		cp.WithRetryConfig(retryConfig),
+		cp.WithSchedulingTimeout(*schedulingTimeout),
+		cp.WithCSIDriverNames(*csiDriverNames...),
+		cp.WithImageCacheTTL(*imageCacheTTL),
+		cp.WithExhaustedZoneTTL(*exhaustedZoneTTL),
+		cp.WithPreflightValidation(*preflightValidation),
+		cp.WithLenientDecoding(*lenientProviderSpec),
+		cp.WithOrphanCollection(*orphanCollectionInterval, *orphanGracePeriod, *orphanCollectionDryRun),
+	)
 
 	if err := app.Run(s, provider); err != nil {
 		klog.Fatalf("failed to run application: %v", err)
@@ -0,0 +1,37 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# Generates the deep-copy, conversion and defaulting functions of the ProviderSpec API
+
+# renovate: datasource=github-tags depName=kubernetes/code-generator
+CODE_GENERATOR_VERSION="${CODE_GENERATOR_VERSION:-v0.33.3}"
+
+cd "$(dirname "$0")/.."
+
+api_pkg=./pkg/provider/apis
+versioned_pkgs=("$api_pkg/v1alpha1")
+header_file="$(mktemp)"
+trap 'rm -f "$header_file"' EXIT
+
+echo "> Generating deep-copy functions"
+go run "k8s.io/code-generator/cmd/deepcopy-gen@$CODE_GENERATOR_VERSION" \
+  --go-header-file "$header_file" \
+  --output-file zz_generated.deepcopy.go \
+  "$api_pkg" "${versioned_pkgs[@]}"
+# deepcopy-gen names the package after its directory, the internal package is called api
+sed -i.bak 's/^package apis$/package api/' "$api_pkg/zz_generated.deepcopy.go" && rm -f "$api_pkg/zz_generated.deepcopy.go.bak"
+
+echo "> Generating conversion functions"
+go run "k8s.io/code-generator/cmd/conversion-gen@$CODE_GENERATOR_VERSION" \
+  --go-header-file "$header_file" \
+  --output-file zz_generated.conversion.go \
+  "${versioned_pkgs[@]}"
+
+echo "> Generating defaulting functions"
+go run "k8s.io/code-generator/cmd/defaulter-gen@$CODE_GENERATOR_VERSION" \
+  --go-header-file "$header_file" \
+  --output-file zz_generated.defaults.go \
+  "${versioned_pkgs[@]}"
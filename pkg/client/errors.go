@@ -0,0 +1,184 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stackitcloud/stackit-sdk-go/core/oapierror"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+)
+
+// Error classes for STACKIT API failures
+// Errors returned by StackitClient wrap one of these, use errors.Is to check the class
+var (
+	// ErrNotFound indicates the requested resource does not exist (404)
+	ErrNotFound = errors.New("not found")
+	// ErrConflict indicates the request conflicts with the current state of the resource (409)
+	ErrConflict = errors.New("conflict")
+	// ErrQuotaExceeded indicates the project quota does not allow the request
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrCapacityExhausted indicates the platform has no capacity left (e.g. "no valid host was found")
+	ErrCapacityExhausted = errors.New("capacity exhausted")
+	// ErrUnauthenticated indicates the credentials were rejected (401)
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden indicates the service account lacks permissions (403)
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited indicates too many requests were sent (429)
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNotImplemented indicates the API does not support the request (501)
+	ErrNotImplemented = errors.New("not implemented")
+	// ErrServerError indicates a failure on the STACKIT side (5xx)
+	ErrServerError = errors.New("server error")
+
+	// ErrServerNotFound indicates the server was not found (404)
+	//
+	// Deprecated: use ErrNotFound, which is the same error
+	ErrServerNotFound = ErrNotFound
+)
+
+// APIError is a classified error returned by the STACKIT API
+type APIError struct {
+	// Class is one of the Err* error classes, nil if the error could not be classified
+	Class error
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// RequestID is the trace ID of the request (x-trace-id header), empty if unknown
+	RequestID string
+	// Message is the error message returned by the API
+	Message string
+	// RetryAfter is the delay requested by the API via the Retry-After header, zero if not set
+	RetryAfter time.Duration
+	// Err is the underlying SDK error
+	Err error
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	if e.Class != nil {
+		sb.WriteString(e.Class.Error())
+		sb.WriteString(": ")
+	}
+	fmt.Fprintf(&sb, "status %d", e.StatusCode)
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ", message %q", e.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&sb, ", request ID %q", e.RequestID)
+	}
+	return sb.String()
+}
+
+// Unwrap allows errors.Is to match both the error class and the underlying SDK error
+func (e *APIError) Unwrap() []error {
+	if e.Class == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Class, e.Err}
+}
+
+// classifyError turns an SDK error into an *APIError
+// Errors that did not come from the API (e.g. network failures) are returned unchanged
+func classifyError(err error, resp *http.Response) error {
+	var oapiErr *oapierror.GenericOpenAPIError
+	if !errors.As(err, &oapiErr) {
+		return err
+	}
+
+	apiErr := &APIError{
+		StatusCode: oapiErr.StatusCode,
+		Message:    apiErrorMessage(oapiErr),
+		Err:        err,
+	}
+	if resp != nil {
+		apiErr.RequestID = resp.Header.Get("x-trace-id")
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	apiErr.Class = errorClass(apiErr.StatusCode, apiErr.Message)
+
+	return apiErr
+}
+
+// errorClass determines the error class from the HTTP status code and API message
+// Quota and capacity errors are detected by message since the API reports them with varying status codes
+func errorClass(statusCode int, message string) error {
+	if class := ClassifyServerErrorMessage(message); class != nil {
+		return class
+	}
+	if strings.Contains(strings.ToLower(message), "quota") {
+		return ErrQuotaExceeded
+	}
+
+	switch {
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusConflict:
+		return ErrConflict
+	case statusCode == http.StatusUnauthorized:
+		return ErrUnauthenticated
+	case statusCode == http.StatusForbidden:
+		return ErrForbidden
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode == http.StatusNotImplemented:
+		return ErrNotImplemented
+	case statusCode >= http.StatusInternalServerError:
+		return ErrServerError
+	}
+
+	return nil
+}
+
+// ClassifyServerErrorMessage classifies the error message of a server in ERROR state
+// Returns ErrCapacityExhausted if the scheduler found no host for the server, nil otherwise
+func ClassifyServerErrorMessage(message string) error {
+	if strings.Contains(strings.ToLower(message), "no valid host") {
+		return ErrCapacityExhausted
+	}
+	return nil
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either delay seconds or an HTTP date
+// Returns zero if the value is empty, invalid or in the past
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// apiErrorMessage extracts the API message from an SDK error
+// Falls back to the HTTP status text if the body could not be decoded
+func apiErrorMessage(oapiErr *oapierror.GenericOpenAPIError) string {
+	if model, ok := oapiErr.Model.(iaas.Error); ok && model.Msg != "" {
+		return model.Msg
+	}
+
+	var body struct {
+		Msg     string `json:"msg"`
+		Message string `json:"message"`
+	}
+	if len(oapiErr.Body) > 0 && json.Unmarshal(oapiErr.Body, &body) == nil {
+		if body.Msg != "" {
+			return body.Msg
+		}
+		if body.Message != "" {
+			return body.Message
+		}
+	}
+
+	return oapiErr.ErrorMessage
+}
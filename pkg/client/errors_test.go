@@ -0,0 +1,153 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/stackit-sdk-go/core/oapierror"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+)
+
+var _ = Describe("Error classification", func() {
+
+	Describe("classifyError", func() {
+		DescribeTable("should classify GenericOpenAPIError by status code",
+			func(statusCode int, expected error) {
+				err := &oapierror.GenericOpenAPIError{
+					StatusCode:   statusCode,
+					ErrorMessage: http.StatusText(statusCode),
+				}
+
+				result := classifyError(err, nil)
+
+				Expect(errors.Is(result, expected)).To(BeTrue())
+				var apiErr *APIError
+				Expect(errors.As(result, &apiErr)).To(BeTrue())
+				Expect(apiErr.StatusCode).To(Equal(statusCode))
+			},
+			Entry("404 as not found", 404, ErrNotFound),
+			Entry("409 as conflict", 409, ErrConflict),
+			Entry("401 as unauthenticated", 401, ErrUnauthenticated),
+			Entry("403 as forbidden", 403, ErrForbidden),
+			Entry("429 as rate limited", 429, ErrRateLimited),
+			Entry("500 as server error", 500, ErrServerError),
+			Entry("501 as not implemented", 501, ErrNotImplemented),
+			Entry("503 as server error", 503, ErrServerError),
+		)
+
+		It("should detect wrapped GenericOpenAPIError with 404", func() {
+			baseErr := &oapierror.GenericOpenAPIError{
+				StatusCode:   404,
+				ErrorMessage: "Server not found",
+				Body:         []byte(`{"message": "server does not exist"}`),
+			}
+			wrappedErr := fmt.Errorf("failed to get server: %w", baseErr)
+
+			result := classifyError(wrappedErr, nil)
+
+			Expect(errors.Is(result, ErrNotFound)).To(BeTrue())
+			Expect(errors.Is(result, ErrServerNotFound)).To(BeTrue())
+		})
+
+		It("should classify quota errors by message", func() {
+			err := &oapierror.GenericOpenAPIError{
+				StatusCode:   400,
+				ErrorMessage: "400 Bad Request",
+				Model:        iaas.Error{Code: 400, Msg: "Quota exceeded for vCPU"},
+			}
+
+			result := classifyError(err, nil)
+
+			Expect(errors.Is(result, ErrQuotaExceeded)).To(BeTrue())
+			Expect(result.Error()).To(ContainSubstring("Quota exceeded for vCPU"))
+		})
+
+		It("should classify capacity errors by message", func() {
+			err := &oapierror.GenericOpenAPIError{
+				StatusCode:   500,
+				ErrorMessage: "500 Internal Server Error",
+				Body:         []byte(`{"code": 500, "msg": "No valid host was found."}`),
+			}
+
+			result := classifyError(err, nil)
+
+			Expect(errors.Is(result, ErrCapacityExhausted)).To(BeTrue())
+			Expect(errors.Is(result, ErrServerError)).To(BeFalse())
+		})
+
+		It("should carry the request ID from the response", func() {
+			err := &oapierror.GenericOpenAPIError{StatusCode: 500}
+			resp := &http.Response{Header: http.Header{}}
+			resp.Header.Set("X-Trace-Id", "trace-123")
+
+			result := classifyError(err, resp)
+
+			var apiErr *APIError
+			Expect(errors.As(result, &apiErr)).To(BeTrue())
+			Expect(apiErr.RequestID).To(Equal("trace-123"))
+			Expect(result.Error()).To(ContainSubstring("trace-123"))
+		})
+
+		It("should keep the underlying SDK error reachable", func() {
+			err := &oapierror.GenericOpenAPIError{StatusCode: 404}
+
+			result := classifyError(err, nil)
+
+			var oapiErr *oapierror.GenericOpenAPIError
+			Expect(errors.As(result, &oapiErr)).To(BeTrue())
+			Expect(oapiErr.StatusCode).To(Equal(404))
+		})
+
+		It("should leave unclassified API errors without a class", func() {
+			err := &oapierror.GenericOpenAPIError{StatusCode: 400}
+
+			result := classifyError(err, nil)
+
+			var apiErr *APIError
+			Expect(errors.As(result, &apiErr)).To(BeTrue())
+			Expect(apiErr.Class).To(BeNil())
+		})
+
+		It("should return non-API errors unchanged", func() {
+			err := fmt.Errorf("API call failed: %w", errors.New("connection timeout"))
+
+			result := classifyError(err, nil)
+
+			Expect(result).To(BeIdenticalTo(err))
+			Expect(errors.Is(result, ErrNotFound)).To(BeFalse())
+		})
+	})
+
+	Describe("parseRetryAfter", func() {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		It("should parse delay seconds", func() {
+			Expect(parseRetryAfter("3", now)).To(Equal(3 * time.Second))
+		})
+
+		It("should parse an HTTP date", func() {
+			Expect(parseRetryAfter(now.Add(5*time.Second).Format(http.TimeFormat), now)).To(Equal(5 * time.Second))
+		})
+
+		It("should ignore empty, invalid and past values", func() {
+			Expect(parseRetryAfter("", now)).To(BeZero())
+			Expect(parseRetryAfter("soon", now)).To(BeZero())
+			Expect(parseRetryAfter("-1", now)).To(BeZero())
+			Expect(parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)).To(BeZero())
+		})
+	})
+
+	Describe("ClassifyServerErrorMessage", func() {
+		It("should detect capacity exhaustion", func() {
+			Expect(ClassifyServerErrorMessage("No valid host was found. There are not enough hosts available.")).To(MatchError(ErrCapacityExhausted))
+		})
+
+		It("should return nil for other messages", func() {
+			Expect(ClassifyServerErrorMessage("image could not be downloaded")).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,1052 @@
+// Package fake provides an in-memory fake of the STACKIT IaaS v2 API for hermetic tests
+//
+// The fake serves the endpoints used by the provider over an httptest.Server, so the
+// real SdkStackitClient can be exercised end to end by pointing STACKIT_IAAS_ENDPOINT
+// to IaaSServer.URL and setting STACKIT_NO_AUTH=true.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+)
+
+// Server states used by the fake state machine
+const (
+	StatusCreating = "CREATING"
+	StatusActive   = "ACTIVE"
+	StatusDeleting = "DELETING"
+	StatusError    = "ERROR"
+	StatusInactive = "INACTIVE"
+)
+
+// Volume states used by the fake state machine
+const (
+	VolumeStatusCreating  = "CREATING"
+	VolumeStatusAvailable = "AVAILABLE"
+	VolumeStatusAttached  = "ATTACHED"
+)
+
+// Operation names used to target faults
+const (
+	OpCreateServer   = "CreateServer"
+	OpGetServer      = "GetServer"
+	OpListServers    = "ListServers"
+	OpDeleteServer   = "DeleteServer"
+	OpStopServer     = "StopServer"
+	OpListServerNICs = "ListServerNICs"
+	OpUpdateNIC      = "UpdateNIC"
+	OpAttachVolume   = "AttachVolume"
+	OpCreateVolume   = "CreateVolume"
+	OpGetVolume      = "GetVolume"
+	OpListVolumes    = "ListVolumes"
+	OpUpdateVolume   = "UpdateVolume"
+	OpDeleteVolume   = "DeleteVolume"
+	OpCreatePublicIP = "CreatePublicIP"
+	OpListPublicIPs  = "ListPublicIPs"
+	OpGetPublicIP    = "GetPublicIP"
+	OpUpdatePublicIP = "UpdatePublicIP"
+	OpDeletePublicIP = "DeletePublicIP"
+	OpListImages     = "ListImages"
+
+	OpGetImage         = "GetImage"
+	OpGetMachineType   = "GetMachineType"
+	OpGetNetwork       = "GetNetwork"
+	OpGetNIC           = "GetNIC"
+	OpListNICs         = "ListNICs"
+	OpDeleteNIC        = "DeleteNIC"
+	OpGetSecurityGroup = "GetSecurityGroup"
+	OpGetAffinityGroup = "GetAffinityGroup"
+	OpGetKeypair       = "GetKeypair"
+)
+
+// Fault describes an error response injected for an operation
+type Fault struct {
+	// Operation is one of the Op* constants, empty matches every operation
+	Operation string
+	// StatusCode is the HTTP status code of the error response
+	StatusCode int
+	// Message is returned in the error body as {"code": StatusCode, "msg": Message}
+	Message string
+	// Header is added to the error response (e.g. Retry-After)
+	Header http.Header
+	// Times is the number of requests the fault applies to, zero means every request
+	Times int
+}
+
+// serverState is a server stored in the fake with the bookkeeping of its state machine
+type serverState struct {
+	projectID string
+	region    string
+	server    iaas.Server
+	nicIDs    []string
+	// polls counts GetServer calls since the last state transition
+	polls int
+}
+
+// volumeState is a volume stored in the fake with the bookkeeping of its state machine
+type volumeState struct {
+	projectID string
+	region    string
+	volume    iaas.Volume
+	// polls counts GetVolume calls since the volume was created
+	polls int
+}
+
+// publicIPState is a public IP stored in the fake
+type publicIPState struct {
+	projectID string
+	region    string
+	publicIP  iaas.PublicIp
+}
+
+// IaaSServer is an in-memory fake of the STACKIT IaaS v2 API
+//
+// Servers follow the state machine CREATING -> ACTIVE -> DELETING -> (gone).
+// Each transition happens after a configurable number of GetServer calls,
+// which keeps tests deterministic without relying on wall clock time.
+// Volumes follow CREATING -> AVAILABLE <-> ATTACHED, driven by GetVolume calls
+// and volume attachments. Volumes are detached when their server is removed.
+// Public IPs are reported on the server NIC they are associated with.
+type IaaSServer struct {
+	*httptest.Server
+
+	// CreatingPolls is the number of GetServer calls a new server stays in CREATING
+	CreatingPolls int
+	// DeletingPolls is the number of GetServer calls a deleted server stays in DELETING
+	DeletingPolls int
+
+	mu      sync.Mutex
+	servers map[string]*serverState
+	volumes map[string]*volumeState
+	nics    map[string]*iaas.NIC
+	// publicIPs are keyed by public IP ID
+	publicIPs map[string]*publicIPState
+	// images are visible in every project and region, same as public images
+	images []iaas.Image
+	// the following resources are only read by the provider, they are visible in every project and region
+	machineTypes   map[string]iaas.MachineType
+	networks       map[string]iaas.Network
+	securityGroups map[string]iaas.SecurityGroup
+	affinityGroups map[string]iaas.AffinityGroup
+	keypairs       map[string]iaas.Keypair
+
+	faults []*Fault
+	nextIP int
+}
+
+// NewIaaSServer starts a new fake IaaS API server
+// The caller must call Close when done
+func NewIaaSServer() *IaaSServer {
+	s := &IaaSServer{
+		CreatingPolls:  1,
+		DeletingPolls:  1,
+		servers:        make(map[string]*serverState),
+		volumes:        make(map[string]*volumeState),
+		nics:           make(map[string]*iaas.NIC),
+		publicIPs:      make(map[string]*publicIPState),
+		machineTypes:   make(map[string]iaas.MachineType),
+		networks:       make(map[string]iaas.Network),
+		securityGroups: make(map[string]iaas.SecurityGroup),
+		affinityGroups: make(map[string]iaas.AffinityGroup),
+		keypairs:       make(map[string]iaas.Keypair),
+	}
+
+	const base = "/v2/projects/{projectId}/regions/{region}"
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+base+"/servers", s.handle(OpCreateServer, s.createServer))
+	mux.HandleFunc("GET "+base+"/servers", s.handle(OpListServers, s.listServers))
+	mux.HandleFunc("GET "+base+"/servers/{serverId}", s.handle(OpGetServer, s.getServer))
+	mux.HandleFunc("DELETE "+base+"/servers/{serverId}", s.handle(OpDeleteServer, s.deleteServer))
+	mux.HandleFunc("POST "+base+"/servers/{serverId}/stop", s.handle(OpStopServer, s.stopServer))
+	mux.HandleFunc("GET "+base+"/servers/{serverId}/nics", s.handle(OpListServerNICs, s.listServerNICs))
+	mux.HandleFunc("PATCH "+base+"/networks/{networkId}/nics/{nicId}", s.handle(OpUpdateNIC, s.updateNIC))
+	mux.HandleFunc("PUT "+base+"/servers/{serverId}/volume-attachments/{volumeId}", s.handle(OpAttachVolume, s.attachVolume))
+	mux.HandleFunc("POST "+base+"/volumes", s.handle(OpCreateVolume, s.createVolume))
+	mux.HandleFunc("GET "+base+"/volumes", s.handle(OpListVolumes, s.listVolumes))
+	mux.HandleFunc("GET "+base+"/volumes/{volumeId}", s.handle(OpGetVolume, s.getVolume))
+	mux.HandleFunc("PATCH "+base+"/volumes/{volumeId}", s.handle(OpUpdateVolume, s.updateVolume))
+	mux.HandleFunc("DELETE "+base+"/volumes/{volumeId}", s.handle(OpDeleteVolume, s.deleteVolume))
+	mux.HandleFunc("POST "+base+"/public-ips", s.handle(OpCreatePublicIP, s.createPublicIP))
+	mux.HandleFunc("GET "+base+"/public-ips", s.handle(OpListPublicIPs, s.listPublicIPs))
+	mux.HandleFunc("GET "+base+"/public-ips/{publicIpId}", s.handle(OpGetPublicIP, s.getPublicIP))
+	mux.HandleFunc("PATCH "+base+"/public-ips/{publicIpId}", s.handle(OpUpdatePublicIP, s.updatePublicIP))
+	mux.HandleFunc("DELETE "+base+"/public-ips/{publicIpId}", s.handle(OpDeletePublicIP, s.deletePublicIP))
+	mux.HandleFunc("GET "+base+"/images", s.handle(OpListImages, s.listImages))
+	mux.HandleFunc("GET "+base+"/images/{imageId}", s.handle(OpGetImage, s.getImage))
+	mux.HandleFunc("GET "+base+"/machine-types/{machineType}", s.handle(OpGetMachineType, s.getMachineType))
+	mux.HandleFunc("GET "+base+"/networks/{networkId}", s.handle(OpGetNetwork, s.getNetwork))
+	mux.HandleFunc("GET "+base+"/nics", s.handle(OpListNICs, s.listNICs))
+	mux.HandleFunc("GET "+base+"/nics/{nicId}", s.handle(OpGetNIC, s.getNIC))
+	mux.HandleFunc("DELETE "+base+"/networks/{networkId}/nics/{nicId}", s.handle(OpDeleteNIC, s.deleteNIC))
+	mux.HandleFunc("GET "+base+"/security-groups/{securityGroupId}", s.handle(OpGetSecurityGroup, s.getSecurityGroup))
+	mux.HandleFunc("GET "+base+"/affinity-groups/{affinityGroupId}", s.handle(OpGetAffinityGroup, s.getAffinityGroup))
+	mux.HandleFunc("GET /v2/keypairs/{keypairName}", s.handle(OpGetKeypair, s.getKeypair))
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// InjectFault makes matching requests fail with the given fault
+// Faults are evaluated in the order they were injected
+func (s *IaaSServer) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults
+func (s *IaaSServer) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// AddNIC stores a pre-created NIC that can be referenced by nicIds in CreateServer
+func (s *IaaSServer) AddNIC(nic iaas.NIC) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nics[nic.GetId()] = &nic
+}
+
+// SetServerStatus forces a server into the given status, e.g. ERROR with an error message
+func (s *IaaSServer) SetServerStatus(serverID, status, errorMessage string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.servers[serverID]
+	if !ok {
+		return fmt.Errorf("server %q not found", serverID)
+	}
+	st.server.Status = &status
+	st.server.ErrorMessage = nil
+	if errorMessage != "" {
+		st.server.ErrorMessage = &errorMessage
+	}
+	st.polls = 0
+	return nil
+}
+
+// Servers returns a snapshot of all servers, including servers in DELETING state
+func (s *IaaSServer) Servers() []iaas.Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]iaas.Server, 0, len(s.servers))
+	for _, st := range s.servers {
+		result = append(result, st.server)
+	}
+	return result
+}
+
+// Volumes returns a snapshot of all volumes created through the fake
+func (s *IaaSServer) Volumes() []iaas.Volume {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]iaas.Volume, 0, len(s.volumes))
+	for _, vs := range s.volumes {
+		result = append(result, vs.volume)
+	}
+	return result
+}
+
+// AddPublicIP stores a pre-allocated public IP in the given project and region, e.g. a member of a public IP pool
+func (s *IaaSServer) AddPublicIP(projectID, region string, publicIP iaas.PublicIp) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publicIPs[publicIP.GetId()] = &publicIPState{projectID: projectID, region: region, publicIP: publicIP}
+}
+
+// AddImage stores an image that is listed by ListImages
+func (s *IaaSServer) AddImage(image iaas.Image) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.images = append(s.images, image)
+}
+
+// AddMachineType stores a machine type that is returned by GetMachineType
+func (s *IaaSServer) AddMachineType(machineType iaas.MachineType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.machineTypes[machineType.Name] = machineType
+}
+
+// AddNetwork stores a network that is returned by GetNetwork
+func (s *IaaSServer) AddNetwork(network iaas.Network) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.networks[network.Id] = network
+}
+
+// AddSecurityGroup stores a security group that is returned by GetSecurityGroup
+func (s *IaaSServer) AddSecurityGroup(securityGroup iaas.SecurityGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.securityGroups[securityGroup.GetId()] = securityGroup
+}
+
+// AddAffinityGroup stores an affinity group that is returned by GetAffinityGroup
+func (s *IaaSServer) AddAffinityGroup(affinityGroup iaas.AffinityGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.affinityGroups[affinityGroup.GetId()] = affinityGroup
+}
+
+// AddKeypair stores an SSH keypair that is returned by GetKeypair
+func (s *IaaSServer) AddKeypair(keypair iaas.Keypair) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keypairs[keypair.GetName()] = keypair
+}
+
+// PublicIPs returns a snapshot of all public IPs
+func (s *IaaSServer) PublicIPs() []iaas.PublicIp {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]iaas.PublicIp, 0, len(s.publicIPs))
+	for _, ps := range s.publicIPs {
+		result = append(result, ps.publicIP)
+	}
+	return result
+}
+
+// handle wraps a handler with locking and fault injection
+func (s *IaaSServer) handle(operation string, h func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if f := s.matchFault(operation); f != nil {
+			maps.Copy(w.Header(), f.Header)
+			writeError(w, f.StatusCode, f.Message)
+			return
+		}
+
+		h(w, r)
+	}
+}
+
+// matchFault returns the first fault for the operation and consumes one of its uses
+func (s *IaaSServer) matchFault(operation string) *Fault {
+	for i, f := range s.faults {
+		if f.Operation != "" && f.Operation != operation {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = slices.Delete(s.faults, i, i+1)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// createServerPayload mirrors iaas.CreateServerPayload
+// The SDK type is not used for decoding since its networking union rejects empty objects
+type createServerPayload struct {
+	Name             string           `json:"name"`
+	MachineType      string           `json:"machineType"`
+	ImageID          *string          `json:"imageId,omitempty"`
+	Labels           map[string]any   `json:"labels,omitempty"`
+	Metadata         map[string]any   `json:"metadata,omitempty"`
+	AvailabilityZone *string          `json:"availabilityZone,omitempty"`
+	AffinityGroup    *string          `json:"affinityGroup,omitempty"`
+	KeypairName      *string          `json:"keypairName,omitempty"`
+	SecurityGroups   []string         `json:"securityGroups,omitempty"`
+	Volumes          []string         `json:"volumes,omitempty"`
+	BootVolume       *iaas.BootVolume `json:"bootVolume,omitempty"`
+	Networking       struct {
+		NetworkID string   `json:"networkId,omitempty"`
+		NICIDs    []string `json:"nicIds,omitempty"`
+	} `json:"networking"`
+}
+
+func (s *IaaSServer) createServer(w http.ResponseWriter, r *http.Request) {
+	var payload createServerPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %v", err))
+		return
+	}
+
+	id := uuid.NewString()
+	now := time.Now().UTC()
+	st := &serverState{
+		projectID: r.PathValue("projectId"),
+		region:    r.PathValue("region"),
+		server: iaas.Server{
+			Id:               &id,
+			Name:             payload.Name,
+			MachineType:      payload.MachineType,
+			Status:           new(StatusCreating),
+			Labels:           payload.Labels,
+			Metadata:         payload.Metadata,
+			AvailabilityZone: payload.AvailabilityZone,
+			AffinityGroup:    payload.AffinityGroup,
+			ImageId:          payload.ImageID,
+			KeypairName:      payload.KeypairName,
+			SecurityGroups:   payload.SecurityGroups,
+			Volumes:          payload.Volumes,
+			BootVolume:       payload.BootVolume,
+			CreatedAt:        &now,
+		},
+	}
+
+	// the boot volume is created along with the server and listed among its volumes
+	if payload.BootVolume != nil && payload.BootVolume.Id == nil {
+		bootVolume := *payload.BootVolume
+		bootVolume.Id = new(uuid.NewString())
+		st.server.BootVolume = &bootVolume
+		st.server.Volumes = append([]string{*bootVolume.Id}, st.server.Volumes...)
+	}
+
+	// networkId takes precedence over nicIds, same as in the real API
+	if payload.Networking.NetworkID != "" {
+		nic := s.newNIC(payload.Networking.NetworkID, id)
+		st.nicIDs = append(st.nicIDs, nic.GetId())
+	} else {
+		for _, nicID := range payload.Networking.NICIDs {
+			nic, ok := s.nics[nicID]
+			if !ok {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("NIC %q not found", nicID))
+				return
+			}
+			nic.Device = &id
+			st.nicIDs = append(st.nicIDs, nicID)
+		}
+	}
+
+	s.servers[id] = st
+	writeJSON(w, http.StatusCreated, s.render(st, st.server, false))
+}
+
+func (s *IaaSServer) getServer(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.lookupServer(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("server %q not found", r.PathValue("serverId")))
+		return
+	}
+
+	// advance the state machine, the response reflects the state before the transition
+	server := st.server
+	st.polls++
+	switch st.server.GetStatus() {
+	case StatusCreating:
+		if st.polls >= s.CreatingPolls {
+			now := time.Now().UTC()
+			st.server.Status = new(StatusActive)
+			st.server.PowerStatus = new("RUNNING")
+			st.server.LaunchedAt = &now
+			st.polls = 0
+		}
+	case StatusDeleting:
+		if st.polls >= s.DeletingPolls {
+			s.removeServer(st)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, s.render(st, server, isDetailed(r)))
+}
+
+func (s *IaaSServer) listServers(w http.ResponseWriter, r *http.Request) {
+	selector, err := parseLabelSelector(r.URL.Query().Get("label_selector"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items := make([]iaas.Server, 0)
+	for _, st := range s.servers {
+		if st.projectID != r.PathValue("projectId") || st.region != r.PathValue("region") {
+			continue
+		}
+		if !matchLabels(st.server.Labels, selector) {
+			continue
+		}
+		items = append(items, s.render(st, st.server, isDetailed(r)))
+	}
+	slices.SortFunc(items, func(a, b iaas.Server) int { return strings.Compare(a.GetName(), b.GetName()) })
+
+	writeJSON(w, http.StatusOK, iaas.ServerListResponse{Items: items})
+}
+
+func (s *IaaSServer) deleteServer(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.lookupServer(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("server %q not found", r.PathValue("serverId")))
+		return
+	}
+
+	st.server.Status = new(StatusDeleting)
+	st.polls = 0
+	if s.DeletingPolls <= 0 {
+		s.removeServer(st)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *IaaSServer) stopServer(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.lookupServer(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("server %q not found", r.PathValue("serverId")))
+		return
+	}
+	if st.server.GetStatus() != StatusActive {
+		writeError(w, http.StatusConflict, fmt.Sprintf("server %q is %s", st.server.GetId(), st.server.GetStatus()))
+		return
+	}
+
+	st.server.Status = new(StatusInactive)
+	st.server.PowerStatus = new("STOPPED")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *IaaSServer) listServerNICs(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.lookupServer(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("server %q not found", r.PathValue("serverId")))
+		return
+	}
+
+	items := make([]iaas.NIC, 0, len(st.nicIDs))
+	for _, nicID := range st.nicIDs {
+		if nic, ok := s.nics[nicID]; ok {
+			items = append(items, *nic)
+		}
+	}
+	writeJSON(w, http.StatusOK, iaas.NICListResponse{Items: items})
+}
+
+func (s *IaaSServer) updateNIC(w http.ResponseWriter, r *http.Request) {
+	nic, ok := s.nics[r.PathValue("nicId")]
+	if !ok || nic.GetNetworkId() != r.PathValue("networkId") {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("NIC %q not found", r.PathValue("nicId")))
+		return
+	}
+
+	var payload iaas.UpdateNicPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %v", err))
+		return
+	}
+	if payload.AllowedAddresses != nil {
+		nic.AllowedAddresses = payload.AllowedAddresses
+	}
+	if payload.Labels != nil {
+		nic.Labels = payload.Labels
+	}
+
+	writeJSON(w, http.StatusOK, nic)
+}
+
+func (s *IaaSServer) attachVolume(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.lookupServer(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("server %q not found", r.PathValue("serverId")))
+		return
+	}
+
+	volumeID := r.PathValue("volumeId")
+	if slices.Contains(st.server.Volumes, volumeID) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("volume %q is already attached", volumeID))
+		return
+	}
+	// volumes created through the fake must be available, unknown volumes are accepted as is
+	if vs, ok := s.volumes[volumeID]; ok {
+		if vs.volume.GetStatus() != VolumeStatusAvailable {
+			writeError(w, http.StatusConflict, fmt.Sprintf("volume %q is %s", volumeID, vs.volume.GetStatus()))
+			return
+		}
+		vs.volume.Status = new(VolumeStatusAttached)
+		vs.volume.ServerId = st.server.Id
+	}
+	st.server.Volumes = append(st.server.Volumes, volumeID)
+
+	writeJSON(w, http.StatusOK, iaas.VolumeAttachment{ServerId: st.server.Id, VolumeId: &volumeID})
+}
+
+// createVolumePayload mirrors iaas.CreateVolumePayload
+type createVolumePayload struct {
+	Name             *string            `json:"name,omitempty"`
+	AvailabilityZone string             `json:"availabilityZone"`
+	Size             *int64             `json:"size,omitempty"`
+	PerformanceClass *string            `json:"performanceClass,omitempty"`
+	Source           *iaas.VolumeSource `json:"source,omitempty"`
+	Labels           map[string]any     `json:"labels,omitempty"`
+}
+
+func (s *IaaSServer) createVolume(w http.ResponseWriter, r *http.Request) {
+	var payload createVolumePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %v", err))
+		return
+	}
+	if payload.AvailabilityZone == "" {
+		writeError(w, http.StatusBadRequest, "availabilityZone is required")
+		return
+	}
+
+	id := uuid.NewString()
+	now := time.Now().UTC()
+	vs := &volumeState{
+		projectID: r.PathValue("projectId"),
+		region:    r.PathValue("region"),
+		volume: iaas.Volume{
+			Id:               &id,
+			Name:             payload.Name,
+			AvailabilityZone: payload.AvailabilityZone,
+			Size:             payload.Size,
+			PerformanceClass: payload.PerformanceClass,
+			Source:           payload.Source,
+			Labels:           payload.Labels,
+			Status:           new(VolumeStatusCreating),
+			CreatedAt:        &now,
+		},
+	}
+	if s.CreatingPolls <= 0 {
+		vs.volume.Status = new(VolumeStatusAvailable)
+	}
+
+	s.volumes[id] = vs
+	writeJSON(w, http.StatusCreated, vs.volume)
+}
+
+func (s *IaaSServer) getVolume(w http.ResponseWriter, r *http.Request) {
+	vs, ok := s.lookupVolume(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("volume %q not found", r.PathValue("volumeId")))
+		return
+	}
+
+	// advance the state machine, the response reflects the state before the transition
+	volume := vs.volume
+	vs.polls++
+	if vs.volume.GetStatus() == VolumeStatusCreating && vs.polls >= s.CreatingPolls {
+		vs.volume.Status = new(VolumeStatusAvailable)
+	}
+
+	writeJSON(w, http.StatusOK, volume)
+}
+
+func (s *IaaSServer) listVolumes(w http.ResponseWriter, r *http.Request) {
+	selector, err := parseLabelSelector(r.URL.Query().Get("label_selector"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items := make([]iaas.Volume, 0)
+	for _, vs := range s.volumes {
+		if vs.projectID != r.PathValue("projectId") || vs.region != r.PathValue("region") {
+			continue
+		}
+		if !matchLabels(vs.volume.Labels, selector) {
+			continue
+		}
+		items = append(items, vs.volume)
+	}
+	slices.SortFunc(items, func(a, b iaas.Volume) int { return strings.Compare(a.GetName(), b.GetName()) })
+
+	writeJSON(w, http.StatusOK, iaas.VolumeListResponse{Items: items})
+}
+
+func (s *IaaSServer) updateVolume(w http.ResponseWriter, r *http.Request) {
+	vs, ok := s.lookupVolume(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("volume %q not found", r.PathValue("volumeId")))
+		return
+	}
+
+	var payload iaas.UpdateVolumePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %v", err))
+		return
+	}
+	if payload.Labels != nil {
+		vs.volume.Labels = payload.Labels
+	}
+
+	writeJSON(w, http.StatusOK, vs.volume)
+}
+
+func (s *IaaSServer) deleteVolume(w http.ResponseWriter, r *http.Request) {
+	vs, ok := s.lookupVolume(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("volume %q not found", r.PathValue("volumeId")))
+		return
+	}
+	if vs.volume.ServerId != nil {
+		writeError(w, http.StatusConflict, fmt.Sprintf("volume %q is attached to server %q", vs.volume.GetId(), vs.volume.GetServerId()))
+		return
+	}
+
+	delete(s.volumes, vs.volume.GetId())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// createPublicIPPayload mirrors iaas.CreatePublicIPPayload
+type createPublicIPPayload struct {
+	Labels           map[string]any `json:"labels,omitempty"`
+	NetworkInterface *string        `json:"networkInterface,omitempty"`
+}
+
+func (s *IaaSServer) createPublicIP(w http.ResponseWriter, r *http.Request) {
+	var payload createPublicIPPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %v", err))
+		return
+	}
+	if payload.NetworkInterface != nil && s.nics[*payload.NetworkInterface] == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("NIC %q not found", *payload.NetworkInterface))
+		return
+	}
+
+	s.nextIP++
+	id := uuid.NewString()
+	ps := &publicIPState{
+		projectID: r.PathValue("projectId"),
+		region:    r.PathValue("region"),
+		publicIP: iaas.PublicIp{
+			Id:               &id,
+			Ip:               new(fmt.Sprintf("192.0.%d.%d", 2+s.nextIP/250, s.nextIP%250+2)),
+			Labels:           payload.Labels,
+			NetworkInterface: *iaas.NewNullableString(payload.NetworkInterface),
+		},
+	}
+
+	s.publicIPs[id] = ps
+	writeJSON(w, http.StatusCreated, ps.publicIP)
+}
+
+func (s *IaaSServer) listPublicIPs(w http.ResponseWriter, r *http.Request) {
+	selector, err := parseLabelSelector(r.URL.Query().Get("label_selector"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items := make([]iaas.PublicIp, 0)
+	for _, ps := range s.publicIPs {
+		if ps.projectID != r.PathValue("projectId") || ps.region != r.PathValue("region") {
+			continue
+		}
+		if !matchLabels(ps.publicIP.Labels, selector) {
+			continue
+		}
+		items = append(items, ps.publicIP)
+	}
+	slices.SortFunc(items, func(a, b iaas.PublicIp) int { return strings.Compare(a.GetIp(), b.GetIp()) })
+
+	writeJSON(w, http.StatusOK, iaas.PublicIpListResponse{Items: items})
+}
+
+func (s *IaaSServer) getPublicIP(w http.ResponseWriter, r *http.Request) {
+	ps, ok := s.lookupPublicIP(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("public IP %q not found", r.PathValue("publicIpId")))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ps.publicIP)
+}
+
+// updatePublicIPPayload mirrors iaas.UpdatePublicIPPayload
+// NetworkInterface is kept raw to tell an explicit null (dissociate) from an absent field
+type updatePublicIPPayload struct {
+	Labels           map[string]any  `json:"labels,omitempty"`
+	NetworkInterface json.RawMessage `json:"networkInterface,omitempty"`
+}
+
+func (s *IaaSServer) updatePublicIP(w http.ResponseWriter, r *http.Request) {
+	ps, ok := s.lookupPublicIP(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("public IP %q not found", r.PathValue("publicIpId")))
+		return
+	}
+
+	var payload updatePublicIPPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %v", err))
+		return
+	}
+
+	if payload.NetworkInterface != nil {
+		var nicID *string
+		if err := json.Unmarshal(payload.NetworkInterface, &nicID); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid networkInterface: %v", err))
+			return
+		}
+		if nicID != nil && *nicID == "" {
+			writeError(w, http.StatusBadRequest, "networkInterface must be a NIC ID or null")
+			return
+		}
+		if nicID != nil && s.nics[*nicID] == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("NIC %q not found", *nicID))
+			return
+		}
+		ps.publicIP.NetworkInterface = *iaas.NewNullableString(nicID)
+	}
+	if payload.Labels != nil {
+		ps.publicIP.Labels = payload.Labels
+	}
+
+	writeJSON(w, http.StatusOK, ps.publicIP)
+}
+
+func (s *IaaSServer) deletePublicIP(w http.ResponseWriter, r *http.Request) {
+	ps, ok := s.lookupPublicIP(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("public IP %q not found", r.PathValue("publicIpId")))
+		return
+	}
+
+	delete(s.publicIPs, ps.publicIP.GetId())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *IaaSServer) listImages(w http.ResponseWriter, _ *http.Request) {
+	items := append(make([]iaas.Image, 0, len(s.images)), s.images...)
+	writeJSON(w, http.StatusOK, iaas.ImageListResponse{Items: items})
+}
+
+func (s *IaaSServer) getImage(w http.ResponseWriter, r *http.Request) {
+	index := slices.IndexFunc(s.images, func(image iaas.Image) bool {
+		return image.GetId() == r.PathValue("imageId")
+	})
+	if index < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("image %q not found", r.PathValue("imageId")))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.images[index])
+}
+
+func (s *IaaSServer) getMachineType(w http.ResponseWriter, r *http.Request) {
+	writeResource(w, s.machineTypes, "machine type", r.PathValue("machineType"))
+}
+
+func (s *IaaSServer) getNetwork(w http.ResponseWriter, r *http.Request) {
+	writeResource(w, s.networks, "network", r.PathValue("networkId"))
+}
+
+func (s *IaaSServer) getNIC(w http.ResponseWriter, r *http.Request) {
+	nic, ok := s.nics[r.PathValue("nicId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("nic %q not found", r.PathValue("nicId")))
+		return
+	}
+	writeJSON(w, http.StatusOK, nic)
+}
+
+func (s *IaaSServer) listNICs(w http.ResponseWriter, r *http.Request) {
+	selector, err := parseLabelSelector(r.URL.Query().Get("label_selector"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items := make([]iaas.NIC, 0)
+	for _, nic := range s.nics {
+		if matchLabels(nic.Labels, selector) {
+			items = append(items, *nic)
+		}
+	}
+	slices.SortFunc(items, func(a, b iaas.NIC) int { return strings.Compare(a.GetId(), b.GetId()) })
+
+	writeJSON(w, http.StatusOK, iaas.NICListResponse{Items: items})
+}
+
+func (s *IaaSServer) deleteNIC(w http.ResponseWriter, r *http.Request) {
+	nic, ok := s.nics[r.PathValue("nicId")]
+	if !ok || nic.GetNetworkId() != r.PathValue("networkId") {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("nic %q not found", r.PathValue("nicId")))
+		return
+	}
+	if nic.GetDevice() != "" {
+		writeError(w, http.StatusConflict, fmt.Sprintf("nic %q is attached to server %q", nic.GetId(), nic.GetDevice()))
+		return
+	}
+
+	delete(s.nics, nic.GetId())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *IaaSServer) getSecurityGroup(w http.ResponseWriter, r *http.Request) {
+	writeResource(w, s.securityGroups, "security group", r.PathValue("securityGroupId"))
+}
+
+func (s *IaaSServer) getAffinityGroup(w http.ResponseWriter, r *http.Request) {
+	writeResource(w, s.affinityGroups, "affinity group", r.PathValue("affinityGroupId"))
+}
+
+func (s *IaaSServer) getKeypair(w http.ResponseWriter, r *http.Request) {
+	writeResource(w, s.keypairs, "keypair", r.PathValue("keypairName"))
+}
+
+// lookupPublicIP returns the public IP addressed by the request path
+func (s *IaaSServer) lookupPublicIP(r *http.Request) (*publicIPState, bool) {
+	ps, ok := s.publicIPs[r.PathValue("publicIpId")]
+	if !ok || ps.projectID != r.PathValue("projectId") || ps.region != r.PathValue("region") {
+		return nil, false
+	}
+	return ps, true
+}
+
+// lookupVolume returns the volume addressed by the request path
+func (s *IaaSServer) lookupVolume(r *http.Request) (*volumeState, bool) {
+	vs, ok := s.volumes[r.PathValue("volumeId")]
+	if !ok || vs.projectID != r.PathValue("projectId") || vs.region != r.PathValue("region") {
+		return nil, false
+	}
+	return vs, true
+}
+
+// lookupServer returns the server addressed by the request path
+func (s *IaaSServer) lookupServer(r *http.Request) (*serverState, bool) {
+	st, ok := s.servers[r.PathValue("serverId")]
+	if !ok || st.projectID != r.PathValue("projectId") || st.region != r.PathValue("region") {
+		return nil, false
+	}
+	return st, true
+}
+
+// render returns the API representation of a server
+// NICs are only included if details were requested, same as in the real API
+func (s *IaaSServer) render(st *serverState, server iaas.Server, details bool) iaas.Server {
+	if !details {
+		return server
+	}
+	for _, nicID := range st.nicIDs {
+		nic, ok := s.nics[nicID]
+		if !ok {
+			continue
+		}
+		server.Nics = append(server.Nics, iaas.ServerNetwork{
+			NicId:            nic.GetId(),
+			NetworkId:        nic.GetNetworkId(),
+			AllowedAddresses: nic.AllowedAddresses,
+			Ipv4:             nic.Ipv4,
+			Ipv6:             nic.Ipv6,
+			Mac:              nic.GetMac(),
+			NicSecurity:      true,
+			PublicIp:         s.publicIPOfNIC(nicID),
+		})
+	}
+	return server
+}
+
+// publicIPOfNIC returns the public IP associated with the NIC, nil if there is none
+func (s *IaaSServer) publicIPOfNIC(nicID string) *string {
+	for _, ps := range s.publicIPs {
+		if ps.publicIP.GetNetworkInterface() == nicID {
+			return ps.publicIP.Ip
+		}
+	}
+	return nil
+}
+
+// isDetailed returns true if the request asks for server details
+func isDetailed(r *http.Request) bool {
+	return r.URL.Query().Get("details") == "true"
+}
+
+// removeServer deletes a server and the NICs that were auto-created for it
+// Volumes and pre-created NICs attached to the server are detached, public IPs of deleted NICs are dissociated
+func (s *IaaSServer) removeServer(st *serverState) {
+	for _, nicID := range st.nicIDs {
+		nic, ok := s.nics[nicID]
+		if !ok {
+			continue
+		}
+		if nic.GetType() != "server" {
+			nic.Device = nil
+			continue
+		}
+		delete(s.nics, nicID)
+		for _, ps := range s.publicIPs {
+			if ps.publicIP.GetNetworkInterface() == nicID {
+				ps.publicIP.NetworkInterface.Unset()
+			}
+		}
+	}
+	for _, volumeID := range st.server.Volumes {
+		if vs, ok := s.volumes[volumeID]; ok {
+			vs.volume.Status = new(VolumeStatusAvailable)
+			vs.volume.ServerId = nil
+		}
+	}
+	delete(s.servers, st.server.GetId())
+}
+
+// newNIC creates a NIC in the given network for the given server
+func (s *IaaSServer) newNIC(networkID, serverID string) *iaas.NIC {
+	s.nextIP++
+	id := uuid.NewString()
+	nic := &iaas.NIC{
+		Id:        &id,
+		NetworkId: &networkID,
+		Device:    &serverID,
+		Ipv4:      new(fmt.Sprintf("10.0.%d.%d", s.nextIP/250, s.nextIP%250+2)),
+		Status:    new("ACTIVE"),
+		Type:      new("server"),
+	}
+	s.nics[id] = nic
+	return nic
+}
+
+// parseLabelSelector parses a selector in the format "k1=v1,k2=v2"
+func parseLabelSelector(selector string) (map[string]string, error) {
+	result := make(map[string]string)
+	if selector == "" {
+		return result, nil
+	}
+	for term := range strings.SplitSeq(selector, ",") {
+		k, v, ok := strings.Cut(term, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid label selector %q", selector)
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+// matchLabels returns true if all selector terms are present in labels
+func matchLabels(labels map[string]any, selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := labels[k].(string); !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeResource writes the resource stored under key, or a 404 error naming the kind of resource
+func writeResource[T any](w http.ResponseWriter, resources map[string]T, kind, key string) {
+	resource, ok := resources[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %q not found", kind, key))
+		return
+	}
+	writeJSON(w, http.StatusOK, resource)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, iaas.Error{Code: int64(statusCode), Msg: message})
+}
@@ -9,14 +9,38 @@ import (
 )
 
 // StackitClient is a mock implementation of StackitClient for testing
-// Note: Single-tenant design - each client is bound to one set of credentials
 type StackitClient struct {
-	CreateServerFunc func(ctx context.Context, projectID, region string, req *client.CreateServerRequest) (*client.Server, error)
-	GetServerFunc    func(ctx context.Context, projectID, region, serverID string) (*client.Server, error)
-	DeleteServerFunc func(ctx context.Context, projectID, region, serverID string) error
-	ListServersFunc  func(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*client.Server, error)
-	GetNICsFunc      func(ctx context.Context, projectID, region, serverID string) ([]*client.NIC, error)
-	UpdateNICFunc    func(ctx context.Context, projectID, region, networkID, nicID string, allowedAddresses []string) (*client.NIC, error)
+	CreateServerFunc    func(ctx context.Context, projectID, region string, req *client.CreateServerRequest) (*client.Server, error)
+	GetServerFunc       func(ctx context.Context, projectID, region, serverID string) (*client.Server, error)
+	DeleteServerFunc    func(ctx context.Context, projectID, region, serverID string) error
+	StopServerFunc      func(ctx context.Context, projectID, region, serverID string) error
+	ListServersFunc     func(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*client.Server, error)
+	GetNICsFunc         func(ctx context.Context, projectID, region, serverID string) ([]*client.NIC, error)
+	UpdateNICFunc       func(ctx context.Context, projectID, region, networkID, nicID string, allowedAddresses []string) (*client.NIC, error)
+	UpdateNICLabelsFunc func(ctx context.Context, projectID, region, networkID, nicID string, labels map[string]string) (*client.NIC, error)
+	AttachVolumeFunc    func(ctx context.Context, projectID, region, serverID, volumeID string) error
+	CreateVolumeFunc    func(ctx context.Context, projectID, region string, req *client.CreateVolumeRequest) (*client.Volume, error)
+	GetVolumeFunc       func(ctx context.Context, projectID, region, volumeID string) (*client.Volume, error)
+	ListVolumesFunc     func(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*client.Volume, error)
+	UpdateVolumeFunc    func(ctx context.Context, projectID, region, volumeID string, req *client.UpdateVolumeRequest) (*client.Volume, error)
+	DeleteVolumeFunc    func(ctx context.Context, projectID, region, volumeID string) error
+
+	CreatePublicIPFunc func(ctx context.Context, projectID, region string, req *client.CreatePublicIPRequest) (*client.PublicIP, error)
+	ListPublicIPsFunc  func(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*client.PublicIP, error)
+	GetPublicIPFunc    func(ctx context.Context, projectID, region, publicIPID string) (*client.PublicIP, error)
+	UpdatePublicIPFunc func(ctx context.Context, projectID, region, publicIPID string, req *client.UpdatePublicIPRequest) (*client.PublicIP, error)
+	DeletePublicIPFunc func(ctx context.Context, projectID, region, publicIPID string) error
+	ListImagesFunc     func(ctx context.Context, projectID, region string) ([]*client.Image, error)
+
+	GetImageFunc         func(ctx context.Context, projectID, region, imageID string) (*client.Image, error)
+	GetMachineTypeFunc   func(ctx context.Context, projectID, region, machineType string) (*client.MachineType, error)
+	GetNetworkFunc       func(ctx context.Context, projectID, region, networkID string) (*client.Network, error)
+	GetNICFunc           func(ctx context.Context, projectID, region, nicID string) (*client.NIC, error)
+	ListNICsFunc         func(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*client.NIC, error)
+	DeleteNICFunc        func(ctx context.Context, projectID, region, networkID, nicID string) error
+	GetSecurityGroupFunc func(ctx context.Context, projectID, region, securityGroupID string) (*client.SecurityGroup, error)
+	GetAffinityGroupFunc func(ctx context.Context, projectID, region, affinityGroupID string) (*client.AffinityGroup, error)
+	GetKeypairFunc       func(ctx context.Context, keypairName string) (*client.Keypair, error)
 }
 
 func (m *StackitClient) CreateServer(ctx context.Context, projectID, region string, req *client.CreateServerRequest) (*client.Server, error) {
@@ -38,6 +62,7 @@ func (m *StackitClient) GetServer(ctx context.Context, projectID, region, server
 		ID:     serverID,
 		Name:   "test-machine",
 		Status: "ACTIVE",
+		Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"},
 	}, nil
 }
 
@@ -48,6 +73,13 @@ func (m *StackitClient) DeleteServer(ctx context.Context, projectID, region, ser
 	return nil
 }
 
+func (m *StackitClient) StopServer(ctx context.Context, projectID, region, serverID string) error {
+	if m.StopServerFunc != nil {
+		return m.StopServerFunc(ctx, projectID, region, serverID)
+	}
+	return nil
+}
+
 func (m *StackitClient) ListServers(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*client.Server, error) {
 	if m.ListServersFunc != nil {
 		return m.ListServersFunc(ctx, projectID, region, labelSelector)
@@ -71,7 +103,203 @@ func (m *StackitClient) UpdateNIC(ctx context.Context, projectID, region, networ
 	return &client.NIC{}, nil
 }
 
-// UpdateNIC updates a network interface
+func (m *StackitClient) UpdateNICLabels(ctx context.Context, projectID, region, networkID, nicID string, labels map[string]string) (*client.NIC, error) {
+	if m.UpdateNICLabelsFunc != nil {
+		return m.UpdateNICLabelsFunc(ctx, projectID, region, networkID, nicID, labels)
+	}
+	return &client.NIC{ID: nicID, NetworkID: networkID, Labels: labels}, nil
+}
+
+func (m *StackitClient) AttachVolume(ctx context.Context, projectID, region, serverID, volumeID string) error {
+	if m.AttachVolumeFunc != nil {
+		return m.AttachVolumeFunc(ctx, projectID, region, serverID, volumeID)
+	}
+	return nil
+}
+
+func (m *StackitClient) CreateVolume(ctx context.Context, projectID, region string, req *client.CreateVolumeRequest) (*client.Volume, error) {
+	if m.CreateVolumeFunc != nil {
+		return m.CreateVolumeFunc(ctx, projectID, region, req)
+	}
+	return &client.Volume{
+		ID:               "660e8400-e29b-41d4-a716-446655440000",
+		Name:             req.Name,
+		Status:           "AVAILABLE",
+		AvailabilityZone: req.AvailabilityZone,
+		Size:             req.Size,
+		PerformanceClass: req.PerformanceClass,
+		Labels:           req.Labels,
+	}, nil
+}
+
+func (m *StackitClient) GetVolume(ctx context.Context, projectID, region, volumeID string) (*client.Volume, error) {
+	if m.GetVolumeFunc != nil {
+		return m.GetVolumeFunc(ctx, projectID, region, volumeID)
+	}
+	return &client.Volume{
+		ID:     volumeID,
+		Status: "AVAILABLE",
+	}, nil
+}
+
+func (m *StackitClient) UpdateVolume(ctx context.Context, projectID, region, volumeID string, req *client.UpdateVolumeRequest) (*client.Volume, error) {
+	if m.UpdateVolumeFunc != nil {
+		return m.UpdateVolumeFunc(ctx, projectID, region, volumeID, req)
+	}
+	return &client.Volume{ID: volumeID, Labels: req.Labels}, nil
+}
+
+func (m *StackitClient) ListVolumes(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*client.Volume, error) {
+	if m.ListVolumesFunc != nil {
+		return m.ListVolumesFunc(ctx, projectID, region, labelSelector)
+	}
+	return []*client.Volume{}, nil
+}
+
+func (m *StackitClient) DeleteVolume(ctx context.Context, projectID, region, volumeID string) error {
+	if m.DeleteVolumeFunc != nil {
+		return m.DeleteVolumeFunc(ctx, projectID, region, volumeID)
+	}
+	return nil
+}
+
+func (m *StackitClient) CreatePublicIP(ctx context.Context, projectID, region string, req *client.CreatePublicIPRequest) (*client.PublicIP, error) {
+	if m.CreatePublicIPFunc != nil {
+		return m.CreatePublicIPFunc(ctx, projectID, region, req)
+	}
+	return &client.PublicIP{
+		ID:     "aa0e8400-e29b-41d4-a716-446655440000",
+		IP:     "192.0.2.10",
+		Labels: req.Labels,
+		NICID:  req.NICID,
+	}, nil
+}
+
+func (m *StackitClient) ListPublicIPs(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*client.PublicIP, error) {
+	if m.ListPublicIPsFunc != nil {
+		return m.ListPublicIPsFunc(ctx, projectID, region, labelSelector)
+	}
+	return []*client.PublicIP{}, nil
+}
+
+func (m *StackitClient) GetPublicIP(ctx context.Context, projectID, region, publicIPID string) (*client.PublicIP, error) {
+	if m.GetPublicIPFunc != nil {
+		return m.GetPublicIPFunc(ctx, projectID, region, publicIPID)
+	}
+	return &client.PublicIP{ID: publicIPID, IP: "192.0.2.10"}, nil
+}
+
+func (m *StackitClient) UpdatePublicIP(ctx context.Context, projectID, region, publicIPID string, req *client.UpdatePublicIPRequest) (*client.PublicIP, error) {
+	if m.UpdatePublicIPFunc != nil {
+		return m.UpdatePublicIPFunc(ctx, projectID, region, publicIPID, req)
+	}
+	publicIP := &client.PublicIP{ID: publicIPID, IP: "192.0.2.10", Labels: req.Labels}
+	if req.NICID != nil {
+		publicIP.NICID = *req.NICID
+	}
+	return publicIP, nil
+}
+
+func (m *StackitClient) DeletePublicIP(ctx context.Context, projectID, region, publicIPID string) error {
+	if m.DeletePublicIPFunc != nil {
+		return m.DeletePublicIPFunc(ctx, projectID, region, publicIPID)
+	}
+	return nil
+}
+
+func (m *StackitClient) ListImages(ctx context.Context, projectID, region string) ([]*client.Image, error) {
+	if m.ListImagesFunc != nil {
+		return m.ListImagesFunc(ctx, projectID, region)
+	}
+	return []*client.Image{}, nil
+}
+
+func (m *StackitClient) GetImage(ctx context.Context, projectID, region, imageID string) (*client.Image, error) {
+	if m.GetImageFunc != nil {
+		return m.GetImageFunc(ctx, projectID, region, imageID)
+	}
+	return &client.Image{
+		ID:     imageID,
+		Name:   "ubuntu-22.04",
+		Status: "AVAILABLE",
+	}, nil
+}
+
+func (m *StackitClient) GetMachineType(ctx context.Context, projectID, region, machineType string) (*client.MachineType, error) {
+	if m.GetMachineTypeFunc != nil {
+		return m.GetMachineTypeFunc(ctx, projectID, region, machineType)
+	}
+	return &client.MachineType{
+		Name:  machineType,
+		VCPUs: 2,
+		RAM:   4096,
+	}, nil
+}
+
+func (m *StackitClient) GetNetwork(ctx context.Context, projectID, region, networkID string) (*client.Network, error) {
+	if m.GetNetworkFunc != nil {
+		return m.GetNetworkFunc(ctx, projectID, region, networkID)
+	}
+	return &client.Network{
+		ID:     networkID,
+		Name:   "test-network",
+		Status: "CREATED",
+	}, nil
+}
+
+func (m *StackitClient) GetNIC(ctx context.Context, projectID, region, nicID string) (*client.NIC, error) {
+	if m.GetNICFunc != nil {
+		return m.GetNICFunc(ctx, projectID, region, nicID)
+	}
+	return &client.NIC{
+		ID:        nicID,
+		NetworkID: "770e8400-e29b-41d4-a716-446655440000",
+	}, nil
+}
+
+func (m *StackitClient) ListNICs(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*client.NIC, error) {
+	if m.ListNICsFunc != nil {
+		return m.ListNICsFunc(ctx, projectID, region, labelSelector)
+	}
+	return []*client.NIC{}, nil
+}
+
+func (m *StackitClient) DeleteNIC(ctx context.Context, projectID, region, networkID, nicID string) error {
+	if m.DeleteNICFunc != nil {
+		return m.DeleteNICFunc(ctx, projectID, region, networkID, nicID)
+	}
+	return nil
+}
+
+func (m *StackitClient) GetSecurityGroup(ctx context.Context, projectID, region, securityGroupID string) (*client.SecurityGroup, error) {
+	if m.GetSecurityGroupFunc != nil {
+		return m.GetSecurityGroupFunc(ctx, projectID, region, securityGroupID)
+	}
+	return &client.SecurityGroup{
+		ID:   securityGroupID,
+		Name: "test-security-group",
+	}, nil
+}
+
+func (m *StackitClient) GetAffinityGroup(ctx context.Context, projectID, region, affinityGroupID string) (*client.AffinityGroup, error) {
+	if m.GetAffinityGroupFunc != nil {
+		return m.GetAffinityGroupFunc(ctx, projectID, region, affinityGroupID)
+	}
+	return &client.AffinityGroup{
+		ID:     affinityGroupID,
+		Name:   "test-affinity-group",
+		Policy: "soft-anti-affinity",
+	}, nil
+}
+
+func (m *StackitClient) GetKeypair(ctx context.Context, keypairName string) (*client.Keypair, error) {
+	if m.GetKeypairFunc != nil {
+		return m.GetKeypairFunc(ctx, keypairName)
+	}
+	return &client.Keypair{
+		Name: keypairName,
+	}, nil
+}
 
 // encodeProviderSpec is a helper function to encode ProviderSpec for tests
 func EncodeProviderSpec(spec *api.ProviderSpec) ([]byte, error) {
@@ -0,0 +1,391 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// RetryConfig configures the retry behavior of RetryingStackitClient
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts per call, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries, including delays requested via Retry-After
+	MaxBackoff time.Duration
+	// Jitter adds a random delay of up to Jitter*backoff to every retry
+	Jitter float64
+	// RetryMutating enables retries for mutating calls (CreateServer, DeleteServer, StopServer, UpdateNIC, UpdateNICLabels, AttachVolume, CreateVolume, UpdateVolume, DeleteVolume, CreatePublicIP, UpdatePublicIP, DeletePublicIP, DeleteNIC)
+	// Disabled by default since a retried create may end up with duplicate resources
+	RetryMutating bool
+}
+
+// DefaultRetryConfig returns the retry configuration used by the provider
+// Only idempotent calls are retried
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Jitter:         0.2,
+	}
+}
+
+// RetryingStackitClient wraps a StackitClient and retries transient failures
+//
+// Retries are done with bounded exponential backoff and jitter, a Retry-After
+// delay sent with 429 responses takes precedence over the computed backoff.
+// Only rate limited (429) and server errors (5xx) are retried.
+type RetryingStackitClient struct {
+	client StackitClient
+	config RetryConfig
+	// sleep waits for the given duration, replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryingStackitClient returns a StackitClient that retries calls to the given client
+func NewRetryingStackitClient(c StackitClient, config RetryConfig) *RetryingStackitClient {
+	return &RetryingStackitClient{
+		client: c,
+		config: config,
+		sleep:  sleepContext,
+	}
+}
+
+// CreateServer creates a server, retried only if RetryMutating is set
+func (r *RetryingStackitClient) CreateServer(ctx context.Context, projectID, region string, req *CreateServerRequest) (*Server, error) {
+	var server *Server
+	err := r.do(ctx, "CreateServer", true, func() (err error) {
+		server, err = r.client.CreateServer(ctx, projectID, region, req)
+		return err
+	})
+	return server, err
+}
+
+// GetServer retrieves a server, always retried
+func (r *RetryingStackitClient) GetServer(ctx context.Context, projectID, region, serverID string) (*Server, error) {
+	var server *Server
+	err := r.do(ctx, "GetServer", false, func() (err error) {
+		server, err = r.client.GetServer(ctx, projectID, region, serverID)
+		return err
+	})
+	return server, err
+}
+
+// DeleteServer deletes a server, retried only if RetryMutating is set
+func (r *RetryingStackitClient) DeleteServer(ctx context.Context, projectID, region, serverID string) error {
+	return r.do(ctx, "DeleteServer", true, func() error {
+		return r.client.DeleteServer(ctx, projectID, region, serverID)
+	})
+}
+
+// StopServer stops a server, retried only if RetryMutating is set
+func (r *RetryingStackitClient) StopServer(ctx context.Context, projectID, region, serverID string) error {
+	return r.do(ctx, "StopServer", true, func() error {
+		return r.client.StopServer(ctx, projectID, region, serverID)
+	})
+}
+
+// ListServers lists servers, always retried
+func (r *RetryingStackitClient) ListServers(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*Server, error) {
+	var servers []*Server
+	err := r.do(ctx, "ListServers", false, func() (err error) {
+		servers, err = r.client.ListServers(ctx, projectID, region, labelSelector)
+		return err
+	})
+	return servers, err
+}
+
+// GetNICsForServer retrieves the NICs of a server, always retried
+func (r *RetryingStackitClient) GetNICsForServer(ctx context.Context, projectID, region, serverID string) ([]*NIC, error) {
+	var nics []*NIC
+	err := r.do(ctx, "GetNICsForServer", false, func() (err error) {
+		nics, err = r.client.GetNICsForServer(ctx, projectID, region, serverID)
+		return err
+	})
+	return nics, err
+}
+
+// UpdateNIC updates a NIC, retried only if RetryMutating is set
+func (r *RetryingStackitClient) UpdateNIC(ctx context.Context, projectID, region, networkID, nicID string, allowedAddresses []string) (*NIC, error) {
+	var nic *NIC
+	err := r.do(ctx, "UpdateNIC", true, func() (err error) {
+		nic, err = r.client.UpdateNIC(ctx, projectID, region, networkID, nicID, allowedAddresses)
+		return err
+	})
+	return nic, err
+}
+
+// UpdateNICLabels replaces the labels of a NIC, retried only if RetryMutating is set
+func (r *RetryingStackitClient) UpdateNICLabels(ctx context.Context, projectID, region, networkID, nicID string, labels map[string]string) (*NIC, error) {
+	var nic *NIC
+	err := r.do(ctx, "UpdateNICLabels", true, func() (err error) {
+		nic, err = r.client.UpdateNICLabels(ctx, projectID, region, networkID, nicID, labels)
+		return err
+	})
+	return nic, err
+}
+
+// AttachVolume attaches a volume to a server, retried only if RetryMutating is set
+func (r *RetryingStackitClient) AttachVolume(ctx context.Context, projectID, region, serverID, volumeID string) error {
+	return r.do(ctx, "AttachVolume", true, func() error {
+		return r.client.AttachVolume(ctx, projectID, region, serverID, volumeID)
+	})
+}
+
+// CreateVolume creates a volume, retried only if RetryMutating is set
+func (r *RetryingStackitClient) CreateVolume(ctx context.Context, projectID, region string, req *CreateVolumeRequest) (*Volume, error) {
+	var volume *Volume
+	err := r.do(ctx, "CreateVolume", true, func() (err error) {
+		volume, err = r.client.CreateVolume(ctx, projectID, region, req)
+		return err
+	})
+	return volume, err
+}
+
+// GetVolume retrieves a volume, always retried
+func (r *RetryingStackitClient) GetVolume(ctx context.Context, projectID, region, volumeID string) (*Volume, error) {
+	var volume *Volume
+	err := r.do(ctx, "GetVolume", false, func() (err error) {
+		volume, err = r.client.GetVolume(ctx, projectID, region, volumeID)
+		return err
+	})
+	return volume, err
+}
+
+// ListVolumes lists volumes, always retried
+func (r *RetryingStackitClient) ListVolumes(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*Volume, error) {
+	var volumes []*Volume
+	err := r.do(ctx, "ListVolumes", false, func() (err error) {
+		volumes, err = r.client.ListVolumes(ctx, projectID, region, labelSelector)
+		return err
+	})
+	return volumes, err
+}
+
+// UpdateVolume updates a volume, retried only if RetryMutating is set
+func (r *RetryingStackitClient) UpdateVolume(ctx context.Context, projectID, region, volumeID string, req *UpdateVolumeRequest) (*Volume, error) {
+	var volume *Volume
+	err := r.do(ctx, "UpdateVolume", true, func() (err error) {
+		volume, err = r.client.UpdateVolume(ctx, projectID, region, volumeID, req)
+		return err
+	})
+	return volume, err
+}
+
+// DeleteVolume deletes a volume, retried only if RetryMutating is set
+func (r *RetryingStackitClient) DeleteVolume(ctx context.Context, projectID, region, volumeID string) error {
+	return r.do(ctx, "DeleteVolume", true, func() error {
+		return r.client.DeleteVolume(ctx, projectID, region, volumeID)
+	})
+}
+
+// CreatePublicIP allocates a public IP, retried only if RetryMutating is set
+func (r *RetryingStackitClient) CreatePublicIP(ctx context.Context, projectID, region string, req *CreatePublicIPRequest) (*PublicIP, error) {
+	var publicIP *PublicIP
+	err := r.do(ctx, "CreatePublicIP", true, func() (err error) {
+		publicIP, err = r.client.CreatePublicIP(ctx, projectID, region, req)
+		return err
+	})
+	return publicIP, err
+}
+
+// ListPublicIPs lists public IPs, always retried
+func (r *RetryingStackitClient) ListPublicIPs(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*PublicIP, error) {
+	var publicIPs []*PublicIP
+	err := r.do(ctx, "ListPublicIPs", false, func() (err error) {
+		publicIPs, err = r.client.ListPublicIPs(ctx, projectID, region, labelSelector)
+		return err
+	})
+	return publicIPs, err
+}
+
+// GetPublicIP retrieves a public IP, always retried
+func (r *RetryingStackitClient) GetPublicIP(ctx context.Context, projectID, region, publicIPID string) (*PublicIP, error) {
+	var publicIP *PublicIP
+	err := r.do(ctx, "GetPublicIP", false, func() (err error) {
+		publicIP, err = r.client.GetPublicIP(ctx, projectID, region, publicIPID)
+		return err
+	})
+	return publicIP, err
+}
+
+// UpdatePublicIP updates a public IP, retried only if RetryMutating is set
+func (r *RetryingStackitClient) UpdatePublicIP(ctx context.Context, projectID, region, publicIPID string, req *UpdatePublicIPRequest) (*PublicIP, error) {
+	var publicIP *PublicIP
+	err := r.do(ctx, "UpdatePublicIP", true, func() (err error) {
+		publicIP, err = r.client.UpdatePublicIP(ctx, projectID, region, publicIPID, req)
+		return err
+	})
+	return publicIP, err
+}
+
+// DeletePublicIP releases a public IP, retried only if RetryMutating is set
+func (r *RetryingStackitClient) DeletePublicIP(ctx context.Context, projectID, region, publicIPID string) error {
+	return r.do(ctx, "DeletePublicIP", true, func() error {
+		return r.client.DeletePublicIP(ctx, projectID, region, publicIPID)
+	})
+}
+
+// ListImages lists images, always retried
+func (r *RetryingStackitClient) ListImages(ctx context.Context, projectID, region string) ([]*Image, error) {
+	var images []*Image
+	err := r.do(ctx, "ListImages", false, func() (err error) {
+		images, err = r.client.ListImages(ctx, projectID, region)
+		return err
+	})
+	return images, err
+}
+
+// GetImage retrieves an image, always retried
+func (r *RetryingStackitClient) GetImage(ctx context.Context, projectID, region, imageID string) (*Image, error) {
+	var image *Image
+	err := r.do(ctx, "GetImage", false, func() (err error) {
+		image, err = r.client.GetImage(ctx, projectID, region, imageID)
+		return err
+	})
+	return image, err
+}
+
+// GetMachineType retrieves a machine type, always retried
+func (r *RetryingStackitClient) GetMachineType(ctx context.Context, projectID, region, machineType string) (*MachineType, error) {
+	var mt *MachineType
+	err := r.do(ctx, "GetMachineType", false, func() (err error) {
+		mt, err = r.client.GetMachineType(ctx, projectID, region, machineType)
+		return err
+	})
+	return mt, err
+}
+
+// GetNetwork retrieves a network, always retried
+func (r *RetryingStackitClient) GetNetwork(ctx context.Context, projectID, region, networkID string) (*Network, error) {
+	var network *Network
+	err := r.do(ctx, "GetNetwork", false, func() (err error) {
+		network, err = r.client.GetNetwork(ctx, projectID, region, networkID)
+		return err
+	})
+	return network, err
+}
+
+// GetNIC retrieves a network interface, always retried
+func (r *RetryingStackitClient) GetNIC(ctx context.Context, projectID, region, nicID string) (*NIC, error) {
+	var nic *NIC
+	err := r.do(ctx, "GetNIC", false, func() (err error) {
+		nic, err = r.client.GetNIC(ctx, projectID, region, nicID)
+		return err
+	})
+	return nic, err
+}
+
+// ListNICs lists network interfaces, always retried
+func (r *RetryingStackitClient) ListNICs(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*NIC, error) {
+	var nics []*NIC
+	err := r.do(ctx, "ListNICs", false, func() (err error) {
+		nics, err = r.client.ListNICs(ctx, projectID, region, labelSelector)
+		return err
+	})
+	return nics, err
+}
+
+// DeleteNIC deletes a network interface, retried only if RetryMutating is set
+func (r *RetryingStackitClient) DeleteNIC(ctx context.Context, projectID, region, networkID, nicID string) error {
+	return r.do(ctx, "DeleteNIC", true, func() error {
+		return r.client.DeleteNIC(ctx, projectID, region, networkID, nicID)
+	})
+}
+
+// GetSecurityGroup retrieves a security group, always retried
+func (r *RetryingStackitClient) GetSecurityGroup(ctx context.Context, projectID, region, securityGroupID string) (*SecurityGroup, error) {
+	var securityGroup *SecurityGroup
+	err := r.do(ctx, "GetSecurityGroup", false, func() (err error) {
+		securityGroup, err = r.client.GetSecurityGroup(ctx, projectID, region, securityGroupID)
+		return err
+	})
+	return securityGroup, err
+}
+
+// GetAffinityGroup retrieves an affinity group, always retried
+func (r *RetryingStackitClient) GetAffinityGroup(ctx context.Context, projectID, region, affinityGroupID string) (*AffinityGroup, error) {
+	var affinityGroup *AffinityGroup
+	err := r.do(ctx, "GetAffinityGroup", false, func() (err error) {
+		affinityGroup, err = r.client.GetAffinityGroup(ctx, projectID, region, affinityGroupID)
+		return err
+	})
+	return affinityGroup, err
+}
+
+// GetKeypair retrieves an SSH keypair, always retried
+func (r *RetryingStackitClient) GetKeypair(ctx context.Context, keypairName string) (*Keypair, error) {
+	var keypair *Keypair
+	err := r.do(ctx, "GetKeypair", false, func() (err error) {
+		keypair, err = r.client.GetKeypair(ctx, keypairName)
+		return err
+	})
+	return keypair, err
+}
+
+// do calls fn until it succeeds, fails with a non-retryable error or the attempts are used up
+func (r *RetryingStackitClient) do(ctx context.Context, operation string, mutating bool, fn func() error) error {
+	attempts := r.config.MaxAttempts
+	if mutating && !r.config.RetryMutating {
+		attempts = 1
+	}
+
+	backoff := wait.Backoff{
+		Duration: r.config.InitialBackoff,
+		Factor:   2,
+		Jitter:   r.config.Jitter,
+		Steps:    attempts,
+		Cap:      r.config.MaxBackoff,
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= attempts || !isRetryable(err) {
+			return err
+		}
+
+		delay := backoff.Step()
+		if retryAfter := retryAfter(err); retryAfter > delay {
+			delay = min(retryAfter, r.config.MaxBackoff)
+		}
+
+		klog.V(3).Infof("STACKIT %s failed (attempt %d/%d), retrying in %s: %v", operation, attempt, attempts, delay, err)
+		if sleepErr := r.sleep(ctx, delay); sleepErr != nil {
+			// context is done, return the last API error rather than the context error
+			return err
+		}
+	}
+}
+
+// isRetryable returns true for errors that are expected to go away on their own
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServerError)
+}
+
+// retryAfter returns the Retry-After delay of an API error, zero if not set
+func retryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// sleepContext waits for the given duration or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
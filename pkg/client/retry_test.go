@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeClient is a minimal StackitClient that returns queued errors
+// The mock package cannot be used here since it imports this package
+type fakeClient struct {
+	StackitClient
+	errs  []error
+	calls int
+}
+
+func (f *fakeClient) next() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeClient) GetServer(_ context.Context, _, _, serverID string) (*Server, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &Server{ID: serverID}, nil
+}
+
+func (f *fakeClient) CreateServer(_ context.Context, _, _ string, req *CreateServerRequest) (*Server, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &Server{Name: req.Name}, nil
+}
+
+var _ = Describe("RetryingStackitClient", func() {
+	var (
+		ctx    context.Context
+		fake   *fakeClient
+		retry  *RetryingStackitClient
+		sleeps []time.Duration
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = &fakeClient{}
+		sleeps = nil
+		retry = NewRetryingStackitClient(fake, RetryConfig{
+			MaxAttempts:    3,
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     time.Second,
+		})
+		retry.sleep = func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		}
+	})
+
+	It("should retry idempotent calls on server errors with exponential backoff", func() {
+		fake.errs = []error{
+			&APIError{Class: ErrServerError, StatusCode: 503},
+			&APIError{Class: ErrServerError, StatusCode: 502},
+		}
+
+		server, err := retry.GetServer(ctx, "project", "eu01", "server-1")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.ID).To(Equal("server-1"))
+		Expect(fake.calls).To(Equal(3))
+		Expect(sleeps).To(Equal([]time.Duration{100 * time.Millisecond, 200 * time.Millisecond}))
+	})
+
+	It("should give up after MaxAttempts and return the last error", func() {
+		fake.errs = []error{
+			&APIError{Class: ErrServerError, StatusCode: 500},
+			&APIError{Class: ErrServerError, StatusCode: 500},
+			&APIError{Class: ErrRateLimited, StatusCode: 429},
+			nil,
+		}
+
+		_, err := retry.GetServer(ctx, "project", "eu01", "server-1")
+
+		Expect(err).To(MatchError(ErrRateLimited))
+		Expect(fake.calls).To(Equal(3))
+	})
+
+	It("should honour Retry-After on rate limited responses", func() {
+		fake.errs = []error{
+			&APIError{Class: ErrRateLimited, StatusCode: 429, RetryAfter: 700 * time.Millisecond},
+		}
+
+		_, err := retry.GetServer(ctx, "project", "eu01", "server-1")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sleeps).To(Equal([]time.Duration{700 * time.Millisecond}))
+	})
+
+	It("should cap Retry-After at MaxBackoff", func() {
+		fake.errs = []error{
+			&APIError{Class: ErrRateLimited, StatusCode: 429, RetryAfter: time.Minute},
+		}
+
+		_, err := retry.GetServer(ctx, "project", "eu01", "server-1")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sleeps).To(Equal([]time.Duration{time.Second}))
+	})
+
+	It("should not retry non-transient errors", func() {
+		fake.errs = []error{&APIError{Class: ErrNotFound, StatusCode: 404}}
+
+		_, err := retry.GetServer(ctx, "project", "eu01", "server-1")
+
+		Expect(err).To(MatchError(ErrNotFound))
+		Expect(fake.calls).To(Equal(1))
+	})
+
+	It("should not retry mutating calls by default", func() {
+		fake.errs = []error{&APIError{Class: ErrServerError, StatusCode: 500}}
+
+		_, err := retry.CreateServer(ctx, "project", "eu01", &CreateServerRequest{Name: "machine"})
+
+		Expect(err).To(MatchError(ErrServerError))
+		Expect(fake.calls).To(Equal(1))
+	})
+
+	It("should retry mutating calls when enabled", func() {
+		retry.config.RetryMutating = true
+		fake.errs = []error{&APIError{Class: ErrServerError, StatusCode: 500}}
+
+		server, err := retry.CreateServer(ctx, "project", "eu01", &CreateServerRequest{Name: "machine"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Name).To(Equal("machine"))
+		Expect(fake.calls).To(Equal(2))
+	})
+
+	It("should stop retrying when the context is done", func() {
+		retry.sleep = sleepContext
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		fake.errs = []error{
+			&APIError{Class: ErrServerError, StatusCode: 500},
+			&APIError{Class: ErrServerError, StatusCode: 500},
+		}
+
+		_, err := retry.GetServer(cancelCtx, "project", "eu01", "server-1")
+
+		Expect(errors.Is(err, ErrServerError)).To(BeTrue())
+		Expect(fake.calls).To(Equal(1))
+	})
+})
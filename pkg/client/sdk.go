@@ -2,19 +2,19 @@ package client
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
 	"github.com/stackitcloud/stackit-sdk-go/core/config"
-	"github.com/stackitcloud/stackit-sdk-go/core/oapierror"
+	"github.com/stackitcloud/stackit-sdk-go/core/runtime"
 	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
 )
 
 // SdkStackitClient is an SDK implementation of StackitClient
-// Each instance handles a single STACKIT project (single-tenant design)
-// The IaaS client is created once and reused across all requests
+// Each instance handles a single service account key
+// The IaaS client is created once and reused across all requests with that key
 // The SDK automatically handles token refresh and re-authentication
 type SdkStackitClient struct {
 	iaasClient *iaas.APIClient
@@ -34,11 +34,6 @@ func NewStackitClient(serviceAccountKey string) (*SdkStackitClient, error) {
 	}, nil
 }
 
-var (
-	// ErrServerNotFound indicates the server was not found (404)
-	ErrServerNotFound = errors.New("server not found")
-)
-
 // createIAASClient creates a new STACKIT SDK IAAS API client
 //
 // Authentication: Uses ServiceAccount Key Flow (recommended by STACKIT)
@@ -198,11 +193,12 @@ func (c *SdkStackitClient) CreateServer(ctx context.Context, projectID, region s
 	}
 
 	// Call SDK using the stored client
+	ctx, resp := captureResponse(ctx)
 	sdkServer, err := c.iaasClient.DefaultAPI.CreateServer(ctx, projectID, region).
 		CreateServerPayload(*payload).
 		Execute()
 	if err != nil {
-		return nil, fmt.Errorf("SDK CreateServer failed: %w", err)
+		return nil, fmt.Errorf("SDK CreateServer failed: %w", classifyError(err, *resp))
 	}
 
 	// Convert SDK server to our Server type
@@ -213,13 +209,12 @@ func (c *SdkStackitClient) CreateServer(ctx context.Context, projectID, region s
 
 // GetServer retrieves a server by ID via STACKIT SDK
 func (c *SdkStackitClient) GetServer(ctx context.Context, projectID, region, serverID string) (*Server, error) {
-	sdkServer, err := c.iaasClient.DefaultAPI.GetServer(ctx, projectID, region, serverID).Execute()
+	ctx, resp := captureResponse(ctx)
+	// details are required for the API to return the NICs of the server
+	sdkServer, err := c.iaasClient.DefaultAPI.GetServer(ctx, projectID, region, serverID).Details(true).Execute()
 	if err != nil {
-		// Check if error is 404 Not Found
-		if isNotFoundError(err) {
-			return nil, fmt.Errorf("%w: %v", ErrServerNotFound, err)
-		}
-		return nil, fmt.Errorf("SDK GetServer failed: %w", err)
+		// 404 Not Found is classified as ErrNotFound
+		return nil, fmt.Errorf("SDK GetServer failed: %w", classifyError(err, *resp))
 	}
 
 	// Convert SDK server to our Server type
@@ -230,13 +225,23 @@ func (c *SdkStackitClient) GetServer(ctx context.Context, projectID, region, ser
 
 // DeleteServer deletes a server by ID via STACKIT SDK
 func (c *SdkStackitClient) DeleteServer(ctx context.Context, projectID, region, serverID string) error {
+	ctx, resp := captureResponse(ctx)
 	err := c.iaasClient.DefaultAPI.DeleteServer(ctx, projectID, region, serverID).Execute()
 	if err != nil {
-		// Check if error is 404 Not Found - this is OK (idempotent)
-		if isNotFoundError(err) {
-			return fmt.Errorf("%w: %v", ErrServerNotFound, err)
-		}
-		return fmt.Errorf("SDK DeleteServer failed: %w", err)
+		// 404 Not Found is classified as ErrNotFound, callers treat it as success (idempotent)
+		return fmt.Errorf("SDK DeleteServer failed: %w", classifyError(err, *resp))
+	}
+
+	return nil
+}
+
+// StopServer stops a server by ID via STACKIT SDK
+func (c *SdkStackitClient) StopServer(ctx context.Context, projectID, region, serverID string) error {
+	ctx, resp := captureResponse(ctx)
+	err := c.iaasClient.DefaultAPI.StopServer(ctx, projectID, region, serverID).Execute()
+	if err != nil {
+		// 409 Conflict is classified as ErrConflict, the server is in a state that cannot be stopped
+		return fmt.Errorf("SDK StopServer failed: %w", classifyError(err, *resp))
 	}
 
 	return nil
@@ -244,27 +249,16 @@ func (c *SdkStackitClient) DeleteServer(ctx context.Context, projectID, region,
 
 // ListServers lists all servers in a project via STACKIT SDK
 func (c *SdkStackitClient) ListServers(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*Server, error) {
-	serverRequest := c.iaasClient.DefaultAPI.ListServers(ctx, projectID, region)
+	ctx, resp := captureResponse(ctx)
+	serverRequest := c.iaasClient.DefaultAPI.ListServers(ctx, projectID, region).Details(true)
 
 	if labelSelector != nil {
-		sb := strings.Builder{}
-		for k, v := range labelSelector {
-			// prevents trailing comma at the end
-			if sb.Len() > 0 {
-				sb.WriteString(",")
-			}
-			_, err := fmt.Fprintf(&sb, "%s=%s", k, v)
-			if err != nil {
-				return nil, fmt.Errorf("failed to format label selector: %w", err)
-			}
-		}
-
-		serverRequest = serverRequest.LabelSelector(sb.String())
+		serverRequest = serverRequest.LabelSelector(formatLabelSelector(labelSelector))
 	}
 
 	sdkResponse, err := serverRequest.Execute()
 	if err != nil {
-		return nil, fmt.Errorf("SDK ListServers failed: %w", err)
+		return nil, fmt.Errorf("SDK ListServers failed: %w", classifyError(err, *resp))
 	}
 
 	// Convert SDK servers to our Server type
@@ -280,9 +274,10 @@ func (c *SdkStackitClient) ListServers(ctx context.Context, projectID, region st
 }
 
 func (c *SdkStackitClient) GetNICsForServer(ctx context.Context, projectID, region, serverID string) ([]*NIC, error) {
+	ctx, resp := captureResponse(ctx)
 	res, err := c.iaasClient.DefaultAPI.ListServerNICs(ctx, projectID, region, serverID).Execute()
 	if err != nil {
-		return nil, fmt.Errorf("SDK ListServerNICs failed: %w", err)
+		return nil, fmt.Errorf("SDK ListServerNICs failed: %w", classifyError(err, *resp))
 	}
 
 	nics := make([]*NIC, 0)
@@ -306,9 +301,10 @@ func (c *SdkStackitClient) UpdateNIC(ctx context.Context, projectID, region, net
 		AllowedAddresses: addresses,
 	}
 
+	ctx, resp := captureResponse(ctx)
 	sdkNic, err := c.iaasClient.DefaultAPI.UpdateNic(ctx, projectID, region, networkID, nicID).UpdateNicPayload(payload).Execute()
 	if err != nil {
-		return nil, fmt.Errorf("SDK UpdateNic failed: %w", err)
+		return nil, fmt.Errorf("SDK UpdateNic failed: %w", classifyError(err, *resp))
 	}
 
 	if sdkNic == nil {
@@ -318,8 +314,404 @@ func (c *SdkStackitClient) UpdateNIC(ctx context.Context, projectID, region, net
 	return convertSDKNICtoNIC(sdkNic), nil
 }
 
+// UpdateNICLabels replaces the labels of a network interface via STACKIT SDK
+func (c *SdkStackitClient) UpdateNICLabels(ctx context.Context, projectID, region, networkID, nicID string, labels map[string]string) (*NIC, error) {
+	payload := iaas.NewUpdateNicPayload()
+	payload.SetLabels(convertLabelsToSDK(labels))
+
+	ctx, resp := captureResponse(ctx)
+	sdkNic, err := c.iaasClient.DefaultAPI.UpdateNic(ctx, projectID, region, networkID, nicID).UpdateNicPayload(*payload).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK UpdateNic failed: %w", classifyError(err, *resp))
+	}
+
+	return convertSDKNICtoNIC(sdkNic), nil
+}
+
+// AttachVolume attaches an existing volume to a server via STACKIT SDK
+func (c *SdkStackitClient) AttachVolume(ctx context.Context, projectID, region, serverID, volumeID string) error {
+	ctx, resp := captureResponse(ctx)
+	_, err := c.iaasClient.DefaultAPI.AddVolumeToServer(ctx, projectID, region, serverID, volumeID).Execute()
+	if err != nil {
+		return fmt.Errorf("SDK AddVolumeToServer failed: %w", classifyError(err, *resp))
+	}
+
+	return nil
+}
+
+// CreateVolume creates a block storage volume via STACKIT SDK
+func (c *SdkStackitClient) CreateVolume(ctx context.Context, projectID, region string, req *CreateVolumeRequest) (*Volume, error) {
+	payload := iaas.NewCreateVolumePayload(req.AvailabilityZone)
+	if req.Name != "" {
+		payload.SetName(req.Name)
+	}
+	if req.Size > 0 {
+		payload.SetSize(int64(req.Size))
+	}
+	if req.PerformanceClass != "" {
+		payload.SetPerformanceClass(req.PerformanceClass)
+	}
+	if req.Source != nil {
+		payload.SetSource(*iaas.NewVolumeSource(req.Source.ID, req.Source.Type))
+	}
+	if req.Labels != nil {
+		payload.SetLabels(convertLabelsToSDK(req.Labels))
+	}
+
+	ctx, resp := captureResponse(ctx)
+	sdkVolume, err := c.iaasClient.DefaultAPI.CreateVolume(ctx, projectID, region).CreateVolumePayload(*payload).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK CreateVolume failed: %w", classifyError(err, *resp))
+	}
+
+	return convertSDKVolumeToVolume(sdkVolume), nil
+}
+
+// GetVolume retrieves a volume by ID via STACKIT SDK
+func (c *SdkStackitClient) GetVolume(ctx context.Context, projectID, region, volumeID string) (*Volume, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkVolume, err := c.iaasClient.DefaultAPI.GetVolume(ctx, projectID, region, volumeID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK GetVolume failed: %w", classifyError(err, *resp))
+	}
+
+	return convertSDKVolumeToVolume(sdkVolume), nil
+}
+
+// UpdateVolume updates the labels of a volume via STACKIT SDK
+func (c *SdkStackitClient) UpdateVolume(ctx context.Context, projectID, region, volumeID string, req *UpdateVolumeRequest) (*Volume, error) {
+	payload := iaas.NewUpdateVolumePayload()
+	if req.Labels != nil {
+		payload.SetLabels(convertLabelsToSDK(req.Labels))
+	}
+
+	ctx, resp := captureResponse(ctx)
+	sdkVolume, err := c.iaasClient.DefaultAPI.UpdateVolume(ctx, projectID, region, volumeID).UpdateVolumePayload(*payload).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK UpdateVolume failed: %w", classifyError(err, *resp))
+	}
+
+	return convertSDKVolumeToVolume(sdkVolume), nil
+}
+
+// ListVolumes lists all volumes in a project via STACKIT SDK
+func (c *SdkStackitClient) ListVolumes(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*Volume, error) {
+	ctx, resp := captureResponse(ctx)
+	volumeRequest := c.iaasClient.DefaultAPI.ListVolumes(ctx, projectID, region)
+	if labelSelector != nil {
+		volumeRequest = volumeRequest.LabelSelector(formatLabelSelector(labelSelector))
+	}
+
+	sdkResponse, err := volumeRequest.Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK ListVolumes failed: %w", classifyError(err, *resp))
+	}
+
+	volumes := make([]*Volume, 0, len(sdkResponse.Items))
+	for i := range sdkResponse.Items {
+		volumes = append(volumes, convertSDKVolumeToVolume(&sdkResponse.Items[i]))
+	}
+
+	return volumes, nil
+}
+
+// DeleteVolume deletes a volume by ID via STACKIT SDK
+func (c *SdkStackitClient) DeleteVolume(ctx context.Context, projectID, region, volumeID string) error {
+	ctx, resp := captureResponse(ctx)
+	err := c.iaasClient.DefaultAPI.DeleteVolume(ctx, projectID, region, volumeID).Execute()
+	if err != nil {
+		// 404 Not Found is classified as ErrNotFound, callers treat it as success (idempotent)
+		return fmt.Errorf("SDK DeleteVolume failed: %w", classifyError(err, *resp))
+	}
+
+	return nil
+}
+
+// CreatePublicIP allocates a public IP via STACKIT SDK
+func (c *SdkStackitClient) CreatePublicIP(ctx context.Context, projectID, region string, req *CreatePublicIPRequest) (*PublicIP, error) {
+	payload := iaas.NewCreatePublicIPPayload()
+	if req.Labels != nil {
+		payload.SetLabels(convertLabelsToSDK(req.Labels))
+	}
+	if req.NICID != "" {
+		payload.SetNetworkInterface(req.NICID)
+	}
+
+	ctx, resp := captureResponse(ctx)
+	sdkPublicIP, err := c.iaasClient.DefaultAPI.CreatePublicIP(ctx, projectID, region).CreatePublicIPPayload(*payload).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK CreatePublicIP failed: %w", classifyError(err, *resp))
+	}
+
+	return convertSDKPublicIPToPublicIP(sdkPublicIP), nil
+}
+
+// ListPublicIPs lists all public IPs in a project via STACKIT SDK
+func (c *SdkStackitClient) ListPublicIPs(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*PublicIP, error) {
+	ctx, resp := captureResponse(ctx)
+	publicIPRequest := c.iaasClient.DefaultAPI.ListPublicIPs(ctx, projectID, region)
+	if labelSelector != nil {
+		publicIPRequest = publicIPRequest.LabelSelector(formatLabelSelector(labelSelector))
+	}
+
+	sdkResponse, err := publicIPRequest.Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK ListPublicIPs failed: %w", classifyError(err, *resp))
+	}
+
+	publicIPs := make([]*PublicIP, 0, len(sdkResponse.Items))
+	for i := range sdkResponse.Items {
+		publicIPs = append(publicIPs, convertSDKPublicIPToPublicIP(&sdkResponse.Items[i]))
+	}
+
+	return publicIPs, nil
+}
+
+// GetPublicIP retrieves a public IP by ID via STACKIT SDK
+func (c *SdkStackitClient) GetPublicIP(ctx context.Context, projectID, region, publicIPID string) (*PublicIP, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkPublicIP, err := c.iaasClient.DefaultAPI.GetPublicIP(ctx, projectID, region, publicIPID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK GetPublicIP failed: %w", classifyError(err, *resp))
+	}
+
+	return convertSDKPublicIPToPublicIP(sdkPublicIP), nil
+}
+
+// UpdatePublicIP updates the labels and the NIC association of a public IP via STACKIT SDK
+func (c *SdkStackitClient) UpdatePublicIP(ctx context.Context, projectID, region, publicIPID string, req *UpdatePublicIPRequest) (*PublicIP, error) {
+	payload := iaas.NewUpdatePublicIPPayload()
+	if req.Labels != nil {
+		payload.SetLabels(convertLabelsToSDK(req.Labels))
+	}
+	switch {
+	case req.DissociateNIC:
+		// the API dissociates the public IP on an explicit null
+		payload.SetNetworkInterfaceNil()
+	case req.NICID != nil:
+		payload.SetNetworkInterface(*req.NICID)
+	}
+
+	ctx, resp := captureResponse(ctx)
+	sdkPublicIP, err := c.iaasClient.DefaultAPI.UpdatePublicIP(ctx, projectID, region, publicIPID).UpdatePublicIPPayload(*payload).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK UpdatePublicIP failed: %w", classifyError(err, *resp))
+	}
+
+	return convertSDKPublicIPToPublicIP(sdkPublicIP), nil
+}
+
+// DeletePublicIP releases a public IP by ID via STACKIT SDK
+func (c *SdkStackitClient) DeletePublicIP(ctx context.Context, projectID, region, publicIPID string) error {
+	ctx, resp := captureResponse(ctx)
+	err := c.iaasClient.DefaultAPI.DeletePublicIP(ctx, projectID, region, publicIPID).Execute()
+	if err != nil {
+		// 404 Not Found is classified as ErrNotFound, callers treat it as success (idempotent)
+		return fmt.Errorf("SDK DeletePublicIP failed: %w", classifyError(err, *resp))
+	}
+
+	return nil
+}
+
+// ListImages lists all images available to a project via STACKIT SDK
+// Public images are included, the scope of an image tells them apart
+func (c *SdkStackitClient) ListImages(ctx context.Context, projectID, region string) ([]*Image, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkResponse, err := c.iaasClient.DefaultAPI.ListImages(ctx, projectID, region).All(true).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK ListImages failed: %w", classifyError(err, *resp))
+	}
+
+	images := make([]*Image, 0, len(sdkResponse.Items))
+	for i := range sdkResponse.Items {
+		images = append(images, convertSDKImageToImage(&sdkResponse.Items[i]))
+	}
+
+	return images, nil
+}
+
+// GetImage retrieves an image by ID via STACKIT SDK
+func (c *SdkStackitClient) GetImage(ctx context.Context, projectID, region, imageID string) (*Image, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkImage, err := c.iaasClient.DefaultAPI.GetImage(ctx, projectID, region, imageID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK GetImage failed: %w", classifyError(err, *resp))
+	}
+
+	return convertSDKImageToImage(sdkImage), nil
+}
+
+// GetMachineType retrieves a machine type by name via STACKIT SDK
+func (c *SdkStackitClient) GetMachineType(ctx context.Context, projectID, region, machineType string) (*MachineType, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkMachineType, err := c.iaasClient.DefaultAPI.GetMachineType(ctx, projectID, region, machineType).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK GetMachineType failed: %w", classifyError(err, *resp))
+	}
+
+	return &MachineType{
+		Name:  sdkMachineType.GetName(),
+		VCPUs: int(sdkMachineType.GetVcpus()),
+		RAM:   int(sdkMachineType.GetRam()),
+		Disk:  int(sdkMachineType.GetDisk()),
+	}, nil
+}
+
+// GetNetwork retrieves a network by ID via STACKIT SDK
+func (c *SdkStackitClient) GetNetwork(ctx context.Context, projectID, region, networkID string) (*Network, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkNetwork, err := c.iaasClient.DefaultAPI.GetNetwork(ctx, projectID, region, networkID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK GetNetwork failed: %w", classifyError(err, *resp))
+	}
+
+	return &Network{
+		ID:     sdkNetwork.GetId(),
+		Name:   sdkNetwork.GetName(),
+		Status: sdkNetwork.GetStatus(),
+	}, nil
+}
+
+// GetNIC retrieves a network interface of the project by ID via STACKIT SDK
+func (c *SdkStackitClient) GetNIC(ctx context.Context, projectID, region, nicID string) (*NIC, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkNIC, err := c.iaasClient.DefaultAPI.GetProjectNIC(ctx, projectID, region, nicID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK GetProjectNIC failed: %w", classifyError(err, *resp))
+	}
+
+	return convertSDKNICtoNIC(sdkNIC), nil
+}
+
+// ListNICs lists all network interfaces in a project via STACKIT SDK
+func (c *SdkStackitClient) ListNICs(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*NIC, error) {
+	ctx, resp := captureResponse(ctx)
+	nicRequest := c.iaasClient.DefaultAPI.ListProjectNICs(ctx, projectID, region)
+	if labelSelector != nil {
+		nicRequest = nicRequest.LabelSelector(formatLabelSelector(labelSelector))
+	}
+
+	sdkResponse, err := nicRequest.Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK ListProjectNICs failed: %w", classifyError(err, *resp))
+	}
+
+	nics := make([]*NIC, 0, len(sdkResponse.Items))
+	for i := range sdkResponse.Items {
+		nics = append(nics, convertSDKNICtoNIC(&sdkResponse.Items[i]))
+	}
+
+	return nics, nil
+}
+
+// DeleteNIC deletes a network interface by ID via STACKIT SDK
+func (c *SdkStackitClient) DeleteNIC(ctx context.Context, projectID, region, networkID, nicID string) error {
+	ctx, resp := captureResponse(ctx)
+	err := c.iaasClient.DefaultAPI.DeleteNic(ctx, projectID, region, networkID, nicID).Execute()
+	if err != nil {
+		// 404 Not Found is classified as ErrNotFound, callers treat it as success (idempotent)
+		return fmt.Errorf("SDK DeleteNic failed: %w", classifyError(err, *resp))
+	}
+
+	return nil
+}
+
+// GetSecurityGroup retrieves a security group by ID via STACKIT SDK
+func (c *SdkStackitClient) GetSecurityGroup(ctx context.Context, projectID, region, securityGroupID string) (*SecurityGroup, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkSecurityGroup, err := c.iaasClient.DefaultAPI.GetSecurityGroup(ctx, projectID, region, securityGroupID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK GetSecurityGroup failed: %w", classifyError(err, *resp))
+	}
+
+	return &SecurityGroup{
+		ID:   sdkSecurityGroup.GetId(),
+		Name: sdkSecurityGroup.GetName(),
+	}, nil
+}
+
+// GetAffinityGroup retrieves an affinity group by ID via STACKIT SDK
+func (c *SdkStackitClient) GetAffinityGroup(ctx context.Context, projectID, region, affinityGroupID string) (*AffinityGroup, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkAffinityGroup, err := c.iaasClient.DefaultAPI.GetAffinityGroup(ctx, projectID, region, affinityGroupID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK GetAffinityGroup failed: %w", classifyError(err, *resp))
+	}
+
+	return &AffinityGroup{
+		ID:     sdkAffinityGroup.GetId(),
+		Name:   sdkAffinityGroup.GetName(),
+		Policy: sdkAffinityGroup.GetPolicy(),
+	}, nil
+}
+
+// GetKeypair retrieves an SSH keypair by name via STACKIT SDK
+func (c *SdkStackitClient) GetKeypair(ctx context.Context, keypairName string) (*Keypair, error) {
+	ctx, resp := captureResponse(ctx)
+	sdkKeypair, err := c.iaasClient.DefaultAPI.GetKeyPair(ctx, keypairName).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("SDK GetKeyPair failed: %w", classifyError(err, *resp))
+	}
+
+	return &Keypair{
+		Name:        sdkKeypair.GetName(),
+		Fingerprint: sdkKeypair.GetFingerprint(),
+	}, nil
+}
+
 // Helper functions
 
+// formatLabelSelector formats a label selector as "k1=v1,k2=v2"
+func formatLabelSelector(labelSelector map[string]string) string {
+	terms := make([]string, 0, len(labelSelector))
+	for k, v := range labelSelector {
+		terms = append(terms, k+"="+v)
+	}
+	return strings.Join(terms, ",")
+}
+
+func convertSDKVolumeToVolume(sdkVolume *iaas.Volume) *Volume {
+	return &Volume{
+		ID:               sdkVolume.GetId(),
+		Name:             sdkVolume.GetName(),
+		Status:           sdkVolume.GetStatus(),
+		AvailabilityZone: sdkVolume.GetAvailabilityZone(),
+		Size:             int(sdkVolume.GetSize()),
+		PerformanceClass: sdkVolume.GetPerformanceClass(),
+		Labels:           convertLabelsFromSDK(sdkVolume.Labels),
+		ServerID:         sdkVolume.GetServerId(),
+	}
+}
+
+func convertSDKPublicIPToPublicIP(sdkPublicIP *iaas.PublicIp) *PublicIP {
+	return &PublicIP{
+		ID:     sdkPublicIP.GetId(),
+		IP:     sdkPublicIP.GetIp(),
+		Labels: convertLabelsFromSDK(sdkPublicIP.Labels),
+		NICID:  sdkPublicIP.GetNetworkInterface(),
+	}
+}
+
+func convertSDKImageToImage(sdkImage *iaas.Image) *Image {
+	image := &Image{
+		ID:          sdkImage.GetId(),
+		Name:        sdkImage.GetName(),
+		Status:      sdkImage.GetStatus(),
+		Scope:       sdkImage.GetScope(),
+		Labels:      convertLabelsFromSDK(sdkImage.Labels),
+		CreatedAt:   sdkImage.CreatedAt,
+		MinDiskSize: int(sdkImage.GetMinDiskSize()),
+	}
+
+	if sdkImage.Config != nil {
+		image.OSDistro = sdkImage.Config.GetOperatingSystemDistro()
+		image.OSVersion = sdkImage.Config.GetOperatingSystemVersion()
+		image.Architecture = sdkImage.Config.GetArchitecture()
+	}
+
+	return image
+}
+
 func convertSDKNICtoNIC(nic *iaas.NIC) *NIC {
 	addresses := make([]string, 0)
 	for _, addr := range nic.AllowedAddresses {
@@ -334,28 +726,60 @@ func convertSDKNICtoNIC(nic *iaas.NIC) *NIC {
 		AllowedAddresses: addresses,
 		IPv4:             nic.GetIpv4(),
 		IPv6:             nic.GetIpv6(),
+		Labels:           convertLabelsFromSDK(nic.Labels),
+		ServerID:         nic.GetDevice(),
 	}
 }
 
 func convertSDKServerToServer(sdkServer *iaas.Server) *Server {
-	return &Server{
-		ID:           sdkServer.GetId(),
-		Name:         sdkServer.GetName(),
-		Status:       sdkServer.GetStatus(),
-		ErrorMessage: sdkServer.GetErrorMessage(),
-		Labels:       convertLabelsFromSDK(sdkServer.Labels),
+	server := &Server{
+		ID:               sdkServer.GetId(),
+		Name:             sdkServer.GetName(),
+		Status:           sdkServer.GetStatus(),
+		ErrorMessage:     sdkServer.GetErrorMessage(),
+		Labels:           convertLabelsFromSDK(sdkServer.Labels),
+		AvailabilityZone: sdkServer.GetAvailabilityZone(),
+		MachineType:      sdkServer.GetMachineType(),
+		ImageID:          sdkServer.GetImageId(),
+		VolumeIDs:        sdkServer.Volumes,
+		PowerStatus:      sdkServer.GetPowerStatus(),
+		LaunchedAt:       sdkServer.LaunchedAt,
+		CreatedAt:        sdkServer.CreatedAt,
+		Metadata:         sdkServer.Metadata,
+	}
+
+	if sdkServer.BootVolume != nil {
+		server.BootVolumeID = sdkServer.BootVolume.GetId()
 	}
+
+	for i := range sdkServer.Nics {
+		server.NICs = append(server.NICs, convertSDKServerNetworkToNIC(&sdkServer.Nics[i]))
+	}
+
+	return server
 }
 
-// isNotFoundError checks if an error is a 404 Not Found error from the SDK
-func isNotFoundError(err error) bool {
-	if err == nil {
-		return false
+func convertSDKServerNetworkToNIC(nic *iaas.ServerNetwork) *NIC {
+	addresses := make([]string, 0)
+	for _, addr := range nic.AllowedAddresses {
+		if addr.String != nil {
+			addresses = append(addresses, *addr.String)
+		}
 	}
-	// Use the SDK's structured error type to check the HTTP status code
-	var oapiErr *oapierror.GenericOpenAPIError
-	if errors.As(err, &oapiErr) {
-		return oapiErr.StatusCode == 404
+
+	return &NIC{
+		ID:               nic.GetNicId(),
+		NetworkID:        nic.GetNetworkId(),
+		AllowedAddresses: addresses,
+		IPv4:             nic.GetIpv4(),
+		IPv6:             nic.GetIpv6(),
+		PublicIP:         nic.GetPublicIp(),
 	}
-	return false
+}
+
+// captureResponse returns a context that captures the raw HTTP response of the SDK call
+// The response is used to read the request ID when classifying errors
+func captureResponse(ctx context.Context) (context.Context, **http.Response) {
+	resp := new(*http.Response)
+	return runtime.WithCaptureHTTPResponse(ctx, resp), resp
 }
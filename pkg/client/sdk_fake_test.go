@@ -0,0 +1,477 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/fake"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+)
+
+var _ = Describe("SdkStackitClient against fake IaaS API", func() {
+	const (
+		projectID = "11111111-2222-3333-4444-555555555555"
+		region    = "eu01"
+		networkID = "770e8400-e29b-41d4-a716-446655440000"
+	)
+
+	var (
+		ctx       context.Context
+		iaasAPI   *fake.IaaSServer
+		sdkClient *SdkStackitClient
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		iaasAPI = fake.NewIaaSServer()
+		DeferCleanup(iaasAPI.Close)
+
+		GinkgoT().Setenv("STACKIT_IAAS_ENDPOINT", iaasAPI.URL)
+		GinkgoT().Setenv("STACKIT_NO_AUTH", "true")
+
+		var err error
+		sdkClient, err = NewStackitClient("")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	createServer := func(name string, labels map[string]string) *Server {
+		server, err := sdkClient.CreateServer(ctx, projectID, region, &CreateServerRequest{
+			Name:        name,
+			MachineType: "c2i.2",
+			ImageID:     "12345678-1234-1234-1234-123456789abc",
+			Labels:      labels,
+			Networking:  &ServerNetworkingRequest{NetworkID: networkID},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return server
+	}
+
+	It("should walk a server through its lifecycle", func() {
+		created := createServer("machine-1", map[string]string{"kubernetes.io/machine": "machine-1"})
+		Expect(created.ID).NotTo(BeEmpty())
+		Expect(created.Status).To(Equal(fake.StatusCreating))
+
+		server, err := sdkClient.GetServer(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Status).To(Equal(fake.StatusCreating))
+
+		server, err = sdkClient.GetServer(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Status).To(Equal(fake.StatusActive))
+		Expect(server.Labels).To(HaveKeyWithValue("kubernetes.io/machine", "machine-1"))
+		Expect(server.MachineType).To(Equal("c2i.2"))
+		Expect(server.ImageID).To(Equal("12345678-1234-1234-1234-123456789abc"))
+		Expect(server.PowerStatus).To(Equal("RUNNING"))
+		Expect(server.CreatedAt).NotTo(BeNil())
+		Expect(server.LaunchedAt).NotTo(BeNil())
+		Expect(server.NICs).To(HaveLen(1))
+		Expect(server.NICs[0].NetworkID).To(Equal(networkID))
+
+		Expect(sdkClient.DeleteServer(ctx, projectID, region, created.ID)).To(Succeed())
+
+		server, err = sdkClient.GetServer(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Status).To(Equal(fake.StatusDeleting))
+
+		_, err = sdkClient.GetServer(ctx, projectID, region, created.ID)
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+
+	It("should stop active servers", func() {
+		created := createServer("machine-1", nil)
+
+		Expect(sdkClient.StopServer(ctx, projectID, region, created.ID)).To(MatchError(ErrConflict))
+
+		_, err := sdkClient.GetServer(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sdkClient.StopServer(ctx, projectID, region, created.ID)).To(Succeed())
+
+		server, err := sdkClient.GetServer(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Status).To(Equal(fake.StatusInactive))
+		Expect(server.PowerStatus).To(Equal("STOPPED"))
+
+		Expect(sdkClient.StopServer(ctx, projectID, region, "990e8400-e29b-41d4-a716-446655440000")).To(MatchError(ErrNotFound))
+	})
+
+	It("should filter servers by label selector", func() {
+		createServer("machine-1", map[string]string{"kubernetes.io/machineclass": "class-a"})
+		createServer("machine-2", map[string]string{"kubernetes.io/machineclass": "class-b"})
+
+		servers, err := sdkClient.ListServers(ctx, projectID, region, map[string]string{"kubernetes.io/machineclass": "class-a"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(servers).To(HaveLen(1))
+		Expect(servers[0].Name).To(Equal("machine-1"))
+	})
+
+	It("should report the boot volume among the attached volumes", func() {
+		created, err := sdkClient.CreateServer(ctx, projectID, region, &CreateServerRequest{
+			Name:             "machine-1",
+			MachineType:      "c2i.2",
+			AvailabilityZone: "eu01-1",
+			Networking:       &ServerNetworkingRequest{NetworkID: networkID},
+			BootVolume: &BootVolumeRequest{
+				Size:   50,
+				Source: &BootVolumeSourceRequest{Type: "image", ID: "12345678-1234-1234-1234-123456789abc"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		servers, err := sdkClient.ListServers(ctx, projectID, region, nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(servers).To(HaveLen(1))
+		Expect(servers[0].ID).To(Equal(created.ID))
+		Expect(servers[0].AvailabilityZone).To(Equal("eu01-1"))
+		Expect(servers[0].BootVolumeID).NotTo(BeEmpty())
+		Expect(servers[0].VolumeIDs).To(ContainElement(servers[0].BootVolumeID))
+		Expect(servers[0].NICs).To(HaveLen(1))
+	})
+
+	It("should not list servers of other projects", func() {
+		createServer("machine-1", nil)
+
+		servers, err := sdkClient.ListServers(ctx, "99999999-2222-3333-4444-555555555555", region, nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(servers).To(BeEmpty())
+	})
+
+	It("should list and update server NICs", func() {
+		created := createServer("machine-1", nil)
+
+		nics, err := sdkClient.GetNICsForServer(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nics).To(HaveLen(1))
+		Expect(nics[0].NetworkID).To(Equal(networkID))
+		Expect(nics[0].IPv4).NotTo(BeEmpty())
+
+		nic, err := sdkClient.UpdateNIC(ctx, projectID, region, networkID, nics[0].ID, []string{"10.96.0.0/12"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nic.AllowedAddresses).To(ConsistOf("10.96.0.0/12"))
+	})
+
+	It("should attach pre-created NICs", func() {
+		nicID := "880e8400-e29b-41d4-a716-446655440000"
+		iaasAPI.AddNIC(iaas.NIC{Id: new(nicID), NetworkId: new(networkID), Ipv4: new("10.1.0.5")})
+
+		created, err := sdkClient.CreateServer(ctx, projectID, region, &CreateServerRequest{
+			Name:        "machine-1",
+			MachineType: "c2i.2",
+			Networking:  &ServerNetworkingRequest{NICIDs: []string{nicID}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		nics, err := sdkClient.GetNICsForServer(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nics).To(HaveLen(1))
+		Expect(nics[0].ID).To(Equal(nicID))
+	})
+
+	It("should list NICs by label selector and delete detached NICs", func() {
+		nicID := "880e8400-e29b-41d4-a716-446655440000"
+		iaasAPI.AddNIC(iaas.NIC{Id: new(nicID), NetworkId: new(networkID), Labels: map[string]any{"kubernetes.io/machineclass": "class-a"}})
+		iaasAPI.AddNIC(iaas.NIC{Id: new("990e8400-e29b-41d4-a716-446655440000"), NetworkId: new(networkID)})
+
+		created, err := sdkClient.CreateServer(ctx, projectID, region, &CreateServerRequest{
+			Name:        "machine-1",
+			MachineType: "c2i.2",
+			Networking:  &ServerNetworkingRequest{NICIDs: []string{nicID}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		nics, err := sdkClient.ListNICs(ctx, projectID, region, map[string]string{"kubernetes.io/machineclass": "class-a"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nics).To(HaveLen(1))
+		Expect(nics[0].Labels).To(HaveKeyWithValue("kubernetes.io/machineclass", "class-a"))
+		Expect(nics[0].ServerID).To(Equal(created.ID))
+
+		nic, err := sdkClient.UpdateNICLabels(ctx, projectID, region, networkID, nicID, map[string]string{"kubernetes.io/machineclass": "class-b"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nic.Labels).To(Equal(map[string]string{"kubernetes.io/machineclass": "class-b"}))
+		nics, err = sdkClient.ListNICs(ctx, projectID, region, map[string]string{"kubernetes.io/machineclass": "class-a"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nics).To(BeEmpty())
+
+		err = sdkClient.DeleteNIC(ctx, projectID, region, networkID, nicID)
+		Expect(err).To(MatchError(ErrConflict))
+
+		iaasAPI.DeletingPolls = 0
+		Expect(sdkClient.DeleteServer(ctx, projectID, region, created.ID)).To(Succeed())
+		Expect(sdkClient.DeleteNIC(ctx, projectID, region, networkID, nicID)).To(Succeed())
+
+		err = sdkClient.DeleteNIC(ctx, projectID, region, networkID, nicID)
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+
+	It("should attach volumes", func() {
+		created := createServer("machine-1", nil)
+		volumeID := "990e8400-e29b-41d4-a716-446655440000"
+
+		Expect(sdkClient.AttachVolume(ctx, projectID, region, created.ID, volumeID)).To(Succeed())
+
+		server, err := sdkClient.GetServer(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.VolumeIDs).To(ContainElement(volumeID))
+
+		err = sdkClient.AttachVolume(ctx, projectID, region, created.ID, volumeID)
+		Expect(err).To(MatchError(ErrConflict))
+	})
+
+	It("should walk a volume through its lifecycle", func() {
+		server := createServer("machine-1", nil)
+		created, err := sdkClient.CreateVolume(ctx, projectID, region, &CreateVolumeRequest{
+			Name:             "machine-1-data",
+			AvailabilityZone: "eu01-1",
+			Size:             100,
+			PerformanceClass: "storage_premium_perf4",
+			Source:           &VolumeSourceRequest{Type: "snapshot", ID: "990e8400-e29b-41d4-a716-446655440000"},
+			Labels:           map[string]string{"kubernetes.io/machine": "machine-1"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.Status).To(Equal(fake.VolumeStatusCreating))
+
+		// attaching requires the volume to be AVAILABLE
+		Expect(sdkClient.AttachVolume(ctx, projectID, region, server.ID, created.ID)).To(MatchError(ErrConflict))
+
+		volume, err := sdkClient.GetVolume(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volume.Status).To(Equal(fake.VolumeStatusCreating))
+		volume, err = sdkClient.GetVolume(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volume.Status).To(Equal(fake.VolumeStatusAvailable))
+		Expect(volume.Name).To(Equal("machine-1-data"))
+		Expect(volume.AvailabilityZone).To(Equal("eu01-1"))
+		Expect(volume.Size).To(Equal(100))
+		Expect(volume.PerformanceClass).To(Equal("storage_premium_perf4"))
+
+		Expect(sdkClient.AttachVolume(ctx, projectID, region, server.ID, created.ID)).To(Succeed())
+
+		volumes, err := sdkClient.ListVolumes(ctx, projectID, region, map[string]string{"kubernetes.io/machine": "machine-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volumes).To(HaveLen(1))
+		Expect(volumes[0].Status).To(Equal(fake.VolumeStatusAttached))
+		Expect(volumes[0].ServerID).To(Equal(server.ID))
+		Expect(volumes[0].Labels).To(HaveKeyWithValue("kubernetes.io/machine", "machine-1"))
+
+		volume, err = sdkClient.UpdateVolume(ctx, projectID, region, created.ID, &UpdateVolumeRequest{
+			Labels: map[string]string{"kubernetes.io/machine": "machine-1", "kubernetes.io/machineclass": "class-a"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volume.Labels).To(HaveKeyWithValue("kubernetes.io/machineclass", "class-a"))
+
+		// attached volumes cannot be deleted, deleting the server detaches them
+		Expect(sdkClient.DeleteVolume(ctx, projectID, region, created.ID)).To(MatchError(ErrConflict))
+		iaasAPI.DeletingPolls = 0
+		Expect(sdkClient.DeleteServer(ctx, projectID, region, server.ID)).To(Succeed())
+
+		Expect(sdkClient.DeleteVolume(ctx, projectID, region, created.ID)).To(Succeed())
+		_, err = sdkClient.GetVolume(ctx, projectID, region, created.ID)
+		Expect(err).To(MatchError(ErrNotFound))
+		Expect(sdkClient.DeleteVolume(ctx, projectID, region, created.ID)).To(MatchError(ErrNotFound))
+	})
+
+	It("should filter volumes by label selector", func() {
+		for _, machine := range []string{"machine-1", "machine-2"} {
+			_, err := sdkClient.CreateVolume(ctx, projectID, region, &CreateVolumeRequest{
+				Name:             machine + "-data",
+				AvailabilityZone: "eu01-1",
+				Size:             10,
+				Labels:           map[string]string{"kubernetes.io/machine": machine},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		volumes, err := sdkClient.ListVolumes(ctx, projectID, region, map[string]string{"kubernetes.io/machine": "machine-2"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volumes).To(HaveLen(1))
+		Expect(volumes[0].Name).To(Equal("machine-2-data"))
+	})
+
+	It("should walk a public IP through its lifecycle", func() {
+		server := createServer("machine-1", nil)
+		created, err := sdkClient.CreatePublicIP(ctx, projectID, region, &CreatePublicIPRequest{
+			Labels: map[string]string{"kubernetes.io/machine": "machine-1"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.IP).NotTo(BeEmpty())
+		Expect(created.NICID).To(BeEmpty())
+
+		nics, err := sdkClient.GetNICsForServer(ctx, projectID, region, server.ID)
+		Expect(err).NotTo(HaveOccurred())
+		nicID := nics[0].ID
+		publicIP, err := sdkClient.UpdatePublicIP(ctx, projectID, region, created.ID, &UpdatePublicIPRequest{NICID: &nicID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(publicIP.NICID).To(Equal(nicID))
+		Expect(publicIP.Labels).To(HaveKeyWithValue("kubernetes.io/machine", "machine-1"))
+
+		publicIP, err = sdkClient.GetPublicIP(ctx, projectID, region, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(publicIP.NICID).To(Equal(nicID))
+
+		// the public IP is reported on the NIC of the server
+		servers, err := sdkClient.ListServers(ctx, projectID, region, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(servers[0].NICs[0].PublicIP).To(Equal(created.IP))
+
+		publicIPs, err := sdkClient.ListPublicIPs(ctx, projectID, region, map[string]string{"kubernetes.io/machine": "machine-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(publicIPs).To(HaveLen(1))
+
+		// an empty NIC ID is no dissociation, only an explicit null is
+		_, err = sdkClient.UpdatePublicIP(ctx, projectID, region, created.ID, &UpdatePublicIPRequest{NICID: new("")})
+		Expect(err).To(HaveOccurred())
+		publicIP, err = sdkClient.UpdatePublicIP(ctx, projectID, region, created.ID, &UpdatePublicIPRequest{Labels: map[string]string{}, DissociateNIC: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(publicIP.NICID).To(BeEmpty())
+		Expect(publicIP.Labels).NotTo(HaveKey("kubernetes.io/machine"))
+
+		publicIPs, err = sdkClient.ListPublicIPs(ctx, projectID, region, map[string]string{"kubernetes.io/machine": "machine-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(publicIPs).To(BeEmpty())
+
+		Expect(sdkClient.DeletePublicIP(ctx, projectID, region, created.ID)).To(Succeed())
+		Expect(sdkClient.DeletePublicIP(ctx, projectID, region, created.ID)).To(MatchError(ErrNotFound))
+	})
+
+	It("should list images with their OS configuration", func() {
+		image := iaas.Image{
+			Id:         new("12345678-1234-1234-1234-123456789abc"),
+			Name:       "ubuntu-22.04",
+			DiskFormat: "qcow2",
+			Status:     new("AVAILABLE"),
+			Scope:      new("public"),
+			Config: &iaas.ImageConfig{
+				Architecture:           new("x86"),
+				OperatingSystemDistro:  *iaas.NewNullableString(new("ubuntu")),
+				OperatingSystemVersion: *iaas.NewNullableString(new("22.04")),
+			},
+		}
+		iaasAPI.AddImage(image)
+
+		images, err := sdkClient.ListImages(ctx, projectID, region)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(images).To(HaveLen(1))
+		Expect(images[0].ID).To(Equal("12345678-1234-1234-1234-123456789abc"))
+		Expect(images[0].Status).To(Equal("AVAILABLE"))
+		Expect(images[0].OSDistro).To(Equal("ubuntu"))
+		Expect(images[0].OSVersion).To(Equal("22.04"))
+		Expect(images[0].Architecture).To(Equal("x86"))
+	})
+
+	It("should get the resources referenced by a ProviderSpec", func() {
+		iaasAPI.AddImage(iaas.Image{Id: new("12345678-1234-1234-1234-123456789abc"), Name: "ubuntu-22.04", DiskFormat: "qcow2", MinDiskSize: new(int64(20))})
+		iaasAPI.AddMachineType(iaas.MachineType{Name: "c2i.2", Vcpus: 2, Ram: 4096})
+		iaasAPI.AddNetwork(iaas.Network{Id: networkID, Name: "nodes", Status: "CREATED"})
+		iaasAPI.AddSecurityGroup(iaas.SecurityGroup{Id: new("880e8400-e29b-41d4-a716-446655440000"), Name: "nodes"})
+		iaasAPI.AddAffinityGroup(iaas.AffinityGroup{Id: new("990e8400-e29b-41d4-a716-446655440000"), Name: "spread", Policy: "soft-anti-affinity"})
+		iaasAPI.AddKeypair(iaas.Keypair{Name: new("my-key"), PublicKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBx"})
+		iaasAPI.AddNIC(iaas.NIC{Id: new("aa0e8400-e29b-41d4-a716-446655440000"), NetworkId: new(networkID)})
+
+		image, err := sdkClient.GetImage(ctx, projectID, region, "12345678-1234-1234-1234-123456789abc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image.MinDiskSize).To(Equal(20))
+
+		machineType, err := sdkClient.GetMachineType(ctx, projectID, region, "c2i.2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(machineType.RAM).To(Equal(4096))
+
+		network, err := sdkClient.GetNetwork(ctx, projectID, region, networkID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(network.Name).To(Equal("nodes"))
+
+		nic, err := sdkClient.GetNIC(ctx, projectID, region, "aa0e8400-e29b-41d4-a716-446655440000")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nic.NetworkID).To(Equal(networkID))
+
+		securityGroup, err := sdkClient.GetSecurityGroup(ctx, projectID, region, "880e8400-e29b-41d4-a716-446655440000")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(securityGroup.Name).To(Equal("nodes"))
+
+		affinityGroup, err := sdkClient.GetAffinityGroup(ctx, projectID, region, "990e8400-e29b-41d4-a716-446655440000")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(affinityGroup.Policy).To(Equal("soft-anti-affinity"))
+
+		keypair, err := sdkClient.GetKeypair(ctx, "my-key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keypair.Name).To(Equal("my-key"))
+
+		_, err = sdkClient.GetMachineType(ctx, projectID, region, "c9z.2")
+		Expect(err).To(MatchError(ErrNotFound))
+		_, err = sdkClient.GetKeypair(ctx, "other-key")
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+
+	It("should report servers forced into ERROR state", func() {
+		created := createServer("machine-1", nil)
+		Expect(iaasAPI.SetServerStatus(created.ID, fake.StatusError, "No valid host was found")).To(Succeed())
+
+		server, err := sdkClient.GetServer(ctx, projectID, region, created.ID)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Status).To(Equal(fake.StatusError))
+		Expect(server.ErrorMessage).To(Equal("No valid host was found"))
+	})
+
+	Context("with injected faults", func() {
+		It("should classify injected errors", func() {
+			header := http.Header{}
+			header.Set("Retry-After", "2")
+			header.Set("X-Trace-Id", "trace-1")
+			iaasAPI.InjectFault(fake.Fault{
+				Operation:  fake.OpListServers,
+				StatusCode: http.StatusTooManyRequests,
+				Message:    "slow down",
+				Header:     header,
+				Times:      1,
+			})
+
+			_, err := sdkClient.ListServers(ctx, projectID, region, nil)
+
+			Expect(err).To(MatchError(ErrRateLimited))
+			var apiErr *APIError
+			Expect(errors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.Message).To(Equal("slow down"))
+			Expect(apiErr.RequestID).To(Equal("trace-1"))
+			Expect(apiErr.RetryAfter.Seconds()).To(BeNumerically("==", 2))
+
+			// fault is used up
+			_, err = sdkClient.ListServers(ctx, projectID, region, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should detect capacity errors on create", func() {
+			iaasAPI.InjectFault(fake.Fault{
+				Operation:  fake.OpCreateServer,
+				StatusCode: http.StatusInternalServerError,
+				Message:    "No valid host was found",
+			})
+
+			_, err := sdkClient.CreateServer(ctx, projectID, region, &CreateServerRequest{
+				Name:        "machine-1",
+				MachineType: "c2i.2",
+				Networking:  &ServerNetworkingRequest{NetworkID: networkID},
+			})
+
+			Expect(err).To(MatchError(ErrCapacityExhausted))
+		})
+
+		It("should be retried by the retrying client", func() {
+			iaasAPI.InjectFault(fake.Fault{
+				Operation:  fake.OpListServers,
+				StatusCode: http.StatusServiceUnavailable,
+				Times:      2,
+			})
+			retrying := NewRetryingStackitClient(sdkClient, RetryConfig{MaxAttempts: 3})
+
+			_, err := retrying.ListServers(ctx, projectID, region, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -1,93 +1,14 @@
 package client
 
 import (
-	"errors"
-	"fmt"
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/stackitcloud/stackit-sdk-go/core/oapierror"
 	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
 )
 
-var _ = Describe("SDK Client Helpers", func() {
-
-	Describe("isNotFoundError", func() {
-		Context("with SDK 404 errors", func() {
-			It("should detect GenericOpenAPIError with 404 status code", func() {
-				err := &oapierror.GenericOpenAPIError{
-					StatusCode:   404,
-					ErrorMessage: "Not Found",
-				}
-
-				result := isNotFoundError(err)
-
-				Expect(result).To(BeTrue())
-			})
-
-			It("should detect wrapped GenericOpenAPIError with 404", func() {
-				baseErr := &oapierror.GenericOpenAPIError{
-					StatusCode:   404,
-					ErrorMessage: "Server not found",
-					Body:         []byte(`{"message": "server does not exist"}`),
-				}
-				wrappedErr := fmt.Errorf("failed to get server: %w", baseErr)
-
-				result := isNotFoundError(wrappedErr)
-
-				Expect(result).To(BeTrue())
-			})
-		})
-
-		Context("with non-404 errors", func() {
-			It("should not detect GenericOpenAPIError with 500 status", func() {
-				err := &oapierror.GenericOpenAPIError{
-					StatusCode:   500,
-					ErrorMessage: "Internal Server Error",
-				}
-
-				result := isNotFoundError(err)
-
-				Expect(result).To(BeFalse())
-			})
-
-			It("should not detect GenericOpenAPIError with 403 status", func() {
-				err := &oapierror.GenericOpenAPIError{
-					StatusCode:   403,
-					ErrorMessage: "Forbidden",
-				}
-
-				result := isNotFoundError(err)
-
-				Expect(result).To(BeFalse())
-			})
-
-			It("should not detect generic error without status code", func() {
-				err := errors.New("something went wrong")
-
-				result := isNotFoundError(err)
-
-				Expect(result).To(BeFalse())
-			})
-
-			It("should not detect wrapped generic error", func() {
-				err := fmt.Errorf("API call failed: %w", errors.New("connection timeout"))
-
-				result := isNotFoundError(err)
-
-				Expect(result).To(BeFalse())
-			})
-
-			It("should return false for nil error", func() {
-				result := isNotFoundError(nil)
-
-				Expect(result).To(BeFalse())
-			})
-		})
-	})
-})
-
 var _ = Describe("SDK Type Conversion Helpers", func() {
 
 	Describe("convertLabelsToSDK", func() {
@@ -262,6 +183,73 @@ var _ = Describe("SDK Type Conversion Helpers", func() {
 		})
 	})
 
+	Describe("convertSDKServerToServer", func() {
+		It("should populate placement, flavor, image, volumes and timestamps", func() {
+			createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			launchedAt := createdAt.Add(time.Minute)
+			sdkServer := &iaas.Server{
+				Id:               new("server-1"),
+				Name:             "machine-1",
+				Status:           new("ACTIVE"),
+				AvailabilityZone: new("eu01-1"),
+				MachineType:      "c2i.2",
+				ImageId:          new("image-1"),
+				BootVolume:       &iaas.BootVolume{Id: new("volume-boot")},
+				Volumes:          []string{"volume-boot", "volume-data"},
+				PowerStatus:      new("RUNNING"),
+				CreatedAt:        &createdAt,
+				LaunchedAt:       &launchedAt,
+				Metadata:         map[string]any{"owner": "team-a"},
+			}
+
+			result := convertSDKServerToServer(sdkServer)
+
+			Expect(result.ID).To(Equal("server-1"))
+			Expect(result.AvailabilityZone).To(Equal("eu01-1"))
+			Expect(result.MachineType).To(Equal("c2i.2"))
+			Expect(result.ImageID).To(Equal("image-1"))
+			Expect(result.BootVolumeID).To(Equal("volume-boot"))
+			Expect(result.VolumeIDs).To(Equal([]string{"volume-boot", "volume-data"}))
+			Expect(result.PowerStatus).To(Equal("RUNNING"))
+			Expect(result.CreatedAt).To(Equal(&createdAt))
+			Expect(result.LaunchedAt).To(Equal(&launchedAt))
+			Expect(result.Metadata).To(HaveKeyWithValue("owner", "team-a"))
+		})
+
+		It("should populate NICs", func() {
+			addr := "10.96.0.0/12"
+			sdkServer := &iaas.Server{
+				Id: new("server-1"),
+				Nics: []iaas.ServerNetwork{
+					{
+						NicId:            "nic-1",
+						NetworkId:        "net-1",
+						Ipv4:             new("10.0.0.5"),
+						PublicIp:         new("193.148.160.1"),
+						AllowedAddresses: []iaas.AllowedAddressesInner{{String: &addr}},
+					},
+				},
+			}
+
+			result := convertSDKServerToServer(sdkServer)
+
+			Expect(result.NICs).To(HaveLen(1))
+			Expect(result.NICs[0].ID).To(Equal("nic-1"))
+			Expect(result.NICs[0].NetworkID).To(Equal("net-1"))
+			Expect(result.NICs[0].IPv4).To(Equal("10.0.0.5"))
+			Expect(result.NICs[0].PublicIP).To(Equal("193.148.160.1"))
+			Expect(result.NICs[0].AllowedAddresses).To(ConsistOf("10.96.0.0/12"))
+		})
+
+		It("should leave optional fields empty", func() {
+			result := convertSDKServerToServer(&iaas.Server{Id: new("server-1")})
+
+			Expect(result.BootVolumeID).To(BeEmpty())
+			Expect(result.NICs).To(BeEmpty())
+			Expect(result.LaunchedAt).To(BeNil())
+		})
+	})
+
 	Describe("NewStackitClient", func() {
 		Context("with STACKIT_NO_AUTH enabled", func() {
 			It("should create client successfully without authentication", func() {
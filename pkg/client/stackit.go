@@ -2,14 +2,16 @@ package client
 
 import (
 	"context"
+	"time"
 )
 
 // StackitClient is an interface for interacting with STACKIT IAAS API
 // This allows us to mock the client in unit tests
 //
-// Architecture: Single-tenant design
-// - Each client instance is bound to one STACKIT project via serviceAccountKey
+// Architecture: One client per credential
+// - Each client instance is bound to one service account via serviceAccountKey
 // - The serviceAccountKey is provided once during client creation (NewStackitClient)
+// - The projectID is passed per call, so one client can serve every project its service account has access to
 // - The SDK automatically handles JWT token generation and refresh
 //
 // Note: region parameter is required by STACKIT SDK v1.0.0+
@@ -21,12 +23,58 @@ type StackitClient interface {
 	GetServer(ctx context.Context, projectID, region, serverID string) (*Server, error)
 	// DeleteServer deletes a server by ID from STACKIT
 	DeleteServer(ctx context.Context, projectID, region, serverID string) error
+	// StopServer stops a server, the server is stopped once its status is INACTIVE
+	StopServer(ctx context.Context, projectID, region, serverID string) error
 	// ListServers lists all servers in a project
 	ListServers(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*Server, error)
 	// GetNICsForServer retrieves a network interfaces for a given server
 	GetNICsForServer(ctx context.Context, projectID, region, serverID string) ([]*NIC, error)
 	// UpdateNIC updates a network interface
 	UpdateNIC(ctx context.Context, projectID, region, networkID, nicID string, allowedAddresses []string) (*NIC, error)
+	// UpdateNICLabels replaces the labels of a network interface
+	UpdateNICLabels(ctx context.Context, projectID, region, networkID, nicID string, labels map[string]string) (*NIC, error)
+	// AttachVolume attaches an existing volume to a server
+	AttachVolume(ctx context.Context, projectID, region, serverID, volumeID string) error
+	// CreateVolume creates a new block storage volume
+	CreateVolume(ctx context.Context, projectID, region string, req *CreateVolumeRequest) (*Volume, error)
+	// GetVolume retrieves a volume by ID
+	GetVolume(ctx context.Context, projectID, region, volumeID string) (*Volume, error)
+	// ListVolumes lists all volumes in a project matching the label selector
+	ListVolumes(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*Volume, error)
+	// UpdateVolume updates the labels of a volume
+	UpdateVolume(ctx context.Context, projectID, region, volumeID string, req *UpdateVolumeRequest) (*Volume, error)
+	// DeleteVolume deletes a volume by ID
+	DeleteVolume(ctx context.Context, projectID, region, volumeID string) error
+	// CreatePublicIP allocates a new public IP
+	CreatePublicIP(ctx context.Context, projectID, region string, req *CreatePublicIPRequest) (*PublicIP, error)
+	// ListPublicIPs lists all public IPs in a project matching the label selector
+	ListPublicIPs(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*PublicIP, error)
+	// GetPublicIP retrieves a public IP by ID
+	GetPublicIP(ctx context.Context, projectID, region, publicIPID string) (*PublicIP, error)
+	// UpdatePublicIP updates the labels and the NIC association of a public IP
+	UpdatePublicIP(ctx context.Context, projectID, region, publicIPID string, req *UpdatePublicIPRequest) (*PublicIP, error)
+	// DeletePublicIP releases a public IP by ID
+	DeletePublicIP(ctx context.Context, projectID, region, publicIPID string) error
+	// ListImages lists all images available to a project, including public images
+	ListImages(ctx context.Context, projectID, region string) ([]*Image, error)
+	// GetImage retrieves an image by ID
+	GetImage(ctx context.Context, projectID, region, imageID string) (*Image, error)
+	// GetMachineType retrieves a machine type by name
+	GetMachineType(ctx context.Context, projectID, region, machineType string) (*MachineType, error)
+	// GetNetwork retrieves a network by ID
+	GetNetwork(ctx context.Context, projectID, region, networkID string) (*Network, error)
+	// GetNIC retrieves a network interface of the project by ID
+	GetNIC(ctx context.Context, projectID, region, nicID string) (*NIC, error)
+	// ListNICs lists all network interfaces in a project matching the label selector
+	ListNICs(ctx context.Context, projectID, region string, labelSelector map[string]string) ([]*NIC, error)
+	// DeleteNIC deletes a network interface by ID
+	DeleteNIC(ctx context.Context, projectID, region, networkID, nicID string) error
+	// GetSecurityGroup retrieves a security group by ID
+	GetSecurityGroup(ctx context.Context, projectID, region, securityGroupID string) (*SecurityGroup, error)
+	// GetAffinityGroup retrieves an affinity group by ID
+	GetAffinityGroup(ctx context.Context, projectID, region, affinityGroupID string) (*AffinityGroup, error)
+	// GetKeypair retrieves an SSH keypair by name, keypairs belong to the service account and not to a project
+	GetKeypair(ctx context.Context, keypairName string) (*Keypair, error)
 }
 
 // CreateServerRequest represents the request to create a server
@@ -74,6 +122,45 @@ type BootVolumeSourceRequest struct {
 	ID   string `json:"id"`
 }
 
+// CreateVolumeRequest represents the request to create a block storage volume
+type CreateVolumeRequest struct {
+	Name             string               `json:"name,omitempty"`
+	AvailabilityZone string               `json:"availabilityZone"`
+	Size             int                  `json:"size,omitempty"`
+	PerformanceClass string               `json:"performanceClass,omitempty"`
+	Source           *VolumeSourceRequest `json:"source,omitempty"`
+	Labels           map[string]string    `json:"labels,omitempty"`
+}
+
+// UpdateVolumeRequest represents the request to update a volume
+type UpdateVolumeRequest struct {
+	// Labels replace the labels of the volume if not nil
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// VolumeSourceRequest represents the source for creating a volume, e.g. a snapshot
+type VolumeSourceRequest struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// CreatePublicIPRequest represents the request to allocate a public IP
+type CreatePublicIPRequest struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	// NICID is the network interface the public IP is associated with, empty to allocate it unassociated
+	NICID string `json:"networkInterface,omitempty"`
+}
+
+// UpdatePublicIPRequest represents the request to update a public IP
+type UpdatePublicIPRequest struct {
+	// Labels replace the labels of the public IP if not nil
+	Labels map[string]string `json:"labels,omitempty"`
+	// NICID is the network interface to associate the public IP with if not nil
+	NICID *string `json:"networkInterface,omitempty"`
+	// DissociateNIC dissociates the public IP from its network interface, NICID is ignored then
+	DissociateNIC bool `json:"-"`
+}
+
 // AgentRequest represents the STACKIT agent configuration for a server
 type AgentRequest struct {
 	Provisioned *bool `json:"provisioned,omitempty"`
@@ -81,11 +168,24 @@ type AgentRequest struct {
 
 // Server represents a STACKIT server response
 type Server struct {
-	ID           string            `json:"id"`
-	Name         string            `json:"name"`
-	Status       string            `json:"status"`
-	ErrorMessage string            `json:"errorMessage,omitempty"`
-	Labels       map[string]string `json:"labels,omitempty"`
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Status           string            `json:"status"`
+	ErrorMessage     string            `json:"errorMessage,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	AvailabilityZone string            `json:"availabilityZone,omitempty"`
+	MachineType      string            `json:"machineType,omitempty"`
+	ImageID          string            `json:"imageId,omitempty"`
+	BootVolumeID     string            `json:"bootVolumeId,omitempty"`
+	// VolumeIDs are the IDs of all volumes attached to the server, including the boot volume
+	VolumeIDs []string `json:"volumes,omitempty"`
+	// NICs are the network interfaces attached to the server
+	NICs []*NIC `json:"nics,omitempty"`
+	// PowerStatus is one of CRASHED, ERROR, RUNNING or STOPPED
+	PowerStatus string         `json:"powerStatus,omitempty"`
+	LaunchedAt  *time.Time     `json:"launchedAt,omitempty"`
+	CreatedAt   *time.Time     `json:"createdAt,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
 // NIC represents a STACKIT network interface
@@ -95,4 +195,84 @@ type NIC struct {
 	AllowedAddresses []string `json:"allowedAddresses,omitempty"`
 	IPv4             string   `json:"ipv4,omitempty"`
 	IPv6             string   `json:"ipv6,omitempty"`
+	// PublicIP is the public IP associated with the NIC, only set for NICs of a Server
+	PublicIP string `json:"publicIp,omitempty"`
+	// Labels are only set for NICs retrieved through the project, not for NICs of a Server
+	Labels map[string]string `json:"labels,omitempty"`
+	// ServerID is the server the NIC is attached to, empty if not attached
+	ServerID string `json:"device,omitempty"`
+}
+
+// Volume represents a STACKIT block storage volume
+type Volume struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name,omitempty"`
+	Status           string            `json:"status"`
+	AvailabilityZone string            `json:"availabilityZone"`
+	Size             int               `json:"size,omitempty"`
+	PerformanceClass string            `json:"performanceClass,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	// ServerID is the server the volume is attached to, empty if not attached
+	ServerID string `json:"serverId,omitempty"`
+}
+
+// PublicIP represents a STACKIT public IP
+type PublicIP struct {
+	ID     string            `json:"id"`
+	IP     string            `json:"ip"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// NICID is the network interface the public IP is associated with, empty if not associated
+	NICID string `json:"networkInterface,omitempty"`
+}
+
+// Image represents a STACKIT OS image
+type Image struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status,omitempty"`
+	// Scope is one of public, local, projects or organization
+	Scope        string            `json:"scope,omitempty"`
+	OSDistro     string            `json:"osDistro,omitempty"`
+	OSVersion    string            `json:"osVersion,omitempty"`
+	Architecture string            `json:"architecture,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	CreatedAt    *time.Time        `json:"createdAt,omitempty"`
+	// MinDiskSize is the minimum size of a boot volume created from the image in GB
+	MinDiskSize int `json:"minDiskSize,omitempty"`
+}
+
+// MachineType represents a STACKIT server type
+type MachineType struct {
+	Name  string `json:"name"`
+	VCPUs int    `json:"vcpus"`
+	// RAM is the memory in MB
+	RAM int `json:"ram"`
+	// Disk is the size of the local disk in GB
+	Disk int `json:"disk"`
+}
+
+// Network represents a STACKIT network
+type Network struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// SecurityGroup represents a STACKIT security group
+type SecurityGroup struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AffinityGroup represents a STACKIT affinity group
+type AffinityGroup struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Policy string `json:"policy"`
+}
+
+// Keypair represents a STACKIT SSH keypair
+type Keypair struct {
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
@@ -0,0 +1,8 @@
+// Package api contains the internal version of the STACKIT ProviderSpec API
+// The provider works on these types only, versioned ProviderSpecs are defaulted and converted to them on decoding
+//
+// +k8s:deepcopy-gen=package
+// +groupName=machine.stackit.cloud
+package api
+
+//go:generate ../../../hack/update-codegen.sh
@@ -0,0 +1,16 @@
+// Package install registers all versions of the STACKIT ProviderSpec API
+package install
+
+import (
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+)
+
+// Install registers the internal and all versioned ProviderSpec types with the scheme
+func Install(scheme *runtime.Scheme) {
+	utilruntime.Must(api.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(scheme.SetVersionPriority(v1alpha1.SchemeGroupVersion))
+}
@@ -1,19 +1,45 @@
 package api
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Image selector scopes
+const (
+	// ImageScopePublic selects public images provided by STACKIT
+	ImageScopePublic = "public"
+	// ImageScopeProject selects images uploaded to or shared with the project
+	ImageScopeProject = "project"
+)
+
 // ProviderSpec is the spec to be used while parsing the calls.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type ProviderSpec struct {
+	metav1.TypeMeta `json:",inline"`
+
 	// Region is the STACKIT region (e.g., "eu01", "eu02")
 	// Required field for creating a server.
 	Region string `json:"region"`
 
 	// MachineType is the STACKIT server type (e.g., "c2i.2", "m2i.8")
-	// Required field for creating a server.
+	// Required field for creating a server, unless MachineTypes is specified.
 	MachineType string `json:"machineType"`
 
+	// MachineTypes is an ordered list of acceptable STACKIT server types
+	// Optional field. Mutually exclusive with MachineType.
+	// If the platform has no capacity left for a type, the server is created with the next type in the list.
+	MachineTypes []string `json:"machineTypes,omitempty"`
+
 	// ImageID is the UUID of the OS image to use for the server
-	// Required field for creating a server.
+	// Required field for creating a server, unless Image or BootVolume.Source is specified.
 	ImageID string `json:"imageId"`
 
+	// Image selects the OS image by name and filters instead of a fixed UUID
+	// Optional field. Mutually exclusive with ImageID.
+	// The newest matching image is resolved through the IaaS API and recorded in the server labels.
+	Image *ImageSelectorSpec `json:"image,omitempty"`
+
 	// Labels are key-value pairs used to tag and identify servers
 	// Used by MCM for mapping servers to MachineClasses and orphan VM detection
 	// Optional field. MCM will automatically add standard labels.
@@ -47,6 +73,16 @@ type ProviderSpec struct {
 	// Optional field. Allows attaching additional data volumes beyond the boot disk.
 	Volumes []string `json:"volumes,omitempty"`
 
+	// DataVolumes are volumes created for every machine and attached to its server
+	// Optional field. Unlike Volumes, every machine gets its own set of volumes, which makes them usable in MachineDeployments.
+	// The volumes are labelled with the machine and MachineClass labels and deleted with the machine unless deleteOnTermination is false.
+	DataVolumes []DataVolumeSpec `json:"dataVolumes,omitempty"`
+
+	// PublicIP assigns a public IPv4 to every machine, associated with the primary NIC of its server
+	// Optional field. If not specified, servers are only reachable through their private addresses.
+	// The public IP is reported as NodeExternalIP and released or returned to its pool when the machine is deleted.
+	PublicIP *PublicIPSpec `json:"publicIP,omitempty"`
+
 	// KeypairName is the name of the SSH keypair for server access
 	// Optional field. If specified, the public key will be injected into the server for SSH access.
 	// The keypair must already exist in the STACKIT project.
@@ -59,6 +95,13 @@ type ProviderSpec struct {
 	// Example values: "eu01-1", "eu01-2"
 	AvailabilityZone string `json:"availabilityZone,omitempty"`
 
+	// AvailabilityZones is a list of candidate availability zones
+	// Optional field. Mutually exclusive with AvailabilityZone.
+	// Machines are spread over the zones, a zone without capacity is skipped in favour of the next one.
+	// Existing volumes are bound to a single zone and cannot be combined with this field.
+	// Example: ["eu01-1", "eu01-2", "eu01-3"]
+	AvailabilityZones []string `json:"availabilityZones,omitempty"`
+
 	// AffinityGroup is the UUID of the affinity group to associate with the server
 	// Optional field. Affinity groups control server placement for performance or availability requirements
 	// The affinity group must already exist in the STACKIT project
@@ -80,7 +123,85 @@ type ProviderSpec struct {
 	// Metadata is a generic JSON object for storing arbitrary key-value pairs
 	// Optional field. Can be used to store custom metadata that doesn't fit into other fields
 	// Example: {"environment": "production", "cost-center": "12345"}
-	Metadata map[string]any `json:"metadata,omitempty"`
+	Metadata Metadata `json:"metadata,omitempty"`
+
+	// ShutdownGracePeriod is the time the server gets to shut down cleanly before it is deleted
+	// Optional field. If set, DeleteMachine stops the server and waits up to this period for it to be stopped
+	// If not specified, the server is deleted right away
+	// Example: "2m"
+	ShutdownGracePeriod *metav1.Duration `json:"shutdownGracePeriod,omitempty"`
+}
+
+// Metadata is a free-form JSON object
+// The deep copy is written by hand because deepcopy-gen does not support interface values
+//
+// +k8s:deepcopy-gen=false
+type Metadata map[string]any
+
+// DeepCopyInto copies the JSON values of the receiver into out
+func (in Metadata) DeepCopyInto(out *Metadata) {
+	if in == nil {
+		*out = nil
+		return
+	}
+	*out = make(Metadata, len(in))
+	for key, val := range in {
+		(*out)[key] = deepCopyJSONValue(val)
+	}
+}
+
+// DeepCopy returns a deep copy of the JSON object
+func (in Metadata) DeepCopy() Metadata {
+	if in == nil {
+		return nil
+	}
+	out := new(Metadata)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// deepCopyJSONValue copies nested objects and arrays, scalar values are immutable and shared
+func deepCopyJSONValue(val any) any {
+	switch val := val.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for key, v := range val {
+			out[key] = deepCopyJSONValue(v)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, v := range val {
+			out[i] = deepCopyJSONValue(v)
+		}
+		return out
+	default:
+		return val
+	}
+}
+
+// ImageSelectorSpec selects an OS image by name and filters
+// If several images match, the one with the highest OS version is used, ties are broken by creation time
+type ImageSelectorSpec struct {
+	// Name is the name of the image
+	// Required field. Must match the image name exactly.
+	Name string `json:"name"`
+
+	// Version is a semantic version constraint on the OS version of the image
+	// Optional field. Example: ">= 22.04, < 24", images without OS version never match a constraint.
+	Version string `json:"version,omitempty"`
+
+	// Distro is the OS distribution of the image
+	// Optional field. Example: "ubuntu", "debian"
+	Distro string `json:"distro,omitempty"`
+
+	// Architecture is the CPU architecture of the image
+	// Optional field. Example: "x86", "arm64"
+	Architecture string `json:"architecture,omitempty"`
+
+	// Scope limits the search to public images or to images of the project
+	// Optional field. One of "public" or "project", both are searched if not specified.
+	Scope string `json:"scope,omitempty"`
 }
 
 // AgentSpec defines the STACKIT agent configuration for a server
@@ -127,6 +248,38 @@ type BootVolumeSpec struct {
 	Source *BootVolumeSourceSpec `json:"source,omitempty"`
 }
 
+// DataVolumeSpec defines a data volume that is created per machine
+type DataVolumeSpec struct {
+	// Name identifies the data volume among the machine's data volumes
+	// Required field. Must be unique within dataVolumes, the STACKIT volume is named "<machine name>-<name>".
+	Name string `json:"name"`
+
+	// Size is the volume size in GB
+	// Required field.
+	Size int `json:"size"`
+
+	// PerformanceClass defines the performance tier for the volume
+	// Optional field. If not specified, the STACKIT default performance class is used.
+	PerformanceClass string `json:"performanceClass,omitempty"`
+
+	// SnapshotID is the UUID of a snapshot to create the volume from
+	// Optional field. If not specified, an empty volume is created.
+	SnapshotID string `json:"snapshotId,omitempty"`
+
+	// DeleteOnTermination controls whether the volume is deleted when the machine is deleted
+	// Optional field. Defaults to true.
+	DeleteOnTermination *bool `json:"deleteOnTermination,omitempty"`
+}
+
+// PublicIPSpec defines how the public IP of a machine is obtained
+// An empty PublicIPSpec allocates a new public IP for every machine
+type PublicIPSpec struct {
+	// PoolLabels selects a free public IP from a pool of existing public IPs carrying all of these labels
+	// Optional field. If not specified, a new public IP is allocated per machine and deleted with it.
+	// Pool IPs are returned to the pool instead of being deleted, an exhausted pool fails the machine creation.
+	PoolLabels map[string]string `json:"poolLabels,omitempty"`
+}
+
 // BootVolumeSourceSpec defines the source for creating a boot volume
 // Can be an image, snapshot, or existing volume
 type BootVolumeSourceSpec struct {
@@ -0,0 +1,61 @@
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/install"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("ProviderSpec", func() {
+	var (
+		scheme    *runtime.Scheme
+		versioned *v1alpha1.ProviderSpec
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		install.Install(scheme)
+		versioned = &v1alpha1.ProviderSpec{
+			Region:      "eu01",
+			MachineType: "c2i.2",
+			ImageID:     "12345678-1234-1234-1234-123456789abc",
+			Labels:      map[string]string{"team": "a"},
+			Networking:  &v1alpha1.NetworkingSpec{NICIDs: []string{"nic-1", "nic-2"}},
+			BootVolume:  &v1alpha1.BootVolumeSpec{Size: 50, Source: &v1alpha1.BootVolumeSourceSpec{Type: "image", ID: "image-1"}},
+			DataVolumes: []v1alpha1.DataVolumeSpec{{Name: "data", Size: 10, DeleteOnTermination: new(false)}},
+			PublicIP:    &v1alpha1.PublicIPSpec{PoolLabels: map[string]string{"pool": "egress"}},
+			Agent:       &v1alpha1.AgentSpec{Provisioned: new(true)},
+			Metadata:    v1alpha1.Metadata{"env": map[string]any{"stage": "prod"}, "tags": []any{"a", "b"}},
+		}
+	})
+
+	It("should convert to the internal version and back without losing fields", func() {
+		internal := &api.ProviderSpec{}
+		Expect(scheme.Convert(versioned, internal, nil)).To(Succeed())
+		Expect(internal.Networking.NICIDs).To(Equal([]string{"nic-1", "nic-2"}))
+		Expect(internal.Metadata).To(HaveKeyWithValue("tags", []any{"a", "b"}))
+
+		roundTripped := &v1alpha1.ProviderSpec{}
+		Expect(scheme.Convert(internal, roundTripped, nil)).To(Succeed())
+		Expect(roundTripped).To(Equal(versioned))
+	})
+
+	It("should default deleteOnTermination without overriding explicit values", func() {
+		scheme.Default(versioned)
+
+		Expect(versioned.BootVolume.DeleteOnTermination).To(Equal(new(true)))
+		Expect(versioned.DataVolumes[0].DeleteOnTermination).To(Equal(new(false)))
+	})
+
+	It("should deep copy the metadata", func() {
+		copied := versioned.DeepCopy()
+		copied.Metadata["env"].(map[string]any)["stage"] = "dev"
+		copied.Metadata["tags"].([]any)[0] = "c"
+
+		Expect(versioned.Metadata["env"]).To(HaveKeyWithValue("stage", "prod"))
+		Expect(versioned.Metadata["tags"]).To(Equal([]any{"a", "b"}))
+	})
+})
@@ -0,0 +1,25 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// addDefaultingFuncs registers the generated defaulters with the scheme
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	return RegisterDefaults(scheme)
+}
+
+// SetDefaults_BootVolumeSpec deletes the boot volume with the server unless configured otherwise
+// DeleteOnTermination defaults to false in the IaaS API, which produces many orphaned volumes since node rolls happen frequently in k8s
+func SetDefaults_BootVolumeSpec(obj *BootVolumeSpec) { //nolint:revive,stylecheck // name required by defaulter-gen
+	if obj.DeleteOnTermination == nil {
+		obj.DeleteOnTermination = new(true)
+	}
+}
+
+// SetDefaults_DataVolumeSpec deletes data volumes with the machine unless configured otherwise
+func SetDefaults_DataVolumeSpec(obj *DataVolumeSpec) { //nolint:revive,stylecheck // name required by defaulter-gen
+	if obj.DeleteOnTermination == nil {
+		obj.DeleteOnTermination = new(true)
+	}
+}
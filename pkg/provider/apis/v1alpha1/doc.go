@@ -0,0 +1,7 @@
+// Package v1alpha1 contains the v1alpha1 version of the STACKIT ProviderSpec API
+//
+// +k8s:deepcopy-gen=package
+// +k8s:conversion-gen=github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis
+// +k8s:defaulter-gen=TypeMeta
+// +groupName=machine.stackit.cloud
+package v1alpha1
@@ -0,0 +1,243 @@
+package v1alpha1
+
+import (
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProviderSpec is the v1alpha1 ProviderSpec of a STACKIT MachineClass
+// A ProviderSpec without apiVersion and kind is decoded as this version.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ProviderSpec struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Region is the STACKIT region (e.g., "eu01", "eu02")
+	// Required field for creating a server.
+	Region string `json:"region"`
+
+	// MachineType is the STACKIT server type (e.g., "c2i.2", "m2i.8")
+	// Required field for creating a server, unless MachineTypes is specified.
+	MachineType string `json:"machineType"`
+
+	// MachineTypes is an ordered list of acceptable STACKIT server types
+	// Optional field. Mutually exclusive with MachineType.
+	// If the platform has no capacity left for a type, the server is created with the next type in the list.
+	MachineTypes []string `json:"machineTypes,omitempty"`
+
+	// ImageID is the UUID of the OS image to use for the server
+	// Required field for creating a server, unless Image or BootVolume.Source is specified.
+	ImageID string `json:"imageId"`
+
+	// Image selects the OS image by name and filters instead of a fixed UUID
+	// Optional field. Mutually exclusive with ImageID.
+	// The newest matching image is resolved through the IaaS API and recorded in the server labels.
+	Image *ImageSelectorSpec `json:"image,omitempty"`
+
+	// Labels are key-value pairs used to tag and identify servers
+	// Used by MCM for mapping servers to MachineClasses and orphan VM detection
+	// Optional field. MCM will automatically add standard labels.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Networking configuration for the server
+	// Specify either a NetworkID (simple) or NICIDs (advanced)
+	// Optional field. If not specified, the server may use default networking or require manual configuration.
+	Networking *NetworkingSpec `json:"networking,omitempty"`
+
+	// AllowedAddresses are the IP address ranges (CIDRs) allowed to originate traffic from the server's network interface.
+	// Optional field. If specified, these ranges are configured as AllowedAddresses on the network interface of the server to bypass anti-spoofing rules.
+	AllowedAddresses []string `json:"allowedAddresses,omitempty"`
+
+	// SecurityGroups are the UUIDs of security groups to attach to the server
+	// Optional field. If not specified, the project's default security group will be used.
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+
+	// UserData is cloud-init script or user data for VM bootstrapping
+	// Optional field. Can be used to override Secret.userData for this MachineClass.
+	// If specified, takes precedence over Secret.userData.
+	// Note: Secret.userData is typically required by MCM for node bootstrapping.
+	UserData string `json:"userData,omitempty"`
+
+	// BootVolume defines detailed boot disk configuration
+	// Optional field. If not specified, a boot volume will be created from ImageID with default settings.
+	// If specified, provides fine-grained control over boot disk size, performance, and lifecycle.
+	BootVolume *BootVolumeSpec `json:"bootVolume,omitempty"`
+
+	// Volumes are UUIDs of existing volumes to attach to the server
+	// Optional field. Allows attaching additional data volumes beyond the boot disk.
+	Volumes []string `json:"volumes,omitempty"`
+
+	// DataVolumes are volumes created for every machine and attached to its server
+	// Optional field. Unlike Volumes, every machine gets its own set of volumes, which makes them usable in MachineDeployments.
+	// The volumes are labelled with the machine and MachineClass labels and deleted with the machine unless deleteOnTermination is false.
+	DataVolumes []DataVolumeSpec `json:"dataVolumes,omitempty"`
+
+	// PublicIP assigns a public IPv4 to every machine, associated with the primary NIC of its server
+	// Optional field. If not specified, servers are only reachable through their private addresses.
+	// The public IP is reported as NodeExternalIP and released or returned to its pool when the machine is deleted.
+	PublicIP *PublicIPSpec `json:"publicIP,omitempty"`
+
+	// KeypairName is the name of the SSH keypair for server access
+	// Optional field. If specified, the public key will be injected into the server for SSH access.
+	// The keypair must already exist in the STACKIT project.
+	KeypairName string `json:"keypairName,omitempty"`
+
+	// AvailabilityZone is the availability zone where the server will be created
+	// Optional field. If not specified:
+	// - If an existing volume is used as boot volume, the server will be created in the same AZ as the volume
+	// - For requests with no volumes, it will be set to the metro availability zone
+	// Example values: "eu01-1", "eu01-2"
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// AvailabilityZones is a list of candidate availability zones
+	// Optional field. Mutually exclusive with AvailabilityZone.
+	// Machines are spread over the zones, a zone without capacity is skipped in favour of the next one.
+	// Existing volumes are bound to a single zone and cannot be combined with this field.
+	// Example: ["eu01-1", "eu01-2", "eu01-3"]
+	AvailabilityZones []string `json:"availabilityZones,omitempty"`
+
+	// AffinityGroup is the UUID of the affinity group to associate with the server
+	// Optional field. Affinity groups control server placement for performance or availability requirements
+	// The affinity group must already exist in the STACKIT project
+	// Example: "880e8400-e29b-41d4-a716-446655440000"
+	AffinityGroup string `json:"affinityGroup,omitempty"`
+
+	// ServiceAccountMails are email addresses of service accounts to associate with the server
+	// Optional field. Service accounts provide identity and access management for the server
+	// Service accounts must already exist in the STACKIT project
+	// Note: STACKIT API currently limits this to a maximum of 1 service account per server
+	// Example: ["my-service@sa.stackit.cloud"]
+	ServiceAccountMails []string `json:"serviceAccountMails,omitempty"`
+
+	// Agent configures the STACKIT agent on the server
+	// Optional field. The STACKIT agent provides monitoring and management capabilities
+	// If not specified, defaults to the STACKIT platform default behavior
+	Agent *AgentSpec `json:"agent,omitempty"`
+
+	// Metadata is a generic JSON object for storing arbitrary key-value pairs
+	// Optional field. Can be used to store custom metadata that doesn't fit into other fields
+	// Example: {"environment": "production", "cost-center": "12345"}
+	Metadata Metadata `json:"metadata,omitempty"`
+
+	// ShutdownGracePeriod is the time the server gets to shut down cleanly before it is deleted
+	// Optional field. If set, DeleteMachine stops the server and waits up to this period for it to be stopped
+	// If not specified, the server is deleted right away
+	// Example: "2m"
+	ShutdownGracePeriod *metav1.Duration `json:"shutdownGracePeriod,omitempty"`
+}
+
+// Metadata is a free-form JSON object
+// It is the internal type, so both versions share its hand-written deep copy
+type Metadata = api.Metadata
+
+// ImageSelectorSpec selects an OS image by name and filters
+// If several images match, the one with the highest OS version is used, ties are broken by creation time
+type ImageSelectorSpec struct {
+	// Name is the name of the image
+	// Required field. Must match the image name exactly.
+	Name string `json:"name"`
+
+	// Version is a semantic version constraint on the OS version of the image
+	// Optional field. Example: ">= 22.04, < 24", images without OS version never match a constraint.
+	Version string `json:"version,omitempty"`
+
+	// Distro is the OS distribution of the image
+	// Optional field. Example: "ubuntu", "debian"
+	Distro string `json:"distro,omitempty"`
+
+	// Architecture is the CPU architecture of the image
+	// Optional field. Example: "x86", "arm64"
+	Architecture string `json:"architecture,omitempty"`
+
+	// Scope limits the search to public images or to images of the project
+	// Optional field. One of "public" or "project", both are searched if not specified.
+	Scope string `json:"scope,omitempty"`
+}
+
+// AgentSpec defines the STACKIT agent configuration for a server
+type AgentSpec struct {
+	// Provisioned controls whether the STACKIT agent is installed on the server
+	// Optional field. Set to true to install the agent, false to skip installation
+	Provisioned *bool `json:"provisioned,omitempty"`
+}
+
+// NetworkingSpec defines the network configuration for a server
+// Use either NetworkID for simple single-network attachment,
+// or NICIDs for advanced multi-NIC configuration (not both)
+type NetworkingSpec struct {
+	// NetworkID is the UUID of the network to attach the server to
+	// Simple variant: Server will be attached to this network with auto-configured NIC
+	// Mutually exclusive with NICIDs
+	NetworkID string `json:"networkId,omitempty"`
+
+	// NICIDs are the UUIDs of pre-created Network Interface Cards to attach
+	// Advanced variant: Allows fine-grained control over NICs, IPs, and security groups
+	// Mutually exclusive with NetworkID
+	NICIDs []string `json:"nicIds,omitempty"`
+}
+
+// BootVolumeSpec defines the boot disk configuration for a server
+// Provides detailed control over boot volume size, performance, and lifecycle
+type BootVolumeSpec struct {
+	// DeleteOnTermination controls whether the boot volume is deleted when the server is terminated
+	// Optional field. Defaults to true (volume deleted with server).
+	DeleteOnTermination *bool `json:"deleteOnTermination,omitempty"`
+
+	// PerformanceClass defines the performance tier for the boot volume
+	// Optional field. Examples: "standard", "premium", "fast" (depends on STACKIT offerings)
+	PerformanceClass string `json:"performanceClass,omitempty"`
+
+	// Size is the boot volume size in GB
+	// Optional field. If not specified, size is determined from the image.
+	// Must be >= image size if specified.
+	Size int `json:"size,omitempty"`
+
+	// Source defines where to create the boot volume from
+	// Optional field. If not specified, uses ImageID from ProviderSpec.
+	// Allows creating boot volume from snapshots or existing volumes.
+	Source *BootVolumeSourceSpec `json:"source,omitempty"`
+}
+
+// DataVolumeSpec defines a data volume that is created per machine
+type DataVolumeSpec struct {
+	// Name identifies the data volume among the machine's data volumes
+	// Required field. Must be unique within dataVolumes, the STACKIT volume is named "<machine name>-<name>".
+	Name string `json:"name"`
+
+	// Size is the volume size in GB
+	// Required field.
+	Size int `json:"size"`
+
+	// PerformanceClass defines the performance tier for the volume
+	// Optional field. If not specified, the STACKIT default performance class is used.
+	PerformanceClass string `json:"performanceClass,omitempty"`
+
+	// SnapshotID is the UUID of a snapshot to create the volume from
+	// Optional field. If not specified, an empty volume is created.
+	SnapshotID string `json:"snapshotId,omitempty"`
+
+	// DeleteOnTermination controls whether the volume is deleted when the machine is deleted
+	// Optional field. Defaults to true.
+	DeleteOnTermination *bool `json:"deleteOnTermination,omitempty"`
+}
+
+// PublicIPSpec defines how the public IP of a machine is obtained
+// An empty PublicIPSpec allocates a new public IP for every machine
+type PublicIPSpec struct {
+	// PoolLabels selects a free public IP from a pool of existing public IPs carrying all of these labels
+	// Optional field. If not specified, a new public IP is allocated per machine and deleted with it.
+	// Pool IPs are returned to the pool instead of being deleted, an exhausted pool fails the machine creation.
+	PoolLabels map[string]string `json:"poolLabels,omitempty"`
+}
+
+// BootVolumeSourceSpec defines the source for creating a boot volume
+// Can be an image, snapshot, or existing volume
+type BootVolumeSourceSpec struct {
+	// Type is the source type: "image", "snapshot", or "volume"
+	// Required field when Source is specified.
+	Type string `json:"type"`
+
+	// ID is the UUID of the source (image/snapshot/volume)
+	// Required field when Source is specified.
+	ID string `json:"id"`
+}
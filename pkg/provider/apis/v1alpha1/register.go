@@ -0,0 +1,31 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the API group of the ProviderSpec
+const GroupName = "machine.stackit.cloud"
+
+// SchemeGroupVersion is the version of the API group implemented by this package
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+var (
+	// SchemeBuilder registers the v1alpha1 types, their defaults and conversions
+	SchemeBuilder      runtime.SchemeBuilder
+	localSchemeBuilder = &SchemeBuilder
+	// AddToScheme adds the v1alpha1 types to a scheme
+	AddToScheme = localSchemeBuilder.AddToScheme
+)
+
+func init() {
+	// The generated conversions and defaulters register themselves in their init functions
+	localSchemeBuilder.Register(addKnownTypes, addDefaultingFuncs)
+}
+
+// addKnownTypes registers the v1alpha1 types with the scheme
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion, &ProviderSpec{})
+	return nil
+}
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"net"
 	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 const (
@@ -38,6 +42,12 @@ var regionRegex = regexp.MustCompile(`^[a-z0-9]+$`)
 // Pattern: lowercase letters/digits followed by dash, then digits or letter 'm' (e.g., eu01-1, eu01-2, eu01-m)
 var availabilityZoneRegex = regexp.MustCompile(`^[a-z0-9]+-(?:\d+|m)$`)
 
+// imageScopes are the allowed values of the image selector scope, empty selects both
+var imageScopes = []string{api.ImageScopePublic, api.ImageScopeProject}
+
+// bootVolumeSourceTypes are the allowed values of the boot volume source type
+var bootVolumeSourceTypes = []string{"image", "snapshot", "volume"}
+
 // labelKeyRegex validates Kubernetes label keys (must start/end with alphanumeric, can contain -, _, ., /)
 // Maximum length: 63 characters
 var labelKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9_./]*[a-zA-Z0-9])?$`)
@@ -47,88 +57,122 @@ var labelKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9_./]*[a-zA-Z0-9]
 var labelValueRegex = regexp.MustCompile(`^([a-zA-Z0-9]([-a-zA-Z0-9_.]*[a-zA-Z0-9])?)?$`)
 
 // ValidateProviderSpecNSecret validates provider spec and secret to check if all fields are present and valid
-//
-//nolint:gocyclo,funlen // splitting this function would make it unreadable
-func ValidateProviderSpecNSecret(spec *api.ProviderSpec, secrets *corev1.Secret) []error {
-	var errors []error
-
+// All errors are returned with the path of the offending field, e.g. providerSpec.bootVolume.source.id
+func ValidateProviderSpecNSecret(spec *api.ProviderSpec, secrets *corev1.Secret) field.ErrorList {
 	// Validate Secret
 	if secrets == nil {
-		errors = append(errors, fmt.Errorf("secret is required"))
-		return errors // Return early if secret is nil
+		return field.ErrorList{field.Required(field.NewPath("secret"), "")} // Return early if secret is nil
 	}
 
+	errors := ValidateSecret(secrets)
+	errors = append(errors, ValidateProviderSpec(spec)...)
+	return errors
+}
+
+// ValidateSecret validates the credentials in the Secret of a MachineClass
+func ValidateSecret(secrets *corev1.Secret) field.ErrorList {
+	var errors field.ErrorList
+	secretData := field.NewPath("secret", "data")
+
+	projectIDPath := secretData.Key(StackitProjectIDSecretKey)
 	projectID, ok := secrets.Data[StackitProjectIDSecretKey]
 	if !ok {
-		errors = append(errors, fmt.Errorf("secret field '%s' is required", StackitProjectIDSecretKey))
+		errors = append(errors, field.Required(projectIDPath, ""))
 	} else if len(projectID) == 0 {
-		errors = append(errors, fmt.Errorf("secret field '%s' cannot be empty", StackitProjectIDSecretKey))
+		errors = append(errors, field.Required(projectIDPath, "cannot be empty"))
 	} else if !isValidUUID(string(projectID)) {
-		errors = append(errors, fmt.Errorf("secret field '%s' must be a valid UUID", StackitProjectIDSecretKey))
+		errors = append(errors, field.Invalid(projectIDPath, string(projectID), "must be a valid UUID"))
 	}
 
 	// Validate serviceAccountKey (required for authentication)
 	// ServiceAccount Key Flow: JSON string containing service account credentials and private key
+	serviceAccountKeyPath := secretData.Key(StackitServiceAccountKey)
 	serviceAccountKey, ok := secrets.Data[StackitServiceAccountKey]
 	if !ok {
-		errors = append(errors, fmt.Errorf("secret field '%s' is required", StackitServiceAccountKey))
+		errors = append(errors, field.Required(serviceAccountKeyPath, ""))
 	} else if len(serviceAccountKey) == 0 {
-		errors = append(errors, fmt.Errorf("secret field '%s' cannot be empty", StackitServiceAccountKey))
+		errors = append(errors, field.Required(serviceAccountKeyPath, "cannot be empty"))
 	} else if !isValidJSON(string(serviceAccountKey)) {
-		errors = append(errors, fmt.Errorf("secret field 'serviceAccountKey' must be valid JSON (service account credentials)"))
+		// the value holds the private key and must not end up in events or logs
+		errors = append(errors, field.Invalid(serviceAccountKeyPath, field.OmitValueType{}, "must be valid JSON (service account credentials)"))
 	}
 
+	return errors
+}
+
+// ValidateProviderSpec validates the ProviderSpec of a MachineClass without its Secret
+//
+//nolint:gocyclo,funlen // splitting this function would make it unreadable
+func ValidateProviderSpec(spec *api.ProviderSpec) field.ErrorList {
+	var errors field.ErrorList
+	root := field.NewPath("providerSpec")
+
 	// Validate region (required for SDK)
 	if spec.Region == "" {
-		errors = append(errors, fmt.Errorf("providerSpec.Region cannot be empty"))
+		errors = append(errors, field.Required(root.Child("region"), ""))
 	} else if !isValidRegion(spec.Region) {
-		errors = append(errors, fmt.Errorf("providerSpec.Region has invalid format (expected format: eu01, eu02, etc.)"))
+		errors = append(errors, field.Invalid(root.Child("region"), spec.Region, "has invalid format (expected format: eu01, eu02, etc.)"))
 	}
 
 	if spec.AvailabilityZone != "" && !isValidAvailabilityZone(spec.AvailabilityZone) {
-		errors = append(errors, fmt.Errorf("providerSpec.availabilityZone has invalid format (expected format: eu01-1, eu01-2, etc.)"))
+		errors = append(errors, field.Invalid(root.Child("availabilityZone"), spec.AvailabilityZone, "has invalid format (expected format: eu01-1, eu01-2, etc.)"))
+	}
+	if len(spec.AvailabilityZones) > 0 {
+		errors = append(errors, validateAvailabilityZones(root, spec)...)
 	}
 
 	// Validate ProviderSpec
-	if spec.MachineType == "" {
-		errors = append(errors, fmt.Errorf("providerSpec.machineType is required"))
-	} else if !isValidMachineType(spec.MachineType) {
-		errors = append(errors, fmt.Errorf("providerSpec.machineType has invalid format (expected format: c2i.2, m2i.8, etc.)"))
+	switch {
+	case spec.MachineType == "" && len(spec.MachineTypes) == 0:
+		errors = append(errors, field.Required(root.Child("machineType"), "machineType or machineTypes is required"))
+	case spec.MachineType != "" && len(spec.MachineTypes) > 0:
+		errors = append(errors, field.Forbidden(root.Child("machineTypes"), "machineType and machineTypes are mutually exclusive"))
+	case spec.MachineType != "" && !isValidMachineType(spec.MachineType):
+		errors = append(errors, field.Invalid(root.Child("machineType"), spec.MachineType, "has invalid format (expected format: c2i.2, m2i.8, etc.)"))
 	}
+	errors = append(errors, validateMachineTypes(root.Child("machineTypes"), spec.MachineTypes)...)
 
-	// ImageID is required unless BootVolume.Source is specified
+	// ImageID is required unless Image or BootVolume.Source is specified
 	hasBootVolumeSource := spec.BootVolume != nil && spec.BootVolume.Source != nil
-	if spec.ImageID == "" && !hasBootVolumeSource {
-		errors = append(errors, fmt.Errorf("providerSpec.imageId or bootVolume.source is required"))
+	if spec.ImageID == "" && spec.Image == nil && !hasBootVolumeSource {
+		errors = append(errors, field.Required(root.Child("imageId"), "image, imageId or bootVolume.source is required"))
 	}
 	// Validate ImageID format if specified
 	if spec.ImageID != "" && !isValidUUID(spec.ImageID) {
-		errors = append(errors, fmt.Errorf("providerSpec.imageId must be a valid UUID"))
+		errors = append(errors, field.Invalid(root.Child("imageId"), spec.ImageID, "must be a valid UUID"))
+	}
+	// Validate Image selector if specified
+	if spec.Image != nil {
+		if spec.ImageID != "" {
+			errors = append(errors, field.Forbidden(root.Child("image"), "imageId and image are mutually exclusive"))
+		}
+		errors = append(errors, validateImageSelector(root.Child("image"), spec.Image)...)
 	}
 
 	// Validate Labels
 	if spec.Labels != nil {
 		for key, value := range spec.Labels {
+			path := root.Child("labels").Key(key)
 			if len(key) > 63 {
-				errors = append(errors, fmt.Errorf("providerSpec.labels key '%s' exceeds maximum length of 63 characters", key))
+				errors = append(errors, field.Invalid(path, key, "key exceeds maximum length of 63 characters"))
 			}
 			if !labelKeyRegex.MatchString(key) {
-				errors = append(errors, fmt.Errorf("providerSpec.labels key '%s' has invalid format (must start/end with alphanumeric, can contain -, _, ., /)", key))
+				errors = append(errors, field.Invalid(path, key, "key has invalid format (must start/end with alphanumeric, can contain -, _, ., /)"))
 			}
 			if len(value) > 63 {
-				errors = append(errors, fmt.Errorf("providerSpec.labels value for key '%s' exceeds maximum length of 63 characters", key))
+				errors = append(errors, field.Invalid(path, value, "value exceeds maximum length of 63 characters"))
 			}
 			if !labelValueRegex.MatchString(value) {
-				errors = append(errors, fmt.Errorf("providerSpec.labels value for key '%s' has invalid format (must start/end with alphanumeric, can contain -, _, ., can be empty)", key))
+				errors = append(errors, field.Invalid(path, value, "value has invalid format (must start/end with alphanumeric, can contain -, _, ., can be empty)"))
 			}
 		}
 	}
 
 	// Validate Networking (required)
 	if spec.Networking == nil {
-		errors = append(errors, fmt.Errorf("providerSpec.networking is required"))
+		errors = append(errors, field.Required(root.Child("networking"), ""))
 	} else {
-		networkingErrors := validateNetworking(spec.Networking)
+		networkingErrors := validateNetworking(root.Child("networking"), spec.Networking)
 		errors = append(errors, networkingErrors...)
 	}
 
@@ -136,43 +180,54 @@ func ValidateProviderSpecNSecret(spec *api.ProviderSpec, secrets *corev1.Secret)
 	if len(spec.SecurityGroups) > 0 {
 		for i, sg := range spec.SecurityGroups {
 			if sg == "" {
-				errors = append(errors, fmt.Errorf("providerSpec.securityGroups[%d] cannot be empty", i))
+				errors = append(errors, field.Required(root.Child("securityGroups").Index(i), "cannot be empty"))
 			}
 		}
 	}
 
 	// Validate BootVolume
 	if spec.BootVolume != nil {
-		bootVolumeErrors := validateBootVolume(spec.BootVolume)
+		bootVolumeErrors := validateBootVolume(root.Child("bootVolume"), spec.BootVolume)
 		errors = append(errors, bootVolumeErrors...)
 	}
 
 	// Validate Volumes
 	if len(spec.Volumes) > 0 {
 		for i, volumeID := range spec.Volumes {
+			path := root.Child("volumes").Index(i)
 			if volumeID == "" {
-				errors = append(errors, fmt.Errorf("providerSpec.volumes[%d] cannot be empty", i))
+				errors = append(errors, field.Required(path, "cannot be empty"))
 			} else if !isValidUUID(volumeID) {
-				errors = append(errors, fmt.Errorf("providerSpec.volumes[%d] must be a valid UUID", i))
+				errors = append(errors, field.Invalid(path, volumeID, "must be a valid UUID"))
 			}
 		}
 	}
 
+	// Validate DataVolumes
+	if len(spec.DataVolumes) > 0 {
+		errors = append(errors, validateDataVolumes(root.Child("dataVolumes"), spec.DataVolumes)...)
+	}
+
+	// Validate PublicIP
+	if spec.PublicIP != nil {
+		errors = append(errors, validatePublicIP(root.Child("publicIP"), spec.PublicIP)...)
+	}
+
 	// Validate KeypairName
 	if spec.KeypairName != "" {
 		if len(spec.KeypairName) > 127 {
-			errors = append(errors, fmt.Errorf("providerSpec.keypairName exceeds maximum length of 127 characters"))
+			errors = append(errors, field.TooLong(root.Child("keypairName"), spec.KeypairName, 127))
 		}
 		if !keypairNameRegex.MatchString(spec.KeypairName) {
-			errors = append(errors, fmt.Errorf("providerSpec.keypairName contains invalid characters (allowed: A-Za-z0-9@._-)"))
+			errors = append(errors, field.Invalid(root.Child("keypairName"), spec.KeypairName, "contains invalid characters (allowed: A-Za-z0-9@._-)"))
 		}
 	}
 
 	// Validate AllowedAddresses
 	if len(spec.AllowedAddresses) > 0 {
-		for _, cidr := range spec.AllowedAddresses {
+		for i, cidr := range spec.AllowedAddresses {
 			if _, _, err := net.ParseCIDR(cidr); err != nil {
-				errors = append(errors, fmt.Errorf("providerSpec.allowedAddresses has an invalid CIDR: %s", cidr))
+				errors = append(errors, field.Invalid(root.Child("allowedAddresses").Index(i), cidr, "must be a valid CIDR block"))
 			}
 		}
 	}
@@ -180,25 +235,31 @@ func ValidateProviderSpecNSecret(spec *api.ProviderSpec, secrets *corev1.Secret)
 	// Validate AffinityGroup
 	if spec.AffinityGroup != "" {
 		if !isValidUUID(spec.AffinityGroup) {
-			errors = append(errors, fmt.Errorf("providerSpec.affinityGroup must be a valid UUID"))
+			errors = append(errors, field.Invalid(root.Child("affinityGroup"), spec.AffinityGroup, "must be a valid UUID"))
 		}
 	}
 
 	// Validate ServiceAccountMails
 	if len(spec.ServiceAccountMails) > 0 {
+		path := root.Child("serviceAccountMails")
 		// STACKIT API currently limits to 1 service account per server
 		if len(spec.ServiceAccountMails) > 1 {
-			errors = append(errors, fmt.Errorf("providerSpec.serviceAccountMails can contain a maximum of 1 service account (STACKIT API constraint)"))
+			errors = append(errors, field.TooMany(path, len(spec.ServiceAccountMails), 1))
 		}
 		for i, email := range spec.ServiceAccountMails {
 			if email == "" {
-				errors = append(errors, fmt.Errorf("providerSpec.serviceAccountMails[%d] cannot be empty", i))
+				errors = append(errors, field.Required(path.Index(i), "cannot be empty"))
 			} else if !isValidEmail(email) {
-				errors = append(errors, fmt.Errorf("providerSpec.serviceAccountMails[%d] must be a valid email address", i))
+				errors = append(errors, field.Invalid(path.Index(i), email, "must be a valid email address"))
 			}
 		}
 	}
 
+	// Validate ShutdownGracePeriod
+	if spec.ShutdownGracePeriod != nil && spec.ShutdownGracePeriod.Duration < 0 {
+		errors = append(errors, field.Invalid(root.Child("shutdownGracePeriod"), spec.ShutdownGracePeriod.Duration.String(), "must not be negative"))
+	}
+
 	// Validate Agent
 	// Agent is optional with no specific constraints - just a boolean flag
 	// No validation needed as any value (nil, true, false) is acceptable
@@ -211,27 +272,27 @@ func ValidateProviderSpecNSecret(spec *api.ProviderSpec, secrets *corev1.Secret)
 }
 
 // validateNetworking validates the NetworkingSpec
-func validateNetworking(networking *api.NetworkingSpec) []error {
-	var errors []error
+func validateNetworking(path *field.Path, networking *api.NetworkingSpec) field.ErrorList {
+	var errors field.ErrorList
 
 	hasNetworkID := networking.NetworkID != ""
 	hasNICIDs := len(networking.NICIDs) > 0
 
 	// Either NetworkID or NICIDs must be set, but not both
 	if !hasNetworkID && !hasNICIDs {
-		errors = append(errors, fmt.Errorf("providerSpec.networking must specify either networkId or nicIds"))
+		errors = append(errors, field.Required(path, "must specify either networkId or nicIds"))
 		return errors
 	}
 
 	if hasNetworkID && hasNICIDs {
-		errors = append(errors, fmt.Errorf("providerSpec.networking cannot specify both networkId and nicIds (mutually exclusive)"))
+		errors = append(errors, field.Forbidden(path.Child("nicIds"), "cannot specify both networkId and nicIds (mutually exclusive)"))
 		return errors
 	}
 
 	// Validate NetworkID format if specified
 	if hasNetworkID {
 		if !isValidUUID(networking.NetworkID) {
-			errors = append(errors, fmt.Errorf("providerSpec.networking.networkId must be a valid UUID"))
+			errors = append(errors, field.Invalid(path.Child("networkId"), networking.NetworkID, "must be a valid UUID"))
 		}
 	}
 
@@ -239,9 +300,9 @@ func validateNetworking(networking *api.NetworkingSpec) []error {
 	if hasNICIDs {
 		for i, nicID := range networking.NICIDs {
 			if nicID == "" {
-				errors = append(errors, fmt.Errorf("providerSpec.networking.nicIds[%d] cannot be empty", i))
+				errors = append(errors, field.Required(path.Child("nicIds").Index(i), "cannot be empty"))
 			} else if !isValidUUID(nicID) {
-				errors = append(errors, fmt.Errorf("providerSpec.networking.nicIds[%d] must be a valid UUID", i))
+				errors = append(errors, field.Invalid(path.Child("nicIds").Index(i), nicID, "must be a valid UUID"))
 			}
 		}
 	}
@@ -250,34 +311,149 @@ func validateNetworking(networking *api.NetworkingSpec) []error {
 }
 
 // validateBootVolume validates the BootVolumeSpec
-func validateBootVolume(bootVolume *api.BootVolumeSpec) []error {
-	var errors []error
+func validateBootVolume(path *field.Path, bootVolume *api.BootVolumeSpec) field.ErrorList {
+	var errors field.ErrorList
 
 	// Validate size if specified
 	if bootVolume.Size < 0 {
-		errors = append(errors, fmt.Errorf("providerSpec.bootVolume.size must be positive or zero"))
+		errors = append(errors, field.Invalid(path.Child("size"), bootVolume.Size, "must be positive or zero"))
 	}
 
 	// Validate source if specified
 	if bootVolume.Source != nil {
+		sourcePath := path.Child("source")
 		if bootVolume.Source.Type == "" {
-			errors = append(errors, fmt.Errorf("providerSpec.bootVolume.source.type is required when source is specified"))
-		} else {
-			// Validate source type is one of the allowed values
-			validSourceTypes := map[string]bool{
-				"image":    true,
-				"snapshot": true,
-				"volume":   true,
-			}
-			if !validSourceTypes[bootVolume.Source.Type] {
-				errors = append(errors, fmt.Errorf("providerSpec.bootVolume.source type must be one of: image, snapshot, volume"))
-			}
+			errors = append(errors, field.Required(sourcePath.Child("type"), "required when source is specified"))
+		} else if !slices.Contains(bootVolumeSourceTypes, bootVolume.Source.Type) {
+			errors = append(errors, field.NotSupported(sourcePath.Child("type"), bootVolume.Source.Type, bootVolumeSourceTypes))
 		}
 
 		if bootVolume.Source.ID == "" {
-			errors = append(errors, fmt.Errorf("providerSpec.bootVolume.source.id is required when source is specified"))
+			errors = append(errors, field.Required(sourcePath.Child("id"), "required when source is specified"))
 		} else if !isValidUUID(bootVolume.Source.ID) {
-			errors = append(errors, fmt.Errorf("providerSpec.bootVolume.source.id must be a valid UUID"))
+			errors = append(errors, field.Invalid(sourcePath.Child("id"), bootVolume.Source.ID, "must be a valid UUID"))
+		}
+	}
+
+	return errors
+}
+
+// validateDataVolumes validates the DataVolumeSpecs
+func validateDataVolumes(path *field.Path, dataVolumes []api.DataVolumeSpec) field.ErrorList {
+	var errors field.ErrorList
+
+	names := make(map[string]bool, len(dataVolumes))
+	for i, dataVolume := range dataVolumes {
+		namePath := path.Index(i).Child("name")
+		// the name ends up in the volume name and in a volume label
+		switch {
+		case dataVolume.Name == "":
+			errors = append(errors, field.Required(namePath, ""))
+		case len(dataVolume.Name) > 63 || !labelValueRegex.MatchString(dataVolume.Name):
+			errors = append(errors, field.Invalid(namePath, dataVolume.Name, "has invalid format (max. 63 characters, must start/end with alphanumeric, can contain -, _, .)"))
+		case names[dataVolume.Name]:
+			errors = append(errors, field.Duplicate(namePath, dataVolume.Name))
+		}
+		names[dataVolume.Name] = true
+
+		if dataVolume.Size <= 0 {
+			errors = append(errors, field.Invalid(path.Index(i).Child("size"), dataVolume.Size, "must be positive"))
+		}
+
+		if dataVolume.SnapshotID != "" && !isValidUUID(dataVolume.SnapshotID) {
+			errors = append(errors, field.Invalid(path.Index(i).Child("snapshotId"), dataVolume.SnapshotID, "must be a valid UUID"))
+		}
+	}
+
+	return errors
+}
+
+// validateAvailabilityZones validates the availability zone candidate list
+// Servers may be created in any of the zones, so the ProviderSpec must not reference resources bound to a single zone
+func validateAvailabilityZones(root *field.Path, spec *api.ProviderSpec) field.ErrorList {
+	var errors field.ErrorList
+
+	path := root.Child("availabilityZones")
+	if spec.AvailabilityZone != "" {
+		errors = append(errors, field.Forbidden(path, "availabilityZone and availabilityZones are mutually exclusive"))
+	}
+
+	seen := make(map[string]bool, len(spec.AvailabilityZones))
+	for i, zone := range spec.AvailabilityZones {
+		switch {
+		case !isValidAvailabilityZone(zone):
+			errors = append(errors, field.Invalid(path.Index(i), zone, "has invalid format (expected format: eu01-1, eu01-2, etc.)"))
+		case spec.Region != "" && !strings.HasPrefix(zone, spec.Region+"-"):
+			errors = append(errors, field.Invalid(path.Index(i), zone, fmt.Sprintf("is not in region '%s'", spec.Region)))
+		}
+		if seen[zone] {
+			errors = append(errors, field.Duplicate(path.Index(i), zone))
+		}
+		seen[zone] = true
+	}
+
+	// existing volumes can only be attached to servers in their own zone
+	if len(spec.Volumes) > 0 {
+		errors = append(errors, field.Forbidden(root.Child("volumes"), "cannot be used with availabilityZones, existing volumes are bound to a single zone"))
+	}
+	if spec.BootVolume != nil && spec.BootVolume.Source != nil && spec.BootVolume.Source.Type == "volume" {
+		errors = append(errors, field.Forbidden(root.Child("bootVolume", "source", "type"), "type 'volume' cannot be used with availabilityZones, existing volumes are bound to a single zone"))
+	}
+
+	return errors
+}
+
+// validateMachineTypes validates the machine type fallback list
+func validateMachineTypes(path *field.Path, machineTypes []string) field.ErrorList {
+	var errors field.ErrorList
+
+	seen := make(map[string]bool, len(machineTypes))
+	for i, machineType := range machineTypes {
+		if !isValidMachineType(machineType) {
+			errors = append(errors, field.Invalid(path.Index(i), machineType, "has invalid format (expected format: c2i.2, m2i.8, etc.)"))
+		}
+		if seen[machineType] {
+			errors = append(errors, field.Duplicate(path.Index(i), machineType))
+		}
+		seen[machineType] = true
+	}
+
+	return errors
+}
+
+// validateImageSelector validates the ImageSelectorSpec
+func validateImageSelector(path *field.Path, image *api.ImageSelectorSpec) field.ErrorList {
+	var errors field.ErrorList
+
+	if image.Name == "" {
+		errors = append(errors, field.Required(path.Child("name"), ""))
+	}
+
+	if image.Version != "" {
+		if _, err := semver.NewConstraint(image.Version); err != nil {
+			errors = append(errors, field.Invalid(path.Child("version"), image.Version, fmt.Sprintf("must be a valid version constraint: %v", err)))
+		}
+	}
+
+	if image.Scope != "" && !slices.Contains(imageScopes, image.Scope) {
+		errors = append(errors, field.NotSupported(path.Child("scope"), image.Scope, imageScopes))
+	}
+
+	return errors
+}
+
+// validatePublicIP validates the PublicIPSpec
+func validatePublicIP(path *field.Path, publicIP *api.PublicIPSpec) field.ErrorList {
+	var errors field.ErrorList
+
+	for key, value := range publicIP.PoolLabels {
+		labelPath := path.Child("poolLabels").Key(key)
+		if len(key) > 63 || !labelKeyRegex.MatchString(key) {
+			errors = append(errors, field.Invalid(labelPath, key, "key has invalid format (max. 63 characters, must start/end with alphanumeric, can contain -, _, ., /)"))
+		}
+		// an empty value would also match released pool IPs of other selectors
+		if value == "" || len(value) > 63 || !labelValueRegex.MatchString(value) {
+			errors = append(errors, field.Invalid(labelPath, value, "value has invalid format (1-63 characters, must start/end with alphanumeric, can contain -, _, .)"))
 		}
 	}
 
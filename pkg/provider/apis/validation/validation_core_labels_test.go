@@ -6,6 +6,7 @@ import (
 	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
 	. "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/validation"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 var _ = Describe("ValidateProviderSpecNSecret", func() {
@@ -38,6 +39,46 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			Expect(errors).To(BeEmpty())
 		})
 
+		It("should succeed with a machine type fallback list instead of MachineType", func() {
+			providerSpec.MachineType = ""
+			providerSpec.MachineTypes = []string{"c3i.2", "c2i.2"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should return all errors with their field paths", func() {
+			providerSpec.MachineType = "InvalidFormat"
+			providerSpec.ImageID = ""
+			providerSpec.BootVolume = &api.BootVolumeSpec{Source: &api.BootVolumeSourceSpec{Type: "image"}}
+			providerSpec.Image = &api.ImageSelectorSpec{Name: "ubuntu", Scope: "shared"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(3))
+			Expect(errors[0].Field).To(Equal("providerSpec.machineType"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+			Expect(errors[1].Field).To(Equal("providerSpec.image.scope"))
+			Expect(errors[1].Type).To(Equal(field.ErrorTypeNotSupported))
+			Expect(errors[2].Field).To(Equal("providerSpec.bootVolume.source.id"))
+			Expect(errors[2].Type).To(Equal(field.ErrorTypeRequired))
+		})
+
+		It("should fail when both MachineType and MachineTypes are set", func() {
+			providerSpec.MachineTypes = []string{"c3i.2"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("machineType and machineTypes are mutually exclusive"))
+		})
+
+		It("should fail when MachineTypes has invalid or duplicate entries", func() {
+			providerSpec.MachineType = ""
+			providerSpec.MachineTypes = []string{"c3i.2", "Invalid", "c3i.2"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(2))
+			Expect(errors[0].Field).To(Equal("providerSpec.machineTypes[1]"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+			Expect(errors[1].Field).To(Equal("providerSpec.machineTypes[2]"))
+			Expect(errors[1].Type).To(Equal(field.ErrorTypeDuplicate))
+		})
+
 		It("should fail when MachineType is empty", func() {
 			providerSpec.MachineType = ""
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
@@ -49,7 +90,8 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			providerSpec.MachineType = "InvalidFormat"
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
 			Expect(errors).NotTo(BeEmpty())
-			Expect(errors[0].Error()).To(ContainSubstring("machineType has invalid format"))
+			Expect(errors[0].Field).To(Equal("providerSpec.machineType"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
 		})
 
 		It("should succeed when MachineType has valid format", func() {
@@ -69,7 +111,47 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			providerSpec.ImageID = "invalid-uuid"
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
 			Expect(errors).NotTo(BeEmpty())
-			Expect(errors[0].Error()).To(ContainSubstring("imageId must be a valid UUID"))
+			Expect(errors[0].Field).To(Equal("providerSpec.imageId"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+		})
+
+		It("should succeed with an image selector instead of ImageID", func() {
+			providerSpec.ImageID = ""
+			providerSpec.Image = &api.ImageSelectorSpec{Name: "ubuntu-22.04", Version: ">= 22.04, < 24", Scope: "public"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should fail when both ImageID and an image selector are set", func() {
+			providerSpec.Image = &api.ImageSelectorSpec{Name: "ubuntu-22.04"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("imageId and image are mutually exclusive"))
+		})
+
+		It("should fail when the image selector has no name", func() {
+			providerSpec.ImageID = ""
+			providerSpec.Image = &api.ImageSelectorSpec{Distro: "ubuntu"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Field).To(Equal("providerSpec.image.name"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeRequired))
+		})
+
+		It("should fail when the image version constraint is invalid", func() {
+			providerSpec.ImageID = ""
+			providerSpec.Image = &api.ImageSelectorSpec{Name: "ubuntu-22.04", Version: "latest"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("providerSpec.image.version"))
+		})
+
+		It("should fail when the image scope is unknown", func() {
+			providerSpec.ImageID = ""
+			providerSpec.Image = &api.ImageSelectorSpec{Name: "ubuntu-22.04", Scope: "organization"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("providerSpec.image.scope"))
 		})
 
 		It("should fail when both required fields are empty", func() {
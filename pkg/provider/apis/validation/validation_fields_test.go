@@ -1,11 +1,15 @@
 package validation_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
 	. "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/validation"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 var _ = Describe("ValidateProviderSpecNSecret", func() {
@@ -65,7 +69,8 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			}
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
 			Expect(errors).NotTo(BeEmpty())
-			Expect(errors[0].Error()).To(ContainSubstring("maximum length"))
+			Expect(errors[0].Field).To(Equal("providerSpec.keypairName"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeTooLong))
 		})
 	})
 
@@ -79,7 +84,8 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 		It("should fail when region is empty", func() {
 			providerSpec.Region = ""
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
-			Expect(errors[0].Error()).To(ContainSubstring("Region cannot be empty"))
+			Expect(errors[0].Field).To(Equal("providerSpec.region"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeRequired))
 		})
 
 		It("should succeed with various region formats", func() {
@@ -105,7 +111,8 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			for _, r := range testCases {
 				providerSpec.Region = r
 				errors := ValidateProviderSpecNSecret(providerSpec, secret)
-				Expect(errors[0].Error()).To(ContainSubstring("Region has invalid format"))
+				Expect(errors[0].Field).To(Equal("providerSpec.region"))
+				Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
 			}
 		})
 	})
@@ -157,6 +164,48 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 		})
 	})
 
+	Context("AvailabilityZones validation", func() {
+		It("should succeed with valid availabilityZones", func() {
+			providerSpec.AvailabilityZones = []string{"eu01-1", "eu01-2", "eu01-3"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should fail when availabilityZone is set as well", func() {
+			providerSpec.AvailabilityZone = "eu01-1"
+			providerSpec.AvailabilityZones = []string{"eu01-1", "eu01-2"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("availabilityZone and availabilityZones are mutually exclusive"))
+		})
+
+		It("should fail with invalid, foreign or duplicate zones", func() {
+			providerSpec.AvailabilityZones = []string{"eu01-a", "eu02-1", "eu01-1", "eu01-1"}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(3))
+			Expect(errors[0].Field).To(Equal("providerSpec.availabilityZones[0]"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+			Expect(errors[1].Field).To(Equal("providerSpec.availabilityZones[1]"))
+			Expect(errors[1].Type).To(Equal(field.ErrorTypeInvalid))
+			Expect(errors[2].Field).To(Equal("providerSpec.availabilityZones[3]"))
+			Expect(errors[2].Type).To(Equal(field.ErrorTypeDuplicate))
+		})
+
+		It("should fail with existing volumes bound to a single zone", func() {
+			providerSpec.AvailabilityZones = []string{"eu01-1", "eu01-2"}
+			providerSpec.Volumes = []string{"550e8400-e29b-41d4-a716-446655440000"}
+			providerSpec.BootVolume = &api.BootVolumeSpec{
+				Source: &api.BootVolumeSourceSpec{Type: "volume", ID: "660e8400-e29b-41d4-a716-446655440000"},
+			}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(2))
+			Expect(errors[0].Field).To(Equal("providerSpec.volumes"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeForbidden))
+			Expect(errors[1].Field).To(Equal("providerSpec.bootVolume.source.type"))
+			Expect(errors[1].Type).To(Equal(field.ErrorTypeForbidden))
+		})
+	})
+
 	Context("AffinityGroup validation", func() {
 		It("should succeed with valid affinityGroup UUID", func() {
 			providerSpec.AffinityGroup = "880e8400-e29b-41d4-a716-446655440000"
@@ -224,7 +273,8 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			}
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
 			Expect(errors).NotTo(BeEmpty())
-			Expect(errors[0].Error()).To(ContainSubstring("maximum of 1"))
+			Expect(errors[0].Field).To(Equal("providerSpec.serviceAccountMails"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeTooMany))
 		})
 	})
 
@@ -297,4 +347,20 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			Expect(errors).To(BeEmpty())
 		})
 	})
+
+	Context("ShutdownGracePeriod validation", func() {
+		It("should succeed with a positive shutdownGracePeriod", func() {
+			providerSpec.ShutdownGracePeriod = &metav1.Duration{Duration: 2 * time.Minute}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should fail with a negative shutdownGracePeriod", func() {
+			providerSpec.ShutdownGracePeriod = &metav1.Duration{Duration: -time.Second}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Field).To(Equal("providerSpec.shutdownGracePeriod"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+		})
+	})
 })
@@ -6,6 +6,7 @@ import (
 	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
 	. "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/validation"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 var _ = Describe("ValidateProviderSpecNSecret", func() {
@@ -53,7 +54,8 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			providerSpec.Networking = nil
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
 			Expect(errors).NotTo(BeEmpty())
-			Expect(errors[0].Error()).To(ContainSubstring("networking is required"))
+			Expect(errors[0].Field).To(Equal("providerSpec.networking"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeRequired))
 		})
 
 		It("should fail when Networking has neither NetworkID nor NICIDs", func() {
@@ -108,4 +110,40 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			Expect(errors[0].Error()).To(ContainSubstring("cannot be empty"))
 		})
 	})
+
+	Context("PublicIP validation", func() {
+		BeforeEach(func() {
+			providerSpec.Networking = &api.NetworkingSpec{
+				NetworkID: "550e8400-e29b-41d4-a716-446655440000",
+			}
+		})
+
+		It("should succeed with an empty PublicIP to allocate an IP per machine", func() {
+			providerSpec.PublicIP = &api.PublicIPSpec{}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should succeed with valid pool labels", func() {
+			providerSpec.PublicIP = &api.PublicIPSpec{PoolLabels: map[string]string{"pool": "edge"}}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should fail when a pool label value is empty", func() {
+			providerSpec.PublicIP = &api.PublicIPSpec{PoolLabels: map[string]string{"pool": ""}}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Field).To(Equal("providerSpec.publicIP.poolLabels[pool]"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+		})
+
+		It("should fail when a pool label key is invalid", func() {
+			providerSpec.PublicIP = &api.PublicIPSpec{PoolLabels: map[string]string{"-pool": "edge"}}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Field).To(Equal("providerSpec.publicIP.poolLabels[-pool]"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+		})
+	})
 })
@@ -6,6 +6,7 @@ import (
 	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
 	. "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/validation"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 var _ = Describe("ValidateProviderSpecNSecret", func() {
@@ -57,7 +58,8 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			secret.Data["project-id"] = []byte("invalid-uuid")
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
 			Expect(errors).NotTo(BeEmpty())
-			Expect(errors[0].Error()).To(ContainSubstring("project-id' must be a valid UUID"))
+			Expect(errors[0].Field).To(Equal("secret.data[project-id]"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
 		})
 
 		It("should fail when serviceaccount.json is missing from secret", func() {
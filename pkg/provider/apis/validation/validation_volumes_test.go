@@ -6,6 +6,7 @@ import (
 	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
 	. "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/validation"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 var _ = Describe("ValidateProviderSpecNSecret", func() {
@@ -71,7 +72,8 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			}
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
 			Expect(errors).NotTo(BeEmpty())
-			Expect(errors[0].Error()).To(ContainSubstring("source type must be"))
+			Expect(errors[0].Field).To(Equal("providerSpec.bootVolume.source.type"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeNotSupported))
 		})
 
 		It("should fail when BootVolume source is missing type", func() {
@@ -114,7 +116,8 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			}
 			errors := ValidateProviderSpecNSecret(providerSpec, secret)
 			Expect(errors).NotTo(BeEmpty())
-			Expect(errors[0].Error()).To(ContainSubstring("size must be positive"))
+			Expect(errors[0].Field).To(Equal("providerSpec.bootVolume.size"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
 		})
 
 		It("should succeed with valid Volumes array", func() {
@@ -179,5 +182,54 @@ var _ = Describe("ValidateProviderSpecNSecret", func() {
 			Expect(errors).NotTo(BeEmpty())
 			Expect(errors[0].Error()).To(ContainSubstring("imageId or bootVolume.source"))
 		})
+
+		It("should succeed with valid DataVolumes", func() {
+			providerSpec.DataVolumes = []api.DataVolumeSpec{
+				{Name: "data", Size: 100, PerformanceClass: "storage_premium_perf4"},
+				{Name: "wal", Size: 20, SnapshotID: "550e8400-e29b-41d4-a716-446655440000"},
+			}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should fail when a DataVolume has no name", func() {
+			providerSpec.DataVolumes = []api.DataVolumeSpec{{Size: 10}}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("providerSpec.dataVolumes[0].name"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeRequired))
+		})
+
+		It("should fail when a DataVolume name has invalid format", func() {
+			providerSpec.DataVolumes = []api.DataVolumeSpec{{Name: "-data", Size: 10}}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("providerSpec.dataVolumes[0].name"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+		})
+
+		It("should fail when DataVolume names are not unique", func() {
+			providerSpec.DataVolumes = []api.DataVolumeSpec{{Name: "data", Size: 10}, {Name: "data", Size: 20}}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("providerSpec.dataVolumes[1].name"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeDuplicate))
+		})
+
+		It("should fail when a DataVolume has no size", func() {
+			providerSpec.DataVolumes = []api.DataVolumeSpec{{Name: "data"}}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("providerSpec.dataVolumes[0].size"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+		})
+
+		It("should fail when a DataVolume snapshotId is not a UUID", func() {
+			providerSpec.DataVolumes = []api.DataVolumeSpec{{Name: "data", Size: 10, SnapshotID: "snapshot"}}
+			errors := ValidateProviderSpecNSecret(providerSpec, secret)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("providerSpec.dataVolumes[0].snapshotId"))
+			Expect(errors[0].Type).To(Equal(field.ErrorTypeInvalid))
+		})
 	})
 })
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	client2 "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"k8s.io/klog/v2"
+)
+
+// clientFactory creates a STACKIT client for the given service account key
+// It is a variable on the cache so tests can inject mock clients
+type clientFactory func(serviceAccountKey string) (client2.StackitClient, error)
+
+// newSdkClientFactory returns the default clientFactory backed by the STACKIT SDK
+// Transient failures are retried with backoff as configured, by default only for idempotent calls
+func newSdkClientFactory(config client2.RetryConfig) clientFactory {
+	return func(serviceAccountKey string) (client2.StackitClient, error) {
+		c, err := client2.NewStackitClient(serviceAccountKey)
+		if err != nil {
+			return nil, err
+		}
+		return client2.NewRetryingStackitClient(c, config), nil
+	}
+}
+
+const (
+	// defaultMaxClients bounds the number of cached clients, the least recently used client is evicted beyond it
+	defaultMaxClients = 64
+	// defaultClientIdleTTL drops clients whose credentials were not used for a while, e.g. after a key rotation
+	defaultClientIdleTTL = time.Hour
+)
+
+// clientCacheEntry is a cached client and when it was last used
+type clientCacheEntry struct {
+	client   client2.StackitClient
+	lastUsed time.Time
+}
+
+// clientCache holds STACKIT clients keyed by a hash of the credentials they were built from
+//
+// Design: Credential rotation without pod restart
+// - Clients are keyed by sha256(projectID, serviceAccountKey), the raw key is never stored
+// - When the Secret content changes, a new client is built on the next request
+// - Clients are evicted per credential, MachineClasses with other credentials for the same project keep theirs
+// - Clients not used for idleTTL are dropped, beyond maxClients the least recently used client is dropped
+type clientCache struct {
+	mu         sync.Mutex
+	clients    map[string]*clientCacheEntry // credential hash -> client
+	maxClients int
+	idleTTL    time.Duration
+	newClient  clientFactory
+	now        func() time.Time // injectable for tests
+}
+
+// newClientCache returns an empty clientCache using the given factory
+func newClientCache(factory clientFactory) *clientCache {
+	return &clientCache{
+		clients:    make(map[string]*clientCacheEntry),
+		maxClients: defaultMaxClients,
+		idleTTL:    defaultClientIdleTTL,
+		newClient:  factory,
+		now:        time.Now,
+	}
+}
+
+// get returns the client for the given credentials, creating it if needed
+// Thread-safe: the mutex is held during client creation so concurrent
+// requests with the same credentials share a single client
+func (c *clientCache) get(projectID, serviceAccountKey string) (client2.StackitClient, error) {
+	key := credentialsHash(projectID, serviceAccountKey)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictIdle(now)
+
+	if entry, ok := c.clients[key]; ok {
+		entry.lastUsed = now
+		return entry.client, nil
+	}
+
+	cl, err := c.newClient(serviceAccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize STACKIT client: %w", err)
+	}
+
+	if len(c.clients) >= c.maxClients {
+		c.evictLeastRecentlyUsed()
+	}
+	c.clients[key] = &clientCacheEntry{client: cl, lastUsed: now}
+	klog.V(2).Infof("Built STACKIT client for new credentials of project %q", projectID)
+
+	return cl, nil
+}
+
+// evictIdle drops the clients not used for idleTTL, the caller holds the mutex
+func (c *clientCache) evictIdle(now time.Time) {
+	for key, entry := range c.clients {
+		if now.Sub(entry.lastUsed) > c.idleTTL {
+			delete(c.clients, key)
+		}
+	}
+}
+
+// evictLeastRecentlyUsed drops the client used longest ago, the caller holds the mutex
+func (c *clientCache) evictLeastRecentlyUsed() {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.clients {
+		if oldestKey == "" || entry.lastUsed.Before(oldest) {
+			oldestKey, oldest = key, entry.lastUsed
+		}
+	}
+	delete(c.clients, oldestKey)
+}
+
+// len returns the number of cached clients
+func (c *clientCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.clients)
+}
+
+// credentialsHash returns a hex encoded sha256 hash of the project ID and service account key
+func credentialsHash(projectID, serviceAccountKey string) string {
+	h := sha256.New()
+	h.Write([]byte(projectID))
+	h.Write([]byte{0})
+	h.Write([]byte(serviceAccountKey))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	client2 "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("clientCache", func() {
+	var (
+		cache        *clientCache
+		createdKeys  []string
+		factoryError error
+		now          time.Time
+	)
+
+	BeforeEach(func() {
+		createdKeys = nil
+		factoryError = nil
+		cache = newClientCache(func(serviceAccountKey string) (client2.StackitClient, error) {
+			if factoryError != nil {
+				return nil, factoryError
+			}
+			createdKeys = append(createdKeys, serviceAccountKey)
+			return &mock.StackitClient{}, nil
+		})
+		now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		cache.now = func() time.Time { return now }
+	})
+
+	It("should reuse the client for identical credentials", func() {
+		c1, err := cache.get("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+		c2, err := cache.get("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c2).To(BeIdenticalTo(c1))
+		Expect(createdKeys).To(HaveLen(1))
+	})
+
+	It("should build a new client when the key is rotated and evict the old one once it is idle", func() {
+		c1, err := cache.get("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+		c2, err := cache.get("project-1", `{"key":"b"}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c2).NotTo(BeIdenticalTo(c1))
+		Expect(createdKeys).To(Equal([]string{`{"key":"a"}`, `{"key":"b"}`}))
+		Expect(cache.len()).To(Equal(2))
+
+		now = now.Add(defaultClientIdleTTL / 2)
+		_, err = cache.get("project-1", `{"key":"b"}`)
+		Expect(err).NotTo(HaveOccurred())
+		now = now.Add(defaultClientIdleTTL/2 + time.Minute)
+		_, err = cache.get("project-1", `{"key":"b"}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cache.len()).To(Equal(1))
+		Expect(cache.clients).To(HaveKey(credentialsHash("project-1", `{"key":"b"}`)))
+	})
+
+	It("should keep the clients of different credentials for the same project", func() {
+		c1, err := cache.get("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.get("project-1", `{"key":"b"}`)
+		Expect(err).NotTo(HaveOccurred())
+		c3, err := cache.get("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c3).To(BeIdenticalTo(c1))
+		Expect(createdKeys).To(HaveLen(2))
+	})
+
+	It("should evict the least recently used client beyond the maximum", func() {
+		cache.maxClients = 2
+		for _, key := range []string{`{"key":"a"}`, `{"key":"b"}`, `{"key":"a"}`, `{"key":"c"}`} {
+			now = now.Add(time.Second)
+			_, err := cache.get("project-1", key)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(cache.len()).To(Equal(2))
+		Expect(cache.clients).To(HaveKey(credentialsHash("project-1", `{"key":"a"}`)))
+		Expect(cache.clients).NotTo(HaveKey(credentialsHash("project-1", `{"key":"b"}`)))
+	})
+
+	It("should keep the old client if creating the new one fails", func() {
+		c1, err := cache.get("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		factoryError = fmt.Errorf("invalid key")
+		_, err = cache.get("project-1", `{"key":"b"}`)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid key"))
+
+		factoryError = nil
+		c2, err := cache.get("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c2).To(BeIdenticalTo(c1))
+	})
+
+	It("should keep clients of different projects side by side", func() {
+		c1, err := cache.get("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+		c2, err := cache.get("project-2", `{"key":"b"}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c2).NotTo(BeIdenticalTo(c1))
+		Expect(cache.len()).To(Equal(2))
+	})
+
+	It("should not store the raw service account key", func() {
+		_, err := cache.get("project-1", `{"key":"secret"}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		for key := range cache.clients {
+			Expect(key).NotTo(ContainSubstring("secret"))
+			Expect(key).To(Equal(credentialsHash("project-1", `{"key":"secret"}`)))
+		}
+	})
+})
+
+var _ = Describe("Provider.getClient", func() {
+	It("should switch to a new client when the credentials change", func() {
+		provider := &Provider{
+			clients: newClientCache(func(_ string) (client2.StackitClient, error) {
+				return &mock.StackitClient{}, nil
+			}),
+		}
+
+		first, err := provider.getClient("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := provider.getClient("project-1", `{"key":"b"}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(first).NotTo(BeNil())
+		Expect(second).NotTo(BeIdenticalTo(first))
+	})
+
+	It("should return the static client when one is set", func() {
+		mockClient := &mock.StackitClient{}
+		provider := &Provider{client: mockClient}
+
+		c, err := provider.getClient("project-1", `{"key":"a"}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).To(BeIdenticalTo(mockClient))
+	})
+
+	It("should serve MachineClasses of different projects with their own credentials", func() {
+		projectsByKey := map[string][]string{}
+		provider := &Provider{
+			clients: newClientCache(func(serviceAccountKey string) (client2.StackitClient, error) {
+				return &mock.StackitClient{
+					ListServersFunc: func(_ context.Context, projectID, _ string, _ map[string]string) ([]*client2.Server, error) {
+						projectsByKey[serviceAccountKey] = append(projectsByKey[serviceAccountKey], projectID)
+						return []*client2.Server{{ID: "server-" + projectID}}, nil
+					},
+				}, nil
+			}),
+		}
+
+		providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{Region: "eu01"})
+		newRequest := func(projectID, serviceAccountKey string) *driver.ListMachinesRequest {
+			return &driver.ListMachinesRequest{
+				MachineClass: &v1alpha1.MachineClass{
+					ObjectMeta:   metav1.ObjectMeta{Name: "class-" + projectID},
+					ProviderSpec: runtime.RawExtension{Raw: providerSpecRaw},
+				},
+				Secret: &corev1.Secret{Data: map[string][]byte{
+					"project-id":          []byte(projectID),
+					"serviceaccount.json": []byte(serviceAccountKey),
+				}},
+			}
+		}
+
+		respA, err := provider.ListMachines(context.Background(), newRequest("project-a", `{"key":"a"}`))
+		Expect(err).NotTo(HaveOccurred())
+		respB, err := provider.ListMachines(context.Background(), newRequest("project-b", `{"key":"b"}`))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = provider.ListMachines(context.Background(), newRequest("project-a", `{"key":"a"}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(respA.MachineList).To(HaveKey("stackit://project-a/server-project-a"))
+		Expect(respB.MachineList).To(HaveKey("stackit://project-b/server-project-b"))
+		Expect(projectsByKey).To(Equal(map[string][]string{
+			`{"key":"a"}`: {"project-a", "project-a"},
+			`{"key":"b"}`: {"project-b"},
+		}))
+		Expect(provider.clients.len()).To(Equal(2))
+	})
+})
+
+var _ = Describe("newSdkClientFactory", func() {
+	It("should wrap the SDK client with the configured retries", func() {
+		GinkgoT().Setenv("STACKIT_NO_AUTH", "true")
+
+		provider := NewProvider(nil, WithRetryConfig(client2.RetryConfig{MaxAttempts: 2, RetryMutating: true})).(*Provider)
+		Expect(provider.retryConfig).To(Equal(client2.RetryConfig{MaxAttempts: 2, RetryMutating: true}))
+
+		c, err := provider.getClient("project-1", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).To(BeAssignableToTypeOf(&client2.RetryingStackitClient{}))
+	})
+
+	It("should not retry mutating calls by default", func() {
+		provider := NewProvider(nil).(*Provider)
+
+		Expect(provider.retryConfig).To(Equal(client2.DefaultRetryConfig()))
+		Expect(provider.retryConfig.RetryMutating).To(BeFalse())
+	})
+})
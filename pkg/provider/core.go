@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"slices"
 
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
@@ -13,22 +14,67 @@ const (
 	StackitProviderName      = "stackit"
 	StackitMachineLabel      = "kubernetes.io/machine"
 	StackitMachineClassLabel = "kubernetes.io/machineclass"
+	// StackitMachineUIDLabel records the UID of the Machine a server was created for
+	// A Machine recreated with the same name gets a new UID, so servers of the earlier Machine are not adopted
+	StackitMachineUIDLabel = "kubernetes.io/machine-uid"
+	// StackitRecreateAttemptsLabel counts how often the server of a machine was recreated after being in ERROR state
+	StackitRecreateAttemptsLabel = "kubernetes.io/recreate-attempts"
+	// StackitDataVolumeLabel marks a volume as data volume of a machine, the value is the name of the ProviderSpec data volume
+	StackitDataVolumeLabel = "kubernetes.io/data-volume"
+	// StackitDeleteOnTerminationLabel records whether a data volume is deleted along with its machine
+	StackitDeleteOnTerminationLabel = "kubernetes.io/delete-on-termination"
+	// StackitPublicIPPoolLabel marks a public IP that was taken from a pool, it is returned to the pool instead of being deleted
+	StackitPublicIPPoolLabel = "kubernetes.io/public-ip-pool"
+	// StackitMachineTypeLabel records the machine type the server was created with
+	StackitMachineTypeLabel = "kubernetes.io/machine-type"
+	// StackitImageIDLabel records the image resolved from the image selector of the MachineClass
+	StackitImageIDLabel = "kubernetes.io/image-id"
+)
+
+// DefaultCSIDriverNames are the CSI drivers recognised by GetVolumeIDs unless configured otherwise
+var DefaultCSIDriverNames = []string{"block-storage.csi.stackit.cloud"}
+
+// STACKIT server states the provider acts on
+const (
+	serverStatusCreating = "CREATING"
+	serverStatusActive   = "ACTIVE"
+	serverStatusError    = "ERROR"
+	serverStatusDeleting = "DELETING"
+)
+
+var (
+	// stoppedServerStatuses are server states in which the server is shut off
+	stoppedServerStatuses = []string{"INACTIVE", "STOPPED", "SHUTOFF", "DEALLOCATED"}
+	// stoppedPowerStatuses are power states of a server that does not run
+	stoppedPowerStatuses = []string{"STOPPED", "CRASHED"}
 )
 
 // GetVolumeIDs extracts volume IDs from PersistentVolume specs
 //
-// This method is used by MCM to get volume IDs for persistent volumes.
-// Currently unimplemented for STACKIT provider - volumes are managed directly
-// through the ProviderSpec (bootVolume and volumes fields).
+// MCM uses the volume IDs during drain to wait until the volumes of evicted pods are
+// detached from the node before the machine is deleted. Only volumes provisioned by one
+// of the configured CSI drivers (see WithCSIDriverNames) are STACKIT block storage volumes,
+// the volume handle of those is the STACKIT volume ID. Volumes of other drivers are ignored.
 //
 // Returns:
-//   - Unimplemented: This functionality is not required for STACKIT provider
+//   - VolumeIDs: STACKIT volume IDs of the given PersistentVolume specs
 func (p *Provider) GetVolumeIDs(_ context.Context, req *driver.GetVolumeIDsRequest) (*driver.GetVolumeIDsResponse, error) {
 	// Log messages to track start and end of request
 	klog.V(2).Infof("GetVolumeIDs request has been received for %q", req.PVSpecs)
 	defer klog.V(2).Infof("GetVolumeIDs request has been processed successfully for %q", req.PVSpecs)
 
-	return &driver.GetVolumeIDsResponse{}, status.Error(codes.Unimplemented, "")
+	volumeIDs := make([]string, 0, len(req.PVSpecs))
+	for _, spec := range req.PVSpecs {
+		if spec == nil || spec.CSI == nil || spec.CSI.VolumeHandle == "" {
+			continue
+		}
+		if !slices.Contains(p.csiDriverNames, spec.CSI.Driver) {
+			continue
+		}
+		volumeIDs = append(volumeIDs, spec.CSI.VolumeHandle)
+	}
+
+	return &driver.GetVolumeIDsResponse{VolumeIDs: volumeIDs}, nil
 }
 
 // GenerateMachineClassForMigration generates a MachineClass for migration purposes
@@ -48,8 +94,3 @@ func (p *Provider) GenerateMachineClassForMigration(_ context.Context, req *driv
 
 	return &driver.GenerateMachineClassForMigrationResponse{}, status.Error(codes.Unimplemented, "")
 }
-
-// InitializeMachine handles VM initialization for STACKIT VM's. Currently, un-implemented.
-func (p *Provider) InitializeMachine(context.Context, *driver.InitializeMachineRequest) (*driver.InitializeMachineResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "STACKIT Provider does not yet implement InitializeMachine")
-}
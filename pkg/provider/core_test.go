@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("GetVolumeIDs", func() {
+	var (
+		ctx      context.Context
+		provider *Provider
+	)
+
+	csiVolume := func(driverName, handle string) *corev1.PersistentVolumeSpec {
+		return &corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: driverName, VolumeHandle: handle},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		provider = NewProvider(nil).(*Provider)
+	})
+
+	It("should return volume IDs of STACKIT block storage volumes", func() {
+		resp, err := provider.GetVolumeIDs(ctx, &driver.GetVolumeIDsRequest{
+			PVSpecs: []*corev1.PersistentVolumeSpec{
+				csiVolume("block-storage.csi.stackit.cloud", "volume-1"),
+				csiVolume("block-storage.csi.stackit.cloud", "volume-2"),
+			},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.VolumeIDs).To(Equal([]string{"volume-1", "volume-2"}))
+	})
+
+	It("should ignore volumes of other drivers and non-CSI volumes", func() {
+		resp, err := provider.GetVolumeIDs(ctx, &driver.GetVolumeIDsRequest{
+			PVSpecs: []*corev1.PersistentVolumeSpec{
+				csiVolume("nfs.csi.k8s.io", "nfs-share"),
+				{PersistentVolumeSource: corev1.PersistentVolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/data"}}},
+				csiVolume("block-storage.csi.stackit.cloud", ""),
+				nil,
+				csiVolume("block-storage.csi.stackit.cloud", "volume-1"),
+			},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.VolumeIDs).To(Equal([]string{"volume-1"}))
+	})
+
+	It("should return an empty list for no volumes", func() {
+		resp, err := provider.GetVolumeIDs(ctx, &driver.GetVolumeIDsRequest{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.VolumeIDs).To(BeEmpty())
+	})
+
+	It("should use the configured driver names", func() {
+		provider = NewProvider(nil, WithCSIDriverNames("cinder.csi.openstack.org")).(*Provider)
+
+		resp, err := provider.GetVolumeIDs(ctx, &driver.GetVolumeIDsRequest{
+			PVSpecs: []*corev1.PersistentVolumeSpec{
+				csiVolume("block-storage.csi.stackit.cloud", "volume-1"),
+				csiVolume("cinder.csi.openstack.org", "volume-2"),
+			},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.VolumeIDs).To(Equal([]string{"volume-2"}))
+	})
+})
@@ -3,9 +3,12 @@ package provider
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"maps"
 	"slices"
+	"strconv"
+	"time"
 
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
@@ -16,26 +19,51 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/ptr"
 )
 
+// DefaultSchedulingTimeout is how long CreateMachine waits for a new server to leave CREATING state
+// Servers going into ERROR state within this time are replaced by CreateMachine
+const DefaultSchedulingTimeout = time.Minute
+
 // CreateMachine handles a machine creation request by creating a STACKIT server
 //
 // This method creates a new server in STACKIT infrastructure based on the ProviderSpec
 // configuration in the MachineClass. It assigns MCM-specific labels to the server for
 // tracking and orphan VM detection.
 //
+// CreateMachine returns once the server has left CREATING state or the scheduling timeout expired,
+// so MCM records the ProviderID early. Waiting for ACTIVE and post-boot configuration are done in InitializeMachine.
+//
 // Returns:
 //   - ProviderID: Unique identifier in format "stackit://<projectId>/<serverId>"
 //   - NodeName: Name that the VM will register with in Kubernetes (matches Machine name)
-//   - Addresses: Internal IP addresses of the server's NICs (NodeInternalIP)
+//   - Addresses: Internal IP addresses of the server's NICs if already known (NodeInternalIP)
+//     and the public IP of the machine if the ProviderSpec requests one (NodeExternalIP)
 //
 // Error codes (see machine_error_codes.md for retry semantics):
-//   - InvalidArgument (no retry): Invalid ProviderSpec fields or missing required values
+//   - InvalidArgument (no retry): Invalid ProviderSpec fields, missing required values, referenced resources that do not exist
+//     or do not fit together (preflight) or no image matching the image selector
 //   - Internal (no retry): Malformed ProviderSpec JSON or failed to initialize STACKIT client
-//   - Unavailable (retry): Transient API failure (create/get server, get NICs, patch NIC)
-//   - ResourceExhausted (no retry): No capacity available (e.g. "no valid host was found")
-//   - DeadlineExceeded (retry): Server did not reach ACTIVE state within the polling timeout
+//   - Unavailable (retry): Transient API failure (list/create server), rate limiting or STACKIT server errors
+//   - ResourceExhausted (no retry): No capacity available for any machine type (e.g. "no valid host was found"), project quota exceeded or public IP pool exhausted
+//   - Unauthenticated / PermissionDenied: Service account credentials rejected or missing permissions
+//   - Aborted (retry): Request conflicts with the current state of a resource
+//   - DeadlineExceeded (retry): A server in ERROR state was not deleted within the polling timeout
+//
+// A server in ERROR state never becomes ACTIVE. A new server going into ERROR state within the
+// scheduling timeout, or an existing one found by name, is deleted and replaced by a fresh server,
+// up to maxRecreateAttempts times. Once the attempts are used up, the server's error message is
+// returned with ResourceExhausted for capacity problems and Internal otherwise. MCM never calls
+// CreateMachine again once it recorded the ProviderID, servers failing later are reported by
+// GetMachineStatus and MCM replaces the Machine after its creation timeout.
+//
+// If the ProviderSpec lists several machineTypes, a type the platform has no capacity for is skipped
+// in favour of the next one, both when CreateServer rejects it and when the server fails with
+// "no valid host was found". The chosen type is recorded in the StackitMachineTypeLabel.
+//
+// If the ProviderSpec lists several availabilityZones, the zone hosting the fewest servers of the
+// MachineClass is tried first and the next zone is used when a zone has no capacity left.
+// Exhausted zones are remembered for a short time and tried last.
 func (p *Provider) CreateMachine(ctx context.Context, req *driver.CreateMachineRequest) (*driver.CreateMachineResponse, error) {
 	// Log messages to track request
 	klog.V(2).Infof("Machine creation request has been received for %q", req.Machine.Name)
@@ -53,51 +81,70 @@ func (p *Provider) CreateMachine(ctx context.Context, req *driver.CreateMachineR
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	// Validate ProviderSpec and Secret
-	validationErrs := validation.ValidateProviderSpecNSecret(providerSpec, req.Secret)
+	// Validate ProviderSpec and Secret, misspelled fields are reported together with invalid values
+	validationErrs := p.checkUnknownFields(req.MachineClass)
+	validationErrs = append(validationErrs, validation.ValidateProviderSpecNSecret(providerSpec, req.Secret)...)
 	if len(validationErrs) > 0 {
-		return nil, status.Error(codes.InvalidArgument, validationErrs[0].Error())
+		return nil, status.Error(codes.InvalidArgument, validationErrs.ToAggregate().Error())
 	}
 
 	// Extract credentials from Secret
 	projectID, serviceAccountKey := extractSecretCredentials(req.Secret.Data)
 
-	// Initialize client on first use (lazy initialization)
-	if err := p.ensureClient(serviceAccountKey); err != nil {
+	// Resolve client for the Secret credentials (created on first use or after rotation)
+	c, err := p.getClient(projectID, serviceAccountKey)
+	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to initialize STACKIT client: %v", err))
 	}
 
-	// check if server already exists
-	server, err := p.getServerByName(ctx, projectID, providerSpec.Region, req.Machine.Name)
+	// check if server already exists, the server of an existing ProviderID wins over duplicates
+	_, providerServerID, _ := parseProviderID(req.Machine.Spec.ProviderID)
+	server, err := getServerByName(ctx, c, projectID, providerSpec.Region, req.Machine.Name, string(req.Machine.UID), providerServerID)
 	if err != nil {
 		klog.Errorf("Failed to fetch server for machine %q: %v", req.Machine.Name, err)
-		return nil, status.Error(codes.Unavailable, fmt.Sprintf("failed to fetch server: %v", err))
+		return nil, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to fetch server: %v", err))
 	}
 
-	if server == nil {
-		// Call STACKIT API to create server
-		server, err = p.client.CreateServer(ctx, projectID, providerSpec.Region, p.createServerRequest(req, providerSpec))
+	// the referenced resources are checked once per MachineClass generation before a server is created
+	if server == nil || server.Status == serverStatusError {
+		preflightErrs, err := p.preflight.check(ctx, c, projectID, req.MachineClass, providerSpec)
 		if err != nil {
-			klog.Errorf("Failed to create server for machine %q: %v", req.Machine.Name, err)
-			if isResourceExhaustedError(err) {
-				return nil, status.Error(codes.ResourceExhausted, fmt.Sprintf("failed to create server: %v", err))
-			}
-			return nil, status.Error(codes.Unavailable, fmt.Sprintf("failed to create server: %v", err))
+			klog.Errorf("Failed to run preflight for machine %q: %v", req.Machine.Name, err)
+			return nil, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to run preflight: %v", err))
 		}
-	}
-
-	if err := p.WaitUntilServerRunning(ctx, projectID, providerSpec.Region, server.ID); err != nil {
-		klog.Errorf("Failed waiting for server %q to reach ACTIVE state: %v", req.Machine.Name, err)
-		if isResourceExhaustedError(err) {
-			return nil, status.Error(codes.ResourceExhausted, fmt.Sprintf("failed waiting for server to be ACTIVE: %v", err))
+		if len(preflightErrs) > 0 {
+			return nil, status.Error(codes.InvalidArgument, preflightErrs.ToAggregate().Error())
 		}
-		return nil, status.Error(codes.DeadlineExceeded, fmt.Sprintf("failed waiting for server to be ACTIVE: %v", err))
 	}
 
-	nics, err := p.patchNetworkInterfaces(ctx, projectID, server.ID, providerSpec)
+	// the public IP is obtained before the server, so its address can be reported right away
+	publicIP, err := ensurePublicIP(ctx, c, projectID, req.Machine.Name, req.MachineClass.Name, string(req.Machine.UID), providerSpec)
 	if err != nil {
-		klog.Errorf("Failed to patch NICs for server %q: %v", req.Machine.Name, err)
-		return nil, status.Error(codes.Unavailable, fmt.Sprintf("failed to patch NICs for server: %v", err))
+		klog.Errorf("Failed to set up public IP for machine %q: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to set up public IP: %v", err))
+	}
+
+	// a server in ERROR state is replaced by a fresh server, this is only possible until MCM recorded the ProviderID
+	machineTypes := machineTypesOf(providerSpec)
+	for server == nil || server.Status == serverStatusError {
+		recreateAttempt := 0
+		if server != nil {
+			recreateAttempt, err = p.deleteFailedServer(ctx, c, projectID, providerSpec.Region, req.Machine.Name, server)
+			if err != nil {
+				return nil, err
+			}
+			if client.ClassifyServerErrorMessage(server.ErrorMessage) != nil {
+				p.exhaustedZones.markExhausted(projectID, providerSpec.Region, server.AvailabilityZone, machineTypeOf(server))
+			}
+			machineTypes = fallbackMachineTypes(machineTypes, server)
+		}
+
+		server, err = p.createServer(ctx, c, projectID, req, providerSpec, machineTypes, recreateAttempt)
+		if err != nil {
+			return nil, err
+		}
+
+		server = p.waitForScheduling(ctx, c, projectID, providerSpec.Region, server)
 	}
 
 	// Generate ProviderID in format: stackit://<projectId>/<serverId>
@@ -107,10 +154,174 @@ func (p *Provider) CreateMachine(ctx context.Context, req *driver.CreateMachineR
 	return &driver.CreateMachineResponse{
 		ProviderID: providerID,
 		NodeName:   req.Machine.Name,
-		Addresses:  nicAddresses(nics),
+		Addresses:  withPublicIP(nicAddresses(server.NICs), publicIP),
 	}, nil
 }
 
+// createServer creates a new server for the machine and returns it, failures are returned as status errors
+// A recreate attempt above zero is recorded in the StackitRecreateAttemptsLabel.
+func (p *Provider) createServer(ctx context.Context, c client.StackitClient, projectID string, req *driver.CreateMachineRequest, providerSpec *api.ProviderSpec, machineTypes []string, recreateAttempt int) (*client.Server, error) {
+	// the image selector is resolved only for new servers, existing servers keep their image
+	if providerSpec.Image != nil {
+		imageID, err := p.images.resolve(ctx, c, projectID, providerSpec.Region, req.MachineClass.Name, providerSpec.Image)
+		if err != nil {
+			klog.Errorf("Failed to resolve image for machine %q: %v", req.Machine.Name, err)
+			code := errorCode(err, codes.Unavailable)
+			if errors.Is(err, errNoMatchingImage) {
+				code = codes.InvalidArgument
+			}
+			return nil, status.Error(code, fmt.Sprintf("failed to resolve image: %v", err))
+		}
+		providerSpec.ImageID = imageID
+	}
+
+	createReq := p.createServerRequest(req, providerSpec)
+	if recreateAttempt > 0 {
+		createReq.Labels[StackitRecreateAttemptsLabel] = strconv.Itoa(recreateAttempt)
+	}
+
+	// spread the machines of the MachineClass over the availability zones
+	zones, err := spreadZones(ctx, c, projectID, providerSpec.Region, req.MachineClass.Name, availabilityZonesOf(providerSpec))
+	if err != nil {
+		klog.Errorf("Failed to pick availability zone for machine %q: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to pick availability zone: %v", err))
+	}
+
+	// Call STACKIT API to create server
+	server, err := p.createServerWithFallback(ctx, c, projectID, providerSpec.Region, createReq, machineTypes, zones)
+	if err != nil {
+		klog.Errorf("Failed to create server for machine %q: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to create server: %v", err))
+	}
+	return server, nil
+}
+
+// waitForScheduling polls a new server until it leaves CREATING state or the scheduling timeout expires
+// Servers that find no host go into ERROR state shortly after they are accepted, so CreateMachine can
+// still replace them before MCM records the ProviderID. Servers that are still CREATING are returned
+// as they are and InitializeMachine waits for them. Lookup failures are only logged since the server exists.
+func (p *Provider) waitForScheduling(ctx context.Context, c client.StackitClient, projectID, region string, server *client.Server) *client.Server {
+	if p.schedulingTimeout <= 0 {
+		return server
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, p.pollingInterval, p.schedulingTimeout, false, func(ctx context.Context) (bool, error) {
+		current, err := c.GetServer(ctx, projectID, region, server.ID)
+		if err != nil {
+			klog.V(2).Infof("Failed to get server %q while waiting for it to be scheduled: %v", server.ID, err)
+			return false, nil
+		}
+		server = current
+		return server.Status != serverStatusCreating, nil
+	})
+	if err != nil {
+		klog.V(2).Infof("Server %q is still %s after %s, leaving it to InitializeMachine", server.ID, server.Status, p.schedulingTimeout)
+	}
+	return server
+}
+
+// deleteFailedServer deletes a server in ERROR state and waits until it is gone
+// Returns the recreate attempt of the replacement server, or a status error once
+// the server was recreated maxRecreateAttempts times
+func (p *Provider) deleteFailedServer(ctx context.Context, c client.StackitClient, projectID, region, machineName string, server *client.Server) (int, error) {
+	attempt := recreateAttempts(server) + 1
+	if attempt > p.maxRecreateAttempts {
+		klog.Errorf("Server %q for machine %q is in ERROR state after %d recreate attempts: %q", server.ID, machineName, attempt-1, server.ErrorMessage)
+		code := codes.Internal
+		if client.ClassifyServerErrorMessage(server.ErrorMessage) != nil {
+			code = codes.ResourceExhausted
+		}
+		return 0, status.Error(code, fmt.Sprintf("server %q is in ERROR state after %d recreate attempts: %q", server.ID, attempt-1, server.ErrorMessage))
+	}
+
+	klog.Warningf("Server %q for machine %q is in ERROR state (%q), recreating it (attempt %d/%d)", server.ID, machineName, server.ErrorMessage, attempt, p.maxRecreateAttempts)
+
+	if err := c.DeleteServer(ctx, projectID, region, server.ID); err != nil && !errors.Is(err, client.ErrNotFound) {
+		klog.Errorf("Failed to delete server %q in ERROR state for machine %q: %v", server.ID, machineName, err)
+		return 0, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to delete server in ERROR state (%q): %v", server.ErrorMessage, err))
+	}
+
+	if err := p.WaitUntilServerDeleted(ctx, c, projectID, region, server.ID); err != nil {
+		klog.Errorf("Failed waiting for server %q in ERROR state to be deleted for machine %q: %v", server.ID, machineName, err)
+		return 0, status.Error(errorCode(err, codes.DeadlineExceeded), fmt.Sprintf("failed waiting for server in ERROR state (%q) to be deleted: %v", server.ErrorMessage, err))
+	}
+
+	return attempt, nil
+}
+
+// machineTypesOf returns the machine types to try in order of preference
+func machineTypesOf(providerSpec *api.ProviderSpec) []string {
+	if len(providerSpec.MachineTypes) > 0 {
+		return providerSpec.MachineTypes
+	}
+	return []string{providerSpec.MachineType}
+}
+
+// fallbackMachineTypes reorders the machine types for the replacement of a server in ERROR state
+// If the failed server found no host, its type is moved to the end of the list so the next type is tried first.
+// Otherwise the replacement keeps the type of the failed server.
+func fallbackMachineTypes(machineTypes []string, failed *client.Server) []string {
+	index := slices.Index(machineTypes, machineTypeOf(failed))
+	if index < 0 {
+		return machineTypes
+	}
+	if client.ClassifyServerErrorMessage(failed.ErrorMessage) != nil {
+		index = (index + 1) % len(machineTypes)
+	}
+	return slices.Concat(machineTypes[index:], machineTypes[:index])
+}
+
+// machineTypeOf returns the machine type a server was created with
+func machineTypeOf(server *client.Server) string {
+	if machineType := server.Labels[StackitMachineTypeLabel]; machineType != "" {
+		return machineType
+	}
+	return server.MachineType
+}
+
+// createServerWithFallback creates the server with the first machine type and availability zone the platform has capacity for
+// Each machine type is tried in all zones before falling back to the next type, recently exhausted zones are tried last.
+// The chosen type is recorded in the server labels. Errors other than capacity exhaustion are returned right away.
+func (p *Provider) createServerWithFallback(ctx context.Context, c client.StackitClient, projectID, region string, createReq *client.CreateServerRequest, machineTypes, zones []string) (*client.Server, error) {
+	var err error
+	for _, machineType := range machineTypes {
+		for _, zone := range p.exhaustedZones.order(projectID, region, machineType, zones) {
+			createReq.MachineType = machineType
+			createReq.AvailabilityZone = zone
+			createReq.Labels[StackitMachineTypeLabel] = machineType
+
+			var server *client.Server
+			server, err = c.CreateServer(ctx, projectID, region, createReq)
+			if err == nil {
+				return server, nil
+			}
+			if !errors.Is(err, client.ErrCapacityExhausted) {
+				return nil, err
+			}
+
+			p.exhaustedZones.markExhausted(projectID, region, zone, machineType)
+			klog.Warningf("No capacity for machine type %q in availability zone %q for server %q: %v", machineType, zone, createReq.Name, err)
+		}
+	}
+
+	switch {
+	case len(zones) > 1:
+		return nil, fmt.Errorf("no capacity for any of the machine types %v in availability zones %v: %w", machineTypes, zones, err)
+	case len(machineTypes) > 1:
+		return nil, fmt.Errorf("no capacity for any of the machine types %v: %w", machineTypes, err)
+	}
+	return nil, err
+}
+
+// recreateAttempts returns the number of times the server was recreated after being in ERROR state
+func recreateAttempts(server *client.Server) int {
+	attempts, err := strconv.Atoi(server.Labels[StackitRecreateAttemptsLabel])
+	if err != nil {
+		return 0
+	}
+	return attempts
+}
+
 // nolint: gocyclo // this function is already pretty simple
 func (p *Provider) createServerRequest(req *driver.CreateMachineRequest, providerSpec *api.ProviderSpec) *client.CreateServerRequest {
 	// Build labels: merge ProviderSpec labels with MCM-specific labels
@@ -124,11 +335,19 @@ func (p *Provider) createServerRequest(req *driver.CreateMachineRequest, provide
 	// Add MCM-specific labels for server identification and orphan VM detection
 	labels[StackitMachineLabel] = req.Machine.Name
 	labels[StackitMachineClassLabel] = req.MachineClass.Name
+	if req.Machine.UID != "" {
+		labels[StackitMachineUIDLabel] = string(req.Machine.UID)
+	}
+
+	// Record the image resolved from the image selector
+	if providerSpec.Image != nil && providerSpec.ImageID != "" {
+		labels[StackitImageIDLabel] = providerSpec.ImageID
+	}
 
 	// Create server request
 	createReq := &client.CreateServerRequest{
 		Name:        req.Machine.Name,
-		MachineType: providerSpec.MachineType,
+		MachineType: machineTypesOf(providerSpec)[0],
 		ImageID:     providerSpec.ImageID,
 		Labels:      labels,
 	}
@@ -163,10 +382,8 @@ func (p *Provider) createServerRequest(req *driver.CreateMachineRequest, provide
 	// Add boot volume configuration if specified
 	if providerSpec.BootVolume != nil {
 		createReq.BootVolume = &client.BootVolumeRequest{
-			// DeleteOnTermination defaults to false in the IaaS API
-			// unless explicitly disabled, bootVolumes should always be cleaned up automatically
-			// otherwise this produces many orphaned volumes since node rolls happen frequently in k8s
-			DeleteOnTermination: new(ptr.Deref(providerSpec.BootVolume.DeleteOnTermination, true)),
+			// defaulted to true when the ProviderSpec is decoded, the IaaS API would keep the volume
+			DeleteOnTermination: providerSpec.BootVolume.DeleteOnTermination,
 			PerformanceClass:    providerSpec.BootVolume.PerformanceClass,
 			Size:                providerSpec.BootVolume.Size,
 		}
@@ -191,9 +408,7 @@ func (p *Provider) createServerRequest(req *driver.CreateMachineRequest, provide
 	}
 
 	// Add availability zone if specified
-	if providerSpec.AvailabilityZone != "" {
-		createReq.AvailabilityZone = providerSpec.AvailabilityZone
-	}
+	createReq.AvailabilityZone = availabilityZonesOf(providerSpec)[0]
 
 	// Add affinity group if specified
 	if providerSpec.AffinityGroup != "" {
@@ -229,99 +444,28 @@ func nicAddresses(nics []*client.NIC) []corev1.NodeAddress {
 		if nic.IPv6 != "" {
 			addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: nic.IPv6})
 		}
+		if nic.PublicIP != "" {
+			addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: nic.PublicIP})
+		}
 	}
 	return addresses
 }
 
-func (p *Provider) getServerByName(ctx context.Context, projectID, region, serverName string) (*client.Server, error) {
+// getServerByName returns the server of the machine, nil if there is none
+// If concurrent creates left several servers, the one to keep is selected and the duplicates are deleted
+func getServerByName(ctx context.Context, c client.StackitClient, projectID, region, serverName, machineUID, preferredServerID string) (*client.Server, error) {
 	// Check if the server got already created
-	labelSelector := map[string]string{
-		StackitMachineLabel: serverName,
-	}
-	servers, err := p.client.ListServers(ctx, projectID, region, labelSelector)
+	servers, err := listServersByName(ctx, c, projectID, region, serverName, machineUID)
 	if err != nil {
-		return nil, fmt.Errorf("SDK ListServers with labelSelector: %v failed: %w", labelSelector, err)
-	}
-
-	if len(servers) > 1 {
-		return nil, fmt.Errorf("%v servers found for server name %v", len(servers), serverName)
-	}
-
-	if len(servers) == 1 {
-		return servers[0], nil
+		return nil, err
 	}
 
 	// no servers found len == 0
-	return nil, nil
-}
-
-func (p *Provider) patchNetworkInterfaces(ctx context.Context, projectID, serverID string, providerSpec *api.ProviderSpec) ([]*client.NIC, error) {
-	nics, err := p.client.GetNICsForServer(ctx, projectID, providerSpec.Region, serverID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get NICs for server %q: %w", serverID, err)
-	}
-
-	if len(nics) == 0 {
-		return nil, fmt.Errorf("no NICs found for server %q", serverID)
-	}
-
-	if len(providerSpec.AllowedAddresses) == 0 {
-		return nics, nil
-	}
-
-	result := make([]*client.NIC, 0, len(nics))
-	for _, nic := range nics {
-		// if networking is not set, server is inside the default network
-		// just patch the interface since the server should only have one
-		if providerSpec.Networking != nil {
-			// only process interfaces that are either in the configured network (NetworkID) or are defined in NICIDs
-			if providerSpec.Networking.NetworkID != nic.NetworkID && !slices.Contains(providerSpec.Networking.NICIDs, nic.ID) {
-				result = append(result, nic)
-				continue
-			}
-		}
-
-		updateNic := false
-		// check if every cidr in providerspec.allowedAddresses is inside the nic allowedAddresses
-		for _, allowedAddress := range providerSpec.AllowedAddresses {
-			if !slices.Contains(nic.AllowedAddresses, allowedAddress) {
-				nic.AllowedAddresses = append(nic.AllowedAddresses, allowedAddress)
-				updateNic = true
-			}
-		}
-
-		if !updateNic {
-			result = append(result, nic)
-			continue
-		}
-
-		updatedNic, err := p.client.UpdateNIC(ctx, projectID, providerSpec.Region, nic.NetworkID, nic.ID, nic.AllowedAddresses)
-		if err != nil {
-			return nil, fmt.Errorf("failed to update allowed addresses for NIC %s: %w", nic.ID, err)
-		}
-
-		klog.V(2).Infof("Updated allowed addresses for NIC %s to %v", nic.ID, nic.AllowedAddresses)
-		result = append(result, updatedNic)
+	if len(servers) == 0 {
+		return nil, nil
 	}
 
-	return result, nil
-}
-
-func (p *Provider) WaitUntilServerRunning(ctx context.Context, projectID, region, serverID string) error {
-	return wait.PollUntilContextTimeout(ctx, p.pollingInterval, p.pollingTimeout, true, func(ctx context.Context) (bool, error) {
-		server, err := p.client.GetServer(ctx, projectID, region, serverID)
-		if err != nil {
-			return false, err
-		}
-
-		switch server.Status {
-		case "ACTIVE":
-			klog.V(2).Infof("Server %q reached ACTIVE state", serverID)
-			return true, nil
-		case "ERROR":
-			return false, fmt.Errorf("server in ERROR state: %q", server.ErrorMessage)
-		}
-
-		return false, nil
-	})
+	server, duplicates := selectServer(servers, preferredServerID)
+	deleteDuplicateServers(ctx, c, projectID, region, serverName, server, duplicates)
+	return server, nil
 }
@@ -118,57 +118,95 @@ var _ = Describe("CreateMachine", func() {
 			Expect(capturedReq.ImageID).To(Equal("12345678-1234-1234-1234-123456789abc"))
 		})
 
-		It("should return internal IPs from NICs in Addresses", func() {
+		It("should return without waiting for the server if the scheduling timeout is disabled", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				Fail("GetServer must not be called by CreateMachine")
+				return nil, nil
+			}
 			mockClient.GetNICsFunc = func(_ context.Context, _, _, _ string) ([]*client.NIC, error) {
-				return []*client.NIC{
-					{ID: "nic-1", NetworkID: "net-1", IPv4: "10.0.0.5", IPv6: "fd00::1"},
-					{ID: "nic-2", NetworkID: "net-2", IPv4: "10.0.1.5"},
-				}, nil
+				Fail("GetNICsForServer must not be called by CreateMachine")
+				return nil, nil
 			}
 
 			resp, err := provider.CreateMachine(ctx, req)
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(resp.Addresses).To(ConsistOf(
-				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.5"},
-				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "fd00::1"},
-				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.1.5"},
-			))
+			Expect(resp.ProviderID).To(Equal("stackit://11111111-2222-3333-4444-555555555555/550e8400-e29b-41d4-a716-446655440000"))
+			Expect(resp.Addresses).To(BeEmpty())
 		})
 
-		It("should poll GetServer until server is ACTIVE", func() {
-			getServerCallCount := 0
+		It("should return the existing server if it was already created", func() {
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{{
+					ID:     "existing-server-id",
+					Name:   "test-machine",
+					Status: "ACTIVE",
+					NICs:   []*client.NIC{{ID: "nic-1", NetworkID: "net-1", IPv4: "10.0.0.5"}},
+				}}, nil
+			}
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, _ *client.CreateServerRequest) (*client.Server, error) {
+				Fail("CreateServer must not be called for an existing server")
+				return nil, nil
+			}
 
-			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
-				return &client.Server{
-					ID:     "550e8400-e29b-41d4-a716-446655440000",
-					Name:   req.Name,
-					Status: "CREATING",
+			resp, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.ProviderID).To(Equal("stackit://11111111-2222-3333-4444-555555555555/existing-server-id"))
+			Expect(resp.Addresses).To(ConsistOf(corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.5"}))
+		})
+
+		It("should keep the oldest ACTIVE server and delete duplicates", func() {
+			older := time.Now().Add(-time.Hour)
+			newer := time.Now()
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{
+					{ID: "duplicate-server-id", Name: "test-machine", Status: "ACTIVE", CreatedAt: &newer},
+					{ID: "existing-server-id", Name: "test-machine", Status: "ACTIVE", CreatedAt: &older},
 				}, nil
 			}
-			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
-				getServerCallCount++
-				// First call returns CREATING, second call returns ACTIVE
-				if getServerCallCount == 1 {
-					return &client.Server{
-						ID:     "550e8400-e29b-41d4-a716-446655440000",
-						Name:   "test-machine",
-						Status: "CREATING",
-					}, nil
-				}
-				return &client.Server{
-					ID:     "550e8400-e29b-41d4-a716-446655440000",
+			var deleted []string
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				deleted = append(deleted, serverID)
+				return nil
+			}
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, _ *client.CreateServerRequest) (*client.Server, error) {
+				Fail("CreateServer must not be called for an existing server")
+				return nil, nil
+			}
+
+			resp, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.ProviderID).To(Equal("stackit://11111111-2222-3333-4444-555555555555/existing-server-id"))
+			Expect(deleted).To(Equal([]string{"duplicate-server-id"}))
+		})
+
+		It("should create a new server labelled with the machine UID instead of adopting one of an earlier machine", func() {
+			req.Machine.UID = "new-uid"
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{{
+					ID:     "stale-server-id",
 					Name:   "test-machine",
-					Status: "ACTIVE",
-				}, nil
+					Status: "DELETING",
+					Labels: map[string]string{StackitMachineLabel: "test-machine", StackitMachineUIDLabel: "old-uid"},
+				}}, nil
+			}
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
+				Fail("servers of an earlier machine must be left to the orphan collection")
+				return nil
+			}
+			var createdReq *client.CreateServerRequest
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, createReq *client.CreateServerRequest) (*client.Server, error) {
+				createdReq = createReq
+				return &client.Server{ID: "new-server-id", Name: createReq.Name, Status: "CREATING"}, nil
 			}
 
 			resp, err := provider.CreateMachine(ctx, req)
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(resp).NotTo(BeNil())
-			Expect(resp.ProviderID).To(Equal("stackit://11111111-2222-3333-4444-555555555555/550e8400-e29b-41d4-a716-446655440000"))
-			Expect(getServerCallCount).To(BeNumerically(">=", 2))
+			Expect(resp.ProviderID).To(Equal("stackit://11111111-2222-3333-4444-555555555555/new-server-id"))
+			Expect(createdReq.Labels).To(HaveKeyWithValue(StackitMachineUIDLabel, "new-uid"))
 		})
 	})
 
@@ -205,6 +243,48 @@ var _ = Describe("CreateMachine", func() {
 			Expect(statusErr.Code()).To(Equal(codes.InvalidArgument))
 		})
 
+		It("should report all validation errors in one message", func() {
+			providerSpec := &api.ProviderSpec{
+				MachineType: "InvalidFormat",
+				ImageID:     "12345678-1234-1234-1234-123456789abc",
+			}
+			providerSpecRaw, _ := mock.EncodeProviderSpec(providerSpec)
+			req.MachineClass.ProviderSpec.Raw = providerSpecRaw
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.InvalidArgument))
+			Expect(statusErr.Message()).To(ContainSubstring("providerSpec.region: Required value"))
+			Expect(statusErr.Message()).To(ContainSubstring(`providerSpec.machineType: Invalid value: "InvalidFormat"`))
+			Expect(statusErr.Message()).To(ContainSubstring("providerSpec.networking: Required value"))
+		})
+
+		It("should reject unknown ProviderSpec fields", func() {
+			req.MachineClass.ProviderSpec.Raw = []byte(`{"machineType":"c2i.2","imageId":"12345678-1234-1234-1234-123456789abc","region":"eu01",` +
+				`"networking":{"networkId":"770e8400-e29b-41d4-a716-446655440000"},"securityGroup":["880e8400-e29b-41d4-a716-446655440000"]}`)
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.InvalidArgument))
+			Expect(statusErr.Message()).To(ContainSubstring("providerSpec.securityGroup: Forbidden: unknown field"))
+		})
+
+		It("should ignore unknown ProviderSpec fields in lenient mode", func() {
+			req.MachineClass.ProviderSpec.Raw = []byte(`{"machineType":"c2i.2","imageId":"12345678-1234-1234-1234-123456789abc","region":"eu01",` +
+				`"networking":{"networkId":"770e8400-e29b-41d4-a716-446655440000"},"securityGroup":["880e8400-e29b-41d4-a716-446655440000"]}`)
+			provider.lenientDecoding = true
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		It("should fail when Provider is wrong", func() {
 			req.MachineClass.Provider = "openstack"
 
@@ -228,10 +308,96 @@ var _ = Describe("CreateMachine", func() {
 		})
 	})
 
-	Context("when server has no NICs", func() {
-		It("should return Unavailable error", func() {
-			mockClient.GetNICsFunc = func(_ context.Context, _, _, _ string) ([]*client.NIC, error) {
-				return []*client.NIC{}, nil
+	Context("when an existing server is in ERROR state", func() {
+		var (
+			deleted      bool
+			createCalled bool
+			createdReq   *client.CreateServerRequest
+			failedServer *client.Server
+		)
+
+		BeforeEach(func() {
+			provider.maxRecreateAttempts = 2
+			deleted = false
+			createCalled = false
+			failedServer = &client.Server{
+				ID:           "failed-server-id",
+				Name:         "test-machine",
+				Status:       "ERROR",
+				ErrorMessage: "No valid host was found. There are not enough hosts available.",
+				Labels:       map[string]string{StackitMachineLabel: "test-machine"},
+			}
+
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{failedServer}, nil
+			}
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				Expect(serverID).To(Equal("failed-server-id"))
+				deleted = true
+				return nil
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				if deleted {
+					return nil, fmt.Errorf("SDK GetServer failed: %w", &client.APIError{Class: client.ErrNotFound, StatusCode: 404})
+				}
+				return failedServer, nil
+			}
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
+				createCalled = true
+				createdReq = req
+				return &client.Server{ID: "new-server-id", Name: req.Name, Status: "CREATING"}, nil
+			}
+		})
+
+		It("should delete the server and create a new one", func() {
+			resp, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+			Expect(createCalled).To(BeTrue())
+			Expect(createdReq.Labels).To(HaveKeyWithValue(StackitRecreateAttemptsLabel, "1"))
+			Expect(resp.ProviderID).To(Equal("stackit://11111111-2222-3333-4444-555555555555/new-server-id"))
+		})
+
+		It("should count recreate attempts", func() {
+			failedServer.Labels[StackitRecreateAttemptsLabel] = "1"
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdReq.Labels).To(HaveKeyWithValue(StackitRecreateAttemptsLabel, "2"))
+		})
+
+		It("should stop recreating once the attempts are used up", func() {
+			failedServer.Labels[StackitRecreateAttemptsLabel] = "2"
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.ResourceExhausted))
+			Expect(statusErr.Message()).To(ContainSubstring("No valid host was found"))
+			Expect(deleted).To(BeFalse())
+			Expect(createCalled).To(BeFalse())
+		})
+
+		It("should return Internal once the attempts are used up for other errors", func() {
+			failedServer.Labels[StackitRecreateAttemptsLabel] = "2"
+			failedServer.ErrorMessage = "Build of instance aborted"
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Internal))
+			Expect(statusErr.Message()).To(ContainSubstring("Build of instance aborted"))
+		})
+
+		It("should return the error message when the deletion fails", func() {
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrServerError, StatusCode: 503}
 			}
 
 			_, err := provider.CreateMachine(ctx, req)
@@ -240,7 +406,263 @@ var _ = Describe("CreateMachine", func() {
 			statusErr, ok := status.FromError(err)
 			Expect(ok).To(BeTrue())
 			Expect(statusErr.Code()).To(Equal(codes.Unavailable))
-			Expect(err.Error()).To(ContainSubstring("no NICs found"))
+			Expect(statusErr.Message()).To(ContainSubstring("No valid host was found"))
+			Expect(createCalled).To(BeFalse())
+		})
+	})
+
+	Context("when MCM runs the creation flow", func() {
+		var (
+			servers  map[string]*client.Server
+			created  []*client.CreateServerRequest
+			outcomes []string
+		)
+
+		statusRequest := func() *driver.GetMachineStatusRequest {
+			return &driver.GetMachineStatusRequest{Machine: machine, MachineClass: machineClass, Secret: secret}
+		}
+		initializeRequest := func() *driver.InitializeMachineRequest {
+			return &driver.InitializeMachineRequest{Machine: machine, MachineClass: machineClass, Secret: secret}
+		}
+		codeOf := func(err error) codes.Code {
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			return statusErr.Code()
+		}
+
+		BeforeEach(func() {
+			provider.maxRecreateAttempts = 2
+			servers = map[string]*client.Server{}
+			created = nil
+			// state each new server reaches on its first lookup, in order of creation
+			outcomes = []string{"ERROR", "ACTIVE"}
+
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
+				created = append(created, req)
+				server := &client.Server{ID: fmt.Sprintf("server-%d", len(created)), Name: req.Name, Status: "CREATING", MachineType: req.MachineType, AvailabilityZone: req.AvailabilityZone, Labels: req.Labels}
+				servers[server.ID] = server
+				copied := *server
+				return &copied, nil
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				server, ok := servers[serverID]
+				if !ok {
+					return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+				}
+				if server.Status == "CREATING" {
+					server.Status = outcomes[0]
+					outcomes = outcomes[1:]
+					if server.Status == "ERROR" {
+						server.ErrorMessage = "No valid host was found. There are not enough hosts available."
+					}
+				}
+				copied := *server
+				return &copied, nil
+			}
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				result := make([]*client.Server, 0, len(servers))
+				for _, server := range servers {
+					copied := *server
+					result = append(result, &copied)
+				}
+				return result, nil
+			}
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				delete(servers, serverID)
+				return nil
+			}
+		})
+
+		It("should replace a server that goes into ERROR state before the ProviderID is recorded", func() {
+			provider.schedulingTimeout = time.Second
+
+			// MCM looks for the VM before it creates one
+			_, err := provider.GetMachineStatus(ctx, statusRequest())
+			Expect(codeOf(err)).To(Equal(codes.NotFound))
+
+			resp, err := provider.CreateMachine(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.ProviderID).To(Equal("stackit://11111111-2222-3333-4444-555555555555/server-2"))
+			Expect(servers).NotTo(HaveKey("server-1"))
+			Expect(created[1].Labels).To(HaveKeyWithValue(StackitRecreateAttemptsLabel, "1"))
+			machine.Spec.ProviderID = resp.ProviderID
+
+			_, err = provider.InitializeMachine(ctx, initializeRequest())
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = provider.GetMachineStatus(ctx, statusRequest())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should replace a server that found no host with the next machine type", func() {
+			provider.schedulingTimeout = time.Second
+			providerSpec := &api.ProviderSpec{
+				MachineTypes: []string{"c3i.2", "c2i.2"},
+				ImageID:      "12345678-1234-1234-1234-123456789abc",
+				Region:       "eu01",
+				Networking: &api.NetworkingSpec{
+					NetworkID: "770e8400-e29b-41d4-a716-446655440000",
+				},
+			}
+			machineClass.ProviderSpec.Raw, _ = mock.EncodeProviderSpec(providerSpec)
+
+			_, err := provider.GetMachineStatus(ctx, statusRequest())
+			Expect(codeOf(err)).To(Equal(codes.NotFound))
+
+			resp, err := provider.CreateMachine(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(HaveLen(2))
+			Expect(created[0].MachineType).To(Equal("c3i.2"))
+			Expect(created[1].MachineType).To(Equal("c2i.2"))
+			Expect(created[1].Labels).To(HaveKeyWithValue(StackitMachineTypeLabel, "c2i.2"))
+			machine.Spec.ProviderID = resp.ProviderID
+
+			_, err = provider.InitializeMachine(ctx, initializeRequest())
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = provider.GetMachineStatus(ctx, statusRequest())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should replace a server that found no host in another availability zone", func() {
+			provider.schedulingTimeout = time.Second
+			provider.exhaustedZones = newZoneCache(time.Minute)
+			providerSpec := &api.ProviderSpec{
+				MachineType:       "c2i.2",
+				ImageID:           "12345678-1234-1234-1234-123456789abc",
+				Region:            "eu01",
+				AvailabilityZones: []string{"eu01-1", "eu01-2"},
+				Networking: &api.NetworkingSpec{
+					NetworkID: "770e8400-e29b-41d4-a716-446655440000",
+				},
+			}
+			machineClass.ProviderSpec.Raw, _ = mock.EncodeProviderSpec(providerSpec)
+
+			_, err := provider.GetMachineStatus(ctx, statusRequest())
+			Expect(codeOf(err)).To(Equal(codes.NotFound))
+
+			resp, err := provider.CreateMachine(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(HaveLen(2))
+			Expect(created[0].AvailabilityZone).To(Equal("eu01-1"))
+			Expect(created[1].AvailabilityZone).To(Equal("eu01-2"))
+			machine.Spec.ProviderID = resp.ProviderID
+
+			_, err = provider.InitializeMachine(ctx, initializeRequest())
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = provider.GetMachineStatus(ctx, statusRequest())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should report a server that goes into ERROR state after the ProviderID is recorded", func() {
+			_, err := provider.GetMachineStatus(ctx, statusRequest())
+			Expect(codeOf(err)).To(Equal(codes.NotFound))
+
+			resp, err := provider.CreateMachine(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.ProviderID).To(Equal("stackit://11111111-2222-3333-4444-555555555555/server-1"))
+			machine.Spec.ProviderID = resp.ProviderID
+
+			_, err = provider.InitializeMachine(ctx, initializeRequest())
+			Expect(codeOf(err)).To(Equal(codes.ResourceExhausted))
+
+			// MCM keeps the ProviderID and fails the Machine after its creation timeout
+			_, err = provider.GetMachineStatus(ctx, statusRequest())
+			Expect(codeOf(err)).To(Equal(codes.ResourceExhausted))
+			Expect(err).To(MatchError(ContainSubstring("No valid host was found")))
+			Expect(created).To(HaveLen(1))
+		})
+	})
+
+	Context("with a machine type fallback list", func() {
+		var createdTypes []string
+
+		BeforeEach(func() {
+			providerSpec := &api.ProviderSpec{
+				MachineTypes: []string{"c3i.2", "c2i.2", "c1.2"},
+				ImageID:      "12345678-1234-1234-1234-123456789abc",
+				Region:       "eu01",
+				Networking: &api.NetworkingSpec{
+					NetworkID: "770e8400-e29b-41d4-a716-446655440000",
+				},
+			}
+			machineClass.ProviderSpec.Raw, _ = mock.EncodeProviderSpec(providerSpec)
+
+			createdTypes = nil
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
+				createdTypes = append(createdTypes, req.MachineType)
+				Expect(req.Labels).To(HaveKeyWithValue(StackitMachineTypeLabel, req.MachineType))
+				if req.MachineType == "c3i.2" {
+					return nil, &client.APIError{Class: client.ErrCapacityExhausted, StatusCode: 409, Message: "No valid host was found"}
+				}
+				return &client.Server{ID: "new-server-id", Name: req.Name, Status: "CREATING"}, nil
+			}
+		})
+
+		It("should fall back to the next machine type on capacity errors", func() {
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdTypes).To(Equal([]string{"c3i.2", "c2i.2"}))
+		})
+
+		It("should not fall back on other errors", func() {
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
+				createdTypes = append(createdTypes, req.MachineType)
+				return nil, &client.APIError{Class: client.ErrQuotaExceeded, StatusCode: 403, Message: "quota exceeded"}
+			}
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			Expect(createdTypes).To(Equal([]string{"c3i.2"}))
+		})
+
+		It("should return ResourceExhausted when no machine type has capacity", func() {
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
+				createdTypes = append(createdTypes, req.MachineType)
+				return nil, &client.APIError{Class: client.ErrCapacityExhausted, StatusCode: 409, Message: "No valid host was found"}
+			}
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.ResourceExhausted))
+			Expect(createdTypes).To(Equal([]string{"c3i.2", "c2i.2", "c1.2"}))
+		})
+
+		It("should replace a server that found no host with the next machine type", func() {
+			provider.maxRecreateAttempts = 2
+			deleted := false
+			failedServer := &client.Server{
+				ID:           "failed-server-id",
+				Name:         "test-machine",
+				Status:       "ERROR",
+				MachineType:  "c2i.2",
+				ErrorMessage: "No valid host was found. There are not enough hosts available.",
+				Labels:       map[string]string{StackitMachineLabel: "test-machine", StackitMachineTypeLabel: "c2i.2"},
+			}
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{failedServer}, nil
+			}
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
+				deleted = true
+				return nil
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				if deleted {
+					return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+				}
+				return failedServer, nil
+			}
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdTypes).To(Equal([]string{"c1.2"}))
 		})
 	})
 
@@ -258,21 +680,13 @@ var _ = Describe("CreateMachine", func() {
 			Expect(statusErr.Code()).To(Equal(codes.Unavailable))
 		})
 
-		It("should return ResourceExhausted when server enters ERROR state with 'no valid host'", func() {
-			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
-				return &client.Server{
-					ID:     "550e8400-e29b-41d4-a716-446655440000",
-					Name:   req.Name,
-					Status: "CREATING",
-				}, nil
-			}
-			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
-				return &client.Server{
-					ID:           "550e8400-e29b-41d4-a716-446655440000",
-					Name:         "test-machine",
-					Status:       "ERROR",
-					ErrorMessage: "No valid host was found. There are not enough hosts available.",
-				}, nil
+		It("should return ResourceExhausted when the project quota is exceeded", func() {
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, _ *client.CreateServerRequest) (*client.Server, error) {
+				return nil, fmt.Errorf("SDK CreateServer failed: %w", &client.APIError{
+					Class:      client.ErrQuotaExceeded,
+					StatusCode: 400,
+					Message:    "quota exceeded for vCPU",
+				})
 			}
 
 			_, err := provider.CreateMachine(ctx, req)
@@ -281,7 +695,20 @@ var _ = Describe("CreateMachine", func() {
 			statusErr, ok := status.FromError(err)
 			Expect(ok).To(BeTrue())
 			Expect(statusErr.Code()).To(Equal(codes.ResourceExhausted))
-			Expect(err.Error()).To(ContainSubstring("No valid host"))
 		})
+
+		It("should return Unauthenticated when the credentials are rejected", func() {
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return nil, &client.APIError{Class: client.ErrUnauthenticated, StatusCode: 401}
+			}
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Unauthenticated))
+		})
+
 	})
 })
@@ -206,7 +206,16 @@ var _ = Describe("CreateMachine - Networking", func() {
 				}, nil
 			}
 
-			_, err := provider.CreateMachine(ctx, req)
+			resp, err := provider.CreateMachine(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// allowedAddresses are applied once the server is ACTIVE
+			machine.Spec.ProviderID = resp.ProviderID
+			_, err = provider.InitializeMachine(ctx, &driver.InitializeMachineRequest{
+				Machine:      machine,
+				MachineClass: machineClass,
+				Secret:       secret,
+			})
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(called).To(BeTrue())
@@ -277,7 +286,16 @@ var _ = Describe("CreateMachine - Networking", func() {
 				}, nil
 			}
 
-			_, err := provider.CreateMachine(ctx, req)
+			resp, err := provider.CreateMachine(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// allowedAddresses are applied once the server is ACTIVE
+			machine.Spec.ProviderID = resp.ProviderID
+			_, err = provider.InitializeMachine(ctx, &driver.InitializeMachineRequest{
+				Machine:      machine,
+				MachineClass: machineClass,
+				Secret:       secret,
+			})
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(called).To(BeTrue())
@@ -343,7 +361,16 @@ var _ = Describe("CreateMachine - Networking", func() {
 				}, nil
 			}
 
-			_, err := provider.CreateMachine(ctx, req)
+			resp, err := provider.CreateMachine(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// allowedAddresses are applied once the server is ACTIVE
+			machine.Spec.ProviderID = resp.ProviderID
+			_, err = provider.InitializeMachine(ctx, &driver.InitializeMachineRequest{
+				Machine:      machine,
+				MachineClass: machineClass,
+				Secret:       secret,
+			})
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(called).To(BeFalse())
@@ -381,7 +408,7 @@ var _ = Describe("CreateMachine - Networking", func() {
 			_, err := provider.CreateMachine(ctx, req)
 
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("networking is required"))
+			Expect(err.Error()).To(ContainSubstring("providerSpec.networking: Required value"))
 		})
 	})
 
@@ -461,7 +488,7 @@ var _ = Describe("CreateMachine - Networking", func() {
 
 			// Should fail validation because networking is specified but empty
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("networking must specify either networkId or nicIds"))
+			Expect(err.Error()).To(ContainSubstring("providerSpec.networking: Required value: must specify either networkId or nicIds"))
 		})
 	})
 })
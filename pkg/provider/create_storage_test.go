@@ -124,6 +124,28 @@ var _ = Describe("CreateMachine", func() {
 			Expect(capturedReq.BootVolume.Source.ID).To(Equal("550e8400-e29b-41d4-a716-446655440000"))
 		})
 
+		It("should delete the BootVolume with the server by default", func() {
+			providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{
+				MachineType: "c2i.2",
+				ImageID:     "12345678-1234-1234-1234-123456789abc",
+				Region:      "eu01",
+				Networking:  &api.NetworkingSpec{NetworkID: "770e8400-e29b-41d4-a716-446655440000"},
+				BootVolume:  &api.BootVolumeSpec{Size: 50},
+			})
+			req.MachineClass.ProviderSpec.Raw = providerSpecRaw
+
+			var capturedReq *client.CreateServerRequest
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
+				capturedReq = req
+				return &client.Server{ID: "test-server-id", Name: req.Name, Status: "CREATING"}, nil
+			}
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(capturedReq.BootVolume.DeleteOnTermination).To(Equal(new(true)))
+		})
+
 		It("should pass BootVolume with minimal config to API", func() {
 			providerSpec := &api.ProviderSpec{
 				MachineType: "c2i.2",
@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+// STACKIT volume states the provider acts on
+const (
+	volumeStatusAvailable = "AVAILABLE"
+	volumeStatusError     = "ERROR"
+)
+
+// errVolumeInUse is returned if a volume is attached to another server, retrying does not help
+var errVolumeInUse = errors.New("volume is attached to another server")
+
+// ensureDataVolumes creates the ProviderSpec data volumes of a machine and attaches them to its server
+//
+// Data volumes are looked up by the machine label, so volumes created by an earlier attempt are reused.
+// Volumes kept from an earlier Machine with the same name are not reused.
+// Volumes are created in the availability zone of the server, which is only known once the server is ACTIVE.
+func (p *Provider) ensureDataVolumes(ctx context.Context, c client.StackitClient, projectID, machineName, machineClassName, machineUID string, server *client.Server, providerSpec *api.ProviderSpec) error {
+	if len(providerSpec.DataVolumes) == 0 {
+		return nil
+	}
+
+	volumes, err := c.ListVolumes(ctx, projectID, providerSpec.Region, map[string]string{StackitMachineLabel: machineName})
+	if err != nil {
+		return fmt.Errorf("failed to list data volumes: %w", err)
+	}
+
+	existing := make(map[string]*client.Volume, len(volumes))
+	for _, volume := range volumes {
+		if name, ok := volume.Labels[StackitDataVolumeLabel]; ok && matchesMachineUID(volume.Labels, machineUID) {
+			existing[name] = volume
+		}
+	}
+
+	for _, spec := range providerSpec.DataVolumes {
+		volume, ok := existing[spec.Name]
+		if !ok {
+			volume, err = c.CreateVolume(ctx, projectID, providerSpec.Region, dataVolumeRequest(machineName, machineClassName, machineUID, server.AvailabilityZone, providerSpec, spec))
+			if err != nil {
+				return fmt.Errorf("failed to create data volume %q: %w", spec.Name, err)
+			}
+			klog.V(2).Infof("Created data volume %q with ID %q for machine %q", spec.Name, volume.ID, machineName)
+		}
+
+		if volume.ServerID == server.ID || slices.Contains(server.VolumeIDs, volume.ID) {
+			continue
+		}
+
+		if err := p.WaitUntilVolumeAvailable(ctx, c, projectID, providerSpec.Region, volume.ID, server.ID); err != nil {
+			return fmt.Errorf("failed waiting for data volume %q to be AVAILABLE: %w", spec.Name, err)
+		}
+
+		if err := attachVolume(ctx, c, projectID, providerSpec.Region, server.ID, volume.ID); err != nil {
+			return fmt.Errorf("failed to attach data volume %q: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// dataVolumeRequest builds the request to create a data volume for a machine
+func dataVolumeRequest(machineName, machineClassName, machineUID, availabilityZone string, providerSpec *api.ProviderSpec, spec api.DataVolumeSpec) *client.CreateVolumeRequest {
+	labels := make(map[string]string)
+	maps.Copy(labels, providerSpec.Labels)
+	maps.Copy(labels, machineLabels(machineName, machineClassName, machineUID))
+	labels[StackitDataVolumeLabel] = spec.Name
+	// the decision is stored on the volume, the MachineClass may have changed by the time the machine is deleted
+	labels[StackitDeleteOnTerminationLabel] = strconv.FormatBool(ptr.Deref(spec.DeleteOnTermination, true))
+
+	req := &client.CreateVolumeRequest{
+		Name:             fmt.Sprintf("%s-%s", machineName, spec.Name),
+		AvailabilityZone: availabilityZone,
+		Size:             spec.Size,
+		PerformanceClass: spec.PerformanceClass,
+		Labels:           labels,
+	}
+	if spec.SnapshotID != "" {
+		req.Source = &client.VolumeSourceRequest{Type: "snapshot", ID: spec.SnapshotID}
+	}
+	return req
+}
+
+// listDataVolumeIDs returns the IDs of the data volumes created for the machine
+// Volumes of an earlier Machine with the same name are left out
+func listDataVolumeIDs(ctx context.Context, c client.StackitClient, projectID, region, machineName, machineUID string) ([]string, error) {
+	volumes, err := c.ListVolumes(ctx, projectID, region, map[string]string{StackitMachineLabel: machineName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data volumes: %w", err)
+	}
+
+	var volumeIDs []string
+	for _, volume := range volumes {
+		if _, ok := volume.Labels[StackitDataVolumeLabel]; ok && matchesMachineUID(volume.Labels, machineUID) {
+			volumeIDs = append(volumeIDs, volume.ID)
+		}
+	}
+	return volumeIDs, nil
+}
+
+// missingDataVolumes returns true if the server has fewer data volumes attached than configured
+// Only the data volumes of the machine are counted, volumes attached by others, e.g. the CSI driver, are not
+func missingDataVolumes(server *client.Server, providerSpec *api.ProviderSpec, dataVolumeIDs []string) bool {
+	attached := 0
+	for _, volumeID := range server.VolumeIDs {
+		if slices.Contains(dataVolumeIDs, volumeID) {
+			attached++
+		}
+	}
+	return attached < len(providerSpec.DataVolumes)
+}
+
+// deleteDataVolumes deletes the data volumes of a machine whose server is gone
+// Volumes created with deleteOnTermination set to false and volumes of an earlier Machine with the same name are kept
+func deleteDataVolumes(ctx context.Context, c client.StackitClient, projectID, region, machineName, machineUID string) error {
+	volumes, err := c.ListVolumes(ctx, projectID, region, map[string]string{StackitMachineLabel: machineName})
+	if err != nil {
+		return fmt.Errorf("failed to list data volumes: %w", err)
+	}
+
+	for _, volume := range volumes {
+		name, ok := volume.Labels[StackitDataVolumeLabel]
+		if !ok {
+			continue
+		}
+		if !matchesMachineUID(volume.Labels, machineUID) {
+			klog.V(2).Infof("Keeping data volume %q (%q) of an earlier machine %q with UID %q", name, volume.ID, machineName, volume.Labels[StackitMachineUIDLabel])
+			continue
+		}
+		if volume.Labels[StackitDeleteOnTerminationLabel] == "false" {
+			klog.V(2).Infof("Keeping data volume %q (%q) of machine %q", name, volume.ID, machineName)
+			continue
+		}
+
+		if err := c.DeleteVolume(ctx, projectID, region, volume.ID); err != nil && !errors.Is(err, client.ErrNotFound) {
+			return fmt.Errorf("failed to delete data volume %q (%q): %w", name, volume.ID, err)
+		}
+
+		klog.V(2).Infof("Deleted data volume %q (%q) of machine %q", name, volume.ID, machineName)
+	}
+
+	return nil
+}
+
+// attachVolume attaches the volume to the server
+// A conflict is accepted if the volume got attached to the server in the meantime,
+// a volume attached to another server fails with errVolumeInUse
+func attachVolume(ctx context.Context, c client.StackitClient, projectID, region, serverID, volumeID string) error {
+	err := c.AttachVolume(ctx, projectID, region, serverID, volumeID)
+	if err == nil {
+		klog.V(2).Infof("Attached volume %q to server %q", volumeID, serverID)
+		return nil
+	}
+	if !errors.Is(err, client.ErrConflict) {
+		return err
+	}
+
+	volume, getErr := c.GetVolume(ctx, projectID, region, volumeID)
+	if getErr != nil {
+		return err
+	}
+	switch volume.ServerID {
+	case serverID:
+		klog.V(2).Infof("Volume %q is already attached to server %q", volumeID, serverID)
+		return nil
+	case "":
+		return err
+	}
+	return fmt.Errorf("%w: volume %q is attached to server %q", errVolumeInUse, volumeID, volume.ServerID)
+}
+
+// WaitUntilVolumeAvailable polls the volume until it reaches AVAILABLE state
+// A volume attached to another server than serverID fails right away with errVolumeInUse
+func (p *Provider) WaitUntilVolumeAvailable(ctx context.Context, c client.StackitClient, projectID, region, volumeID, serverID string) error {
+	return wait.PollUntilContextTimeout(ctx, p.pollingInterval, p.pollingTimeout, true, func(ctx context.Context) (bool, error) {
+		volume, err := c.GetVolume(ctx, projectID, region, volumeID)
+		if err != nil {
+			return false, err
+		}
+
+		if volume.ServerID != "" && volume.ServerID != serverID {
+			return false, fmt.Errorf("%w: volume %q is attached to server %q", errVolumeInUse, volumeID, volume.ServerID)
+		}
+
+		switch volume.Status {
+		case volumeStatusAvailable:
+			return true, nil
+		case volumeStatusError:
+			return false, fmt.Errorf("volume %q in ERROR state", volumeID)
+		}
+
+		return false, nil
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
@@ -18,9 +20,23 @@ import (
 //
 // This method deletes the server identified by the ProviderID from STACKIT infrastructure.
 // It is idempotent - if the server is already deleted (404), it returns success.
+// The server is only deleted if its machine and machine class labels match the Machine, so a
+// wrong ProviderID can never delete a server that is not managed by this Machine.
+// Duplicate servers carrying the machine and machine class labels of the Machine are deleted as well,
+// servers labelled with the UID of an earlier Machine with the same name are left alone.
+// If the ProviderSpec sets a shutdownGracePeriod, a running server is stopped first and
+// deleted once it is stopped or the grace period is over.
+// Once the server is gone, its volumes and NICs are detached. The data volumes of the
+// machine are deleted unless they were created with deleteOnTermination set to false,
+// detached NICs labelled for the machine are deleted. Public IPs allocated for the
+// machine are deleted, public IPs taken from a pool are returned to it.
 //
 // Error codes:
 //   - InvalidArgument: Missing or invalid ProviderID
+//   - FailedPrecondition: Server of the ProviderID is not labelled for the Machine and its MachineClass
+//   - Unauthenticated / PermissionDenied: Service account credentials rejected or missing permissions
+//   - Unavailable: Rate limiting or STACKIT server errors
+//   - Aborted: Server, data volume or public IP is in a state that does not allow deletion
 //   - Internal: Failed to delete server or communicate with STACKIT API
 func (p *Provider) DeleteMachine(ctx context.Context, req *driver.DeleteMachineRequest) (*driver.DeleteMachineResponse, error) {
 	// Log messages to track delete request
@@ -30,13 +46,13 @@ func (p *Provider) DeleteMachine(ctx context.Context, req *driver.DeleteMachineR
 	// Extract credentials from Secret
 	projectIDFromSecret, serviceAccountKey := extractSecretCredentials(req.Secret.Data)
 
-	// Initialize client on first use (lazy initialization)
-	if err := p.ensureClient(serviceAccountKey); err != nil {
+	// Resolve client for the Secret credentials (created on first use or after rotation)
+	c, err := p.getClient(projectIDFromSecret, serviceAccountKey)
+	if err != nil {
 		return nil, status.Error(codes.Unauthenticated, fmt.Sprintf("failed to initialize STACKIT client: %v", err))
 	}
 
 	var projectID, serverID string
-	var err error
 	if req.Machine.Spec.ProviderID != "" {
 		if !strings.HasPrefix(req.Machine.Spec.ProviderID, StackitProviderName) {
 			return nil, status.Error(codes.InvalidArgument, "providerID is not empty and does not start with stackit://")
@@ -59,49 +75,216 @@ func (p *Provider) DeleteMachine(ctx context.Context, req *driver.DeleteMachineR
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	if serverID == "" {
-		server, err := p.getServerByName(ctx, projectID, providerSpec.Region, req.Machine.Name)
-		if err != nil {
-			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to find server by name: %v", err))
+	// MCM deletes servers of unknown machines with a Machine without UID, the server of the ProviderID tells its UID then
+	machineUID := string(req.Machine.UID)
+	var servers []*client.Server
+	if serverID != "" {
+		server, err := c.GetServer(ctx, projectID, providerSpec.Region, serverID)
+		switch {
+		case errors.Is(err, client.ErrNotFound):
+			klog.V(2).Infof("Server %q already deleted for machine %q (idempotent)", serverID, req.Machine.Name)
+		case err != nil:
+			return nil, status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to get server: %v", err))
+		default:
+			if err := verifyServerOwnership(server, req.Machine.Name, req.MachineClass.Name, machineUID); err != nil {
+				klog.Errorf("Refusing to delete server %q for machine %q: %v", serverID, req.Machine.Name, err)
+				return nil, status.Error(codes.FailedPrecondition, err.Error())
+			}
+			if machineUID == "" {
+				machineUID = server.Labels[StackitMachineUIDLabel]
+			}
+			servers = append(servers, server)
 		}
+	}
 
-		if server != nil {
-			serverID = server.ID
+	// servers found by name carry the machine label, duplicates left by concurrent creates are deleted as well
+	// A server of the same name in another MachineClass sharing the project is not deleted
+	namedServers, err := listServersByName(ctx, c, projectID, providerSpec.Region, req.Machine.Name, machineUID)
+	if err != nil {
+		return nil, status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to find servers by name: %v", err))
+	}
+	for _, server := range namedServers {
+		if server.ID == serverID {
+			continue
 		}
+		if err := verifyServerOwnership(server, req.Machine.Name, req.MachineClass.Name, machineUID); err != nil {
+			klog.Warningf("Not deleting server %q found for machine %q: %v", server.ID, req.Machine.Name, err)
+			continue
+		}
+		servers = append(servers, server)
+	}
+	if len(servers) > 1 {
+		klog.Warningf("Found %d servers for machine %q, deleting all of them", len(servers), req.Machine.Name)
+		// the server CreateMachine keeps is deleted first, only the others count as duplicates
+		kept, duplicates := selectServer(servers, serverID)
+		servers = append([]*client.Server{kept}, duplicates...)
 	}
 
-	if serverID == "" {
+	if len(servers) == 0 {
 		klog.V(2).Infof("Server is already deleted for machine %q", req.Machine.Name)
-		return &driver.DeleteMachineResponse{}, nil
+	}
+	for i, server := range servers {
+		if providerSpec.ShutdownGracePeriod != nil && providerSpec.ShutdownGracePeriod.Duration > 0 {
+			if err := p.stopServer(ctx, c, projectID, providerSpec.Region, server, providerSpec.ShutdownGracePeriod.Duration, req.Machine.Name); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.deleteServer(ctx, c, projectID, providerSpec.Region, server.ID, req.Machine.Name); err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			duplicateServersDeleted.Inc()
+		}
 	}
 
+	// data volumes are detached once the server is gone
+	if err := deleteDataVolumes(ctx, c, projectID, providerSpec.Region, req.Machine.Name, machineUID); err != nil {
+		klog.Errorf("Failed to delete data volumes for machine %q: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to delete data volumes: %v", err))
+	}
+
+	if err := deleteNICs(ctx, c, projectID, providerSpec.Region, req.Machine.Name, req.MachineClass.Name, machineUID); err != nil {
+		klog.Errorf("Failed to delete NICs for machine %q: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to delete NICs: %v", err))
+	}
+
+	if err := releasePublicIPs(ctx, c, projectID, providerSpec.Region, req.Machine.Name, machineUID); err != nil {
+		klog.Errorf("Failed to release public IPs for machine %q: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to release public IPs: %v", err))
+	}
+
+	return &driver.DeleteMachineResponse{}, nil
+}
+
+// verifyServerOwnership returns an error unless the server carries the machine and machine class labels of the Machine
+// A server with another machine UID label belongs to an earlier Machine with the same name
+func verifyServerOwnership(server *client.Server, machineName, machineClassName, machineUID string) error {
+	owner, ok := server.Labels[StackitMachineLabel]
+	if !ok {
+		return fmt.Errorf("server %q has no %s label and is not managed by machine %q", server.ID, StackitMachineLabel, machineName)
+	}
+	if owner != machineName {
+		return fmt.Errorf("server %q belongs to machine %q, not to machine %q", server.ID, owner, machineName)
+	}
+	if class := server.Labels[StackitMachineClassLabel]; class != machineClassName {
+		return fmt.Errorf("server %q belongs to machine class %q, not to machine class %q", server.ID, class, machineClassName)
+	}
+	if !matchesMachineUID(server.Labels, machineUID) {
+		return fmt.Errorf("server %q belongs to an earlier machine %q with UID %q, not to UID %q", server.ID, owner, server.Labels[StackitMachineUIDLabel], machineUID)
+	}
+	return nil
+}
+
+// deleteNICs deletes the NICs labelled for the machine and its machine class once they are detached from the deleted server
+// InitializeMachine labels the NICs created along with the server, NICs attached to another server
+// and NICs of an earlier Machine with the same name are kept
+func deleteNICs(ctx context.Context, c client.StackitClient, projectID, region, machineName, machineClassName, machineUID string) error {
+	nics, err := c.ListNICs(ctx, projectID, region, map[string]string{
+		StackitMachineLabel:      machineName,
+		StackitMachineClassLabel: machineClassName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list NICs: %w", err)
+	}
+
+	for _, nic := range nics {
+		if !matchesMachineUID(nic.Labels, machineUID) {
+			klog.V(2).Infof("Keeping NIC %q of an earlier machine %q with UID %q", nic.ID, machineName, nic.Labels[StackitMachineUIDLabel])
+			continue
+		}
+		if nic.ServerID != "" {
+			klog.V(2).Infof("Keeping NIC %q of machine %q, it is attached to server %q", nic.ID, machineName, nic.ServerID)
+			continue
+		}
+
+		if err := c.DeleteNIC(ctx, projectID, region, nic.NetworkID, nic.ID); err != nil && !errors.Is(err, client.ErrNotFound) {
+			return fmt.Errorf("failed to delete NIC %q: %w", nic.ID, err)
+		}
+
+		klog.V(2).Infof("Deleted NIC %q of machine %q", nic.ID, machineName)
+	}
+
+	return nil
+}
+
+// stopServer stops a running server and waits up to the grace period until it is stopped
+// A server that does not stop in time is deleted anyway, servers that are not ACTIVE are not stopped
+func (p *Provider) stopServer(ctx context.Context, c client.StackitClient, projectID, region string, server *client.Server, gracePeriod time.Duration, machineName string) error {
+	if server.Status != serverStatusActive || isServerStopped(server) {
+		return nil
+	}
+
+	klog.V(2).Infof("Stopping server %q of machine %q with a shutdown grace period of %s", server.ID, machineName, gracePeriod)
+	if err := c.StopServer(ctx, projectID, region, server.ID); err != nil {
+		switch {
+		case errors.Is(err, client.ErrNotFound):
+			return nil
+		case errors.Is(err, client.ErrConflict):
+			// the server is already stopping or changing its state, wait for it anyway
+			klog.V(2).Infof("Server %q of machine %q cannot be stopped right now: %v", server.ID, machineName, err)
+		default:
+			klog.Errorf("Failed to stop server %q for machine %q: %v", server.ID, machineName, err)
+			return status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to stop server: %v", err))
+		}
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, p.pollingInterval, gracePeriod, false, func(ctx context.Context) (bool, error) {
+		server, err := c.GetServer(ctx, projectID, region, server.ID)
+		if err != nil {
+			if errors.Is(err, client.ErrNotFound) {
+				return true, nil
+			}
+			return false, err
+		}
+		return isServerStopped(server), nil
+	})
+	switch {
+	case err == nil:
+		klog.V(2).Infof("Server %q of machine %q has been stopped", server.ID, machineName)
+	case wait.Interrupted(err) && ctx.Err() == nil:
+		klog.Warningf("Server %q of machine %q did not stop within %s, deleting it", server.ID, machineName, gracePeriod)
+	default:
+		klog.Errorf("Failed waiting for server %q to be stopped for machine %q: %v", server.ID, machineName, err)
+		return status.Error(errorCode(err, codes.DeadlineExceeded), fmt.Sprintf("failed waiting for server to be stopped: %v", err))
+	}
+
+	return nil
+}
+
+// isServerStopped returns true if the server is shut off or does not run
+func isServerStopped(server *client.Server) bool {
+	return slices.Contains(stoppedServerStatuses, server.Status) || slices.Contains(stoppedPowerStatuses, server.PowerStatus)
+}
+
+// deleteServer deletes the server and waits until it is gone
+func (p *Provider) deleteServer(ctx context.Context, c client.StackitClient, projectID, region, serverID, machineName string) error {
 	// Call STACKIT API to delete server
-	err = p.client.DeleteServer(ctx, projectID, providerSpec.Region, serverID)
+	err := c.DeleteServer(ctx, projectID, region, serverID)
 	if err != nil {
 		// Check if server was not found (404) - this is OK for idempotency
-		if errors.Is(err, client.ErrServerNotFound) {
-			klog.V(2).Infof("Server %q already deleted for machine %q (idempotent)", serverID, req.Machine.Name)
-			return &driver.DeleteMachineResponse{}, nil
+		if errors.Is(err, client.ErrNotFound) {
+			klog.V(2).Infof("Server %q already deleted for machine %q (idempotent)", serverID, machineName)
+			return nil
 		}
-		// All other errors are internal errors
-		klog.Errorf("Failed to delete server for machine %q: %v", req.Machine.Name, err)
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to delete server: %v", err))
+		// All other errors are mapped by their class, unclassified errors are internal errors
+		klog.Errorf("Failed to delete server for machine %q: %v", machineName, err)
+		return status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to delete server: %v", err))
 	}
 
-	if err := p.WaitUntilServerDeleted(ctx, projectID, providerSpec.Region, serverID); err != nil {
-		klog.Errorf("Failed waiting for server %q to be deleted for machine %q: %v", serverID, req.Machine.Name, err)
-		return nil, status.Error(codes.DeadlineExceeded, fmt.Sprintf("failed waiting for server to be deleted: %v", err))
+	if err := p.WaitUntilServerDeleted(ctx, c, projectID, region, serverID); err != nil {
+		klog.Errorf("Failed waiting for server %q to be deleted for machine %q: %v", serverID, machineName, err)
+		return status.Error(errorCode(err, codes.DeadlineExceeded), fmt.Sprintf("failed waiting for server to be deleted: %v", err))
 	}
 
-	return &driver.DeleteMachineResponse{}, nil
+	return nil
 }
 
-func (p *Provider) WaitUntilServerDeleted(ctx context.Context, projectID, region, serverID string) error {
+func (p *Provider) WaitUntilServerDeleted(ctx context.Context, c client.StackitClient, projectID, region, serverID string) error {
 	return wait.PollUntilContextTimeout(ctx, p.pollingInterval, p.pollingTimeout, true, func(ctx context.Context) (bool, error) {
-		_, err := p.client.GetServer(ctx, projectID, region, serverID)
+		_, err := c.GetServer(ctx, projectID, region, serverID)
 		if err != nil {
 			// Server is deleted if we get a not found error
-			if errors.Is(err, client.ErrServerNotFound) {
+			if errors.Is(err, client.ErrNotFound) {
 				klog.V(2).Infof("Server %q has been deleted", serverID)
 				return true, nil
 			}
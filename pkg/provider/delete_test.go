@@ -11,6 +11,7 @@ import (
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/status"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
 	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
 	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
@@ -87,12 +88,12 @@ var _ = Describe("DeleteMachine", func() {
 
 	Context("with valid inputs", func() {
 		It("should successfully delete a machine", func() {
+			serverDeleted := false
 			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
+				serverDeleted = true
 				return nil
 			}
-			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
-				return nil, fmt.Errorf("%w: status 404", client.ErrServerNotFound)
-			}
+			mockClient.GetServerFunc = ownedServerUntil(&serverDeleted)
 
 			resp, err := provider.DeleteMachine(ctx, req)
 
@@ -104,14 +105,14 @@ var _ = Describe("DeleteMachine", func() {
 			var capturedProjectID string
 			var capturedServerID string
 
+			serverDeleted := false
 			mockClient.DeleteServerFunc = func(_ context.Context, projectID, _, serverID string) error {
 				capturedProjectID = projectID
 				capturedServerID = serverID
+				serverDeleted = true
 				return nil
 			}
-			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
-				return nil, fmt.Errorf("%w: status 404", client.ErrServerNotFound)
-			}
+			mockClient.GetServerFunc = ownedServerUntil(&serverDeleted)
 
 			_, err := provider.DeleteMachine(ctx, req)
 
@@ -128,12 +129,13 @@ var _ = Describe("DeleteMachine", func() {
 			}
 			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
 				getServerCallCount++
-				// First call returns server still exists, second call returns not found
-				if getServerCallCount == 1 {
+				// First calls return the server still exists, then it is not found
+				if getServerCallCount <= 2 {
 					return &client.Server{
 						ID:     "550e8400-e29b-41d4-a716-446655440000",
 						Name:   "test-machine",
 						Status: "SHUTTING_DOWN",
+						Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"},
 					}, nil
 				}
 				return nil, fmt.Errorf("%w: status 404", client.ErrServerNotFound)
@@ -143,7 +145,7 @@ var _ = Describe("DeleteMachine", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(resp).NotTo(BeNil())
-			Expect(getServerCallCount).To(BeNumerically(">=", 2))
+			Expect(getServerCallCount).To(BeNumerically(">=", 3))
 		})
 	})
 
@@ -191,6 +193,89 @@ var _ = Describe("DeleteMachine", func() {
 		})
 	})
 
+	Context("with data volumes", func() {
+		var serverDeleted bool
+
+		BeforeEach(func() {
+			serverDeleted = false
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
+				serverDeleted = true
+				return nil
+			}
+			mockClient.GetServerFunc = ownedServerUntil(&serverDeleted)
+			mockClient.ListVolumesFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.Volume, error) {
+				Expect(labelSelector).To(Equal(map[string]string{"kubernetes.io/machine": "test-machine"}))
+				return []*client.Volume{
+					{ID: "volume-data", Labels: map[string]string{"kubernetes.io/data-volume": "data", "kubernetes.io/delete-on-termination": "true"}},
+					{ID: "volume-wal", Labels: map[string]string{"kubernetes.io/data-volume": "wal", "kubernetes.io/delete-on-termination": "false"}},
+					{ID: "volume-other", Labels: map[string]string{"kubernetes.io/machine": "test-machine"}},
+				}, nil
+			}
+		})
+
+		It("should delete data volumes after the server is gone and keep retained ones", func() {
+			var deleted []string
+			mockClient.DeleteVolumeFunc = func(_ context.Context, _, _, volumeID string) error {
+				Expect(serverDeleted).To(BeTrue())
+				deleted = append(deleted, volumeID)
+				return nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal([]string{"volume-data"}))
+		})
+
+		It("should keep data volumes of an earlier machine with the same name", func() {
+			machine.UID = "new-uid"
+			mockClient.ListVolumesFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Volume, error) {
+				return []*client.Volume{
+					{ID: "volume-old", Labels: map[string]string{"kubernetes.io/data-volume": "data", "kubernetes.io/machine-uid": "old-uid"}},
+					{ID: "volume-new", Labels: map[string]string{"kubernetes.io/data-volume": "data", "kubernetes.io/machine-uid": "new-uid"}},
+				}, nil
+			}
+			var deleted []string
+			mockClient.DeleteVolumeFunc = func(_ context.Context, _, _, volumeID string) error {
+				deleted = append(deleted, volumeID)
+				return nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal([]string{"volume-new"}))
+		})
+
+		It("should delete data volumes when the server is already gone", func() {
+			machine.Spec.ProviderID = ""
+			serverDeleted = true
+			var deleted []string
+			mockClient.DeleteVolumeFunc = func(_ context.Context, _, _, volumeID string) error {
+				deleted = append(deleted, volumeID)
+				return nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal([]string{"volume-data"}))
+		})
+
+		It("should return Aborted when a data volume is still attached", func() {
+			mockClient.DeleteVolumeFunc = func(_ context.Context, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrConflict, StatusCode: 409}
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Aborted))
+		})
+	})
+
 	Context("when STACKIT API fails", func() {
 		It("should return error when API call fails", func() {
 			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
@@ -205,4 +290,478 @@ var _ = Describe("DeleteMachine", func() {
 			Expect(statusErr.Code()).To(Equal(codes.Internal))
 		})
 	})
+
+	Context("with ownership verification", func() {
+		var deleteCalled bool
+
+		BeforeEach(func() {
+			deleteCalled = false
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
+				deleteCalled = true
+				return nil
+			}
+		})
+
+		It("should refuse to delete a server labelled for another machine", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Name: "other-machine", Labels: map[string]string{"kubernetes.io/machine": "other-machine"}}, nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.FailedPrecondition))
+			Expect(statusErr.Message()).To(ContainSubstring(`belongs to machine "other-machine"`))
+			Expect(deleteCalled).To(BeFalse())
+		})
+
+		It("should refuse to delete a server without machine label", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Name: "test-machine"}, nil
+			}
+			volumesListed := false
+			mockClient.ListVolumesFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Volume, error) {
+				volumesListed = true
+				return nil, nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.FailedPrecondition))
+			Expect(deleteCalled).To(BeFalse())
+			Expect(volumesListed).To(BeFalse())
+		})
+
+		It("should clean up without deleting when the server of the ProviderID is already gone", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+			}
+			volumesListed := false
+			mockClient.ListVolumesFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Volume, error) {
+				volumesListed = true
+				return nil, nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteCalled).To(BeFalse())
+			Expect(volumesListed).To(BeTrue())
+		})
+
+		It("should refuse to delete a server of an earlier machine with the same name", func() {
+			machine.UID = "new-uid"
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Labels: map[string]string{
+					"kubernetes.io/machine":      "test-machine",
+					"kubernetes.io/machineclass": "test-machine-class",
+					"kubernetes.io/machine-uid":  "old-uid",
+				}}, nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.FailedPrecondition))
+			Expect(statusErr.Message()).To(ContainSubstring(`earlier machine "test-machine"`))
+			Expect(deleteCalled).To(BeFalse())
+		})
+
+		It("should refuse to delete a server of another machine class", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Labels: map[string]string{
+					"kubernetes.io/machine":      "test-machine",
+					"kubernetes.io/machineclass": "other-machine-class",
+				}}, nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.FailedPrecondition))
+			Expect(statusErr.Message()).To(ContainSubstring(`machine class "other-machine-class"`))
+			Expect(deleteCalled).To(BeFalse())
+		})
+
+		It("should map errors reading the server", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return nil, &client.APIError{Class: client.ErrForbidden, StatusCode: 403}
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.PermissionDenied))
+			Expect(deleteCalled).To(BeFalse())
+		})
+	})
+
+	Context("with shutdown grace period", func() {
+		var (
+			calls       []string
+			stopped     bool
+			deleted     bool
+			serverState func() *client.Server
+		)
+
+		BeforeEach(func() {
+			providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{
+				MachineType:         "c2i.2",
+				ImageID:             "image-uuid-123",
+				Region:              "eu01",
+				ShutdownGracePeriod: &metav1.Duration{Duration: 200 * time.Millisecond},
+			})
+			machineClass.ProviderSpec.Raw = providerSpecRaw
+
+			calls = nil
+			stopped = false
+			deleted = false
+			serverState = func() *client.Server {
+				server := &client.Server{
+					ID:          "550e8400-e29b-41d4-a716-446655440000",
+					Status:      "ACTIVE",
+					PowerStatus: "RUNNING",
+					Labels:      map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"},
+				}
+				if stopped {
+					server.Status = "INACTIVE"
+					server.PowerStatus = "STOPPED"
+				}
+				return server
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				if deleted {
+					return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+				}
+				return serverState(), nil
+			}
+			mockClient.StopServerFunc = func(_ context.Context, _, _, _ string) error {
+				calls = append(calls, "stop")
+				stopped = true
+				return nil
+			}
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
+				calls = append(calls, "delete")
+				deleted = true
+				return nil
+			}
+		})
+
+		It("should stop the server before deleting it", func() {
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal([]string{"stop", "delete"}))
+		})
+
+		It("should delete the server when it does not stop within the grace period", func() {
+			mockClient.StopServerFunc = func(_ context.Context, _, _, _ string) error {
+				calls = append(calls, "stop")
+				return nil
+			}
+
+			start := time.Now()
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically(">=", 200*time.Millisecond))
+			Expect(calls).To(Equal([]string{"stop", "delete"}))
+		})
+
+		It("should not stop a server that is already stopped", func() {
+			stopped = true
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal([]string{"delete"}))
+		})
+
+		It("should wait for a server that cannot be stopped right now", func() {
+			polls := 0
+			mockClient.StopServerFunc = func(_ context.Context, _, _, _ string) error {
+				calls = append(calls, "stop")
+				return &client.APIError{Class: client.ErrConflict, StatusCode: 409}
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				if deleted {
+					return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+				}
+				polls++
+				stopped = polls > 2
+				return serverState(), nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal([]string{"stop", "delete"}))
+			Expect(polls).To(BeNumerically(">", 2))
+		})
+
+		It("should not delete the server when it cannot be stopped", func() {
+			mockClient.StopServerFunc = func(_ context.Context, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrForbidden, StatusCode: 403}
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.PermissionDenied))
+			Expect(deleted).To(BeFalse())
+		})
+
+		It("should not stop the server without shutdown grace period", func() {
+			providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{
+				MachineType: "c2i.2",
+				ImageID:     "image-uuid-123",
+				Region:      "eu01",
+			})
+			machineClass.ProviderSpec.Raw = providerSpecRaw
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal([]string{"delete"}))
+		})
+	})
+
+	Context("with duplicate servers", func() {
+		It("should delete the server of the ProviderID and all duplicates", func() {
+			deleted := map[string]bool{}
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.Server, error) {
+				Expect(labelSelector).To(Equal(map[string]string{"kubernetes.io/machine": "test-machine"}))
+				return []*client.Server{
+					{ID: "550e8400-e29b-41d4-a716-446655440000", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"}},
+					{ID: "duplicate", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"}},
+				}, nil
+			}
+			var deleteOrder []string
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				deleteOrder = append(deleteOrder, serverID)
+				deleted[serverID] = true
+				return nil
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				if deleted[serverID] {
+					return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+				}
+				return &client.Server{ID: serverID, Status: "ACTIVE", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"}}, nil
+			}
+			deletedCount := testutil.ToFloat64(duplicateServersDeleted)
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteOrder).To(Equal([]string{"550e8400-e29b-41d4-a716-446655440000", "duplicate"}))
+			Expect(testutil.ToFloat64(duplicateServersDeleted)).To(Equal(deletedCount + 1))
+		})
+
+		It("should only delete servers with the UID of the ProviderID server for a Machine without UID", func() {
+			servers := map[string]*client.Server{
+				"550e8400-e29b-41d4-a716-446655440000": {ID: "550e8400-e29b-41d4-a716-446655440000", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class", "kubernetes.io/machine-uid": "old-uid"}},
+				"stale-duplicate":                      {ID: "stale-duplicate", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class", "kubernetes.io/machine-uid": "old-uid"}},
+				"current":                              {ID: "current", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class", "kubernetes.io/machine-uid": "new-uid"}},
+			}
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{servers["current"], servers["stale-duplicate"], servers["550e8400-e29b-41d4-a716-446655440000"]}, nil
+			}
+			deleted := map[string]bool{}
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				deleted[serverID] = true
+				return nil
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				if deleted[serverID] {
+					return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+				}
+				return servers[serverID], nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(map[string]bool{"550e8400-e29b-41d4-a716-446655440000": true, "stale-duplicate": true}))
+		})
+
+		It("should delete all duplicates when ProviderID is missing", func() {
+			machine.Spec.ProviderID = ""
+			deleted := map[string]bool{}
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{
+					{ID: "server-1", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"}},
+					{ID: "server-2", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"}},
+				}, nil
+			}
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				deleted[serverID] = true
+				return nil
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(map[string]bool{"server-1": true, "server-2": true}))
+		})
+
+		It("should count the servers selectServer does not keep as duplicates", func() {
+			machine.Spec.ProviderID = ""
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{
+					{ID: "server-creating", Status: "CREATING", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"}},
+					{ID: "server-active", Status: "ACTIVE", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"}},
+				}, nil
+			}
+			var deleteOrder []string
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				deleteOrder = append(deleteOrder, serverID)
+				return nil
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+			}
+			deletedCount := testutil.ToFloat64(duplicateServersDeleted)
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteOrder).To(Equal([]string{"server-active", "server-creating"}))
+			Expect(testutil.ToFloat64(duplicateServersDeleted)).To(Equal(deletedCount + 1))
+		})
+
+		It("should not delete a server of the same name in another machine class", func() {
+			machine.Spec.ProviderID = ""
+			deleted := map[string]bool{}
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{
+					{ID: "server-1", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"}},
+					{ID: "other-class", Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "other-machine-class"}},
+				}, nil
+			}
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				deleted[serverID] = true
+				return nil
+			}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(map[string]bool{"server-1": true}))
+		})
+	})
+
+	Context("with NICs", func() {
+		var serverDeleted bool
+
+		BeforeEach(func() {
+			serverDeleted = false
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
+				serverDeleted = true
+				return nil
+			}
+			mockClient.GetServerFunc = ownedServerUntil(&serverDeleted)
+			mockClient.ListNICsFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.NIC, error) {
+				Expect(labelSelector).To(Equal(map[string]string{
+					"kubernetes.io/machine":      "test-machine",
+					"kubernetes.io/machineclass": "test-machine-class",
+				}))
+				return []*client.NIC{
+					{ID: "nic-detached", NetworkID: "network-1"},
+					{ID: "nic-attached", NetworkID: "network-1", ServerID: "other-server"},
+				}, nil
+			}
+		})
+
+		It("should delete detached NICs after the server is gone and keep attached ones", func() {
+			var deleted []string
+			mockClient.DeleteNICFunc = func(_ context.Context, _, _, networkID, nicID string) error {
+				Expect(serverDeleted).To(BeTrue())
+				Expect(networkID).To(Equal("network-1"))
+				deleted = append(deleted, nicID)
+				return nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal([]string{"nic-detached"}))
+		})
+
+		It("should keep NICs of an earlier machine with the same name", func() {
+			machine.UID = "new-uid"
+			mockClient.ListNICsFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.NIC, error) {
+				return []*client.NIC{
+					{ID: "nic-old", NetworkID: "network-1", Labels: map[string]string{"kubernetes.io/machine-uid": "old-uid"}},
+					{ID: "nic-new", NetworkID: "network-1", Labels: map[string]string{"kubernetes.io/machine-uid": "new-uid"}},
+				}, nil
+			}
+			var deleted []string
+			mockClient.DeleteNICFunc = func(_ context.Context, _, _, _, nicID string) error {
+				deleted = append(deleted, nicID)
+				return nil
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal([]string{"nic-new"}))
+		})
+
+		It("should ignore NICs that are already deleted", func() {
+			mockClient.DeleteNICFunc = func(_ context.Context, _, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return Aborted when a NIC cannot be deleted yet", func() {
+			mockClient.DeleteNICFunc = func(_ context.Context, _, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrConflict, StatusCode: 409}
+			}
+
+			_, err := provider.DeleteMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Aborted))
+		})
+	})
 })
+
+// ownedServerUntil returns a GetServerFunc returning a server labelled for test-machine until deleted is set
+func ownedServerUntil(deleted *bool) func(context.Context, string, string, string) (*client.Server, error) {
+	return func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+		if *deleted {
+			return nil, fmt.Errorf("%w: status 404", client.ErrServerNotFound)
+		}
+		return &client.Server{
+			ID:     serverID,
+			Name:   "test-machine",
+			Status: "ACTIVE",
+			Labels: map[string]string{"kubernetes.io/machine": "test-machine", "kubernetes.io/machineclass": "test-machine-class"},
+		}, nil
+	}
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"k8s.io/klog/v2"
+)
+
+// listServersByName returns all servers carrying the machine label of the machine
+// More than one server is returned if concurrent creates for the machine raced each other.
+// Servers created for an earlier Machine with the same name are skipped, they are left to MCM's orphan collection.
+func listServersByName(ctx context.Context, c client.StackitClient, projectID, region, machineName, machineUID string) ([]*client.Server, error) {
+	labelSelector := map[string]string{
+		StackitMachineLabel: machineName,
+	}
+	servers, err := c.ListServers(ctx, projectID, region, labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("SDK ListServers with labelSelector: %v failed: %w", labelSelector, err)
+	}
+
+	return slices.DeleteFunc(servers, func(server *client.Server) bool {
+		if matchesMachineUID(server.Labels, machineUID) {
+			return false
+		}
+		klog.V(2).Infof("Ignoring server %q of an earlier machine %q with UID %q", server.ID, machineName, server.Labels[StackitMachineUIDLabel])
+		return true
+	}), nil
+}
+
+// matchesMachineUID returns false if the labels record another Machine with the same name
+// Resources labelled before the UID label was introduced and Machines without UID match any resource
+func matchesMachineUID(labels map[string]string, machineUID string) bool {
+	uid, ok := labels[StackitMachineUIDLabel]
+	return machineUID == "" || !ok || uid == machineUID
+}
+
+// selectServer returns the server to keep out of the servers of a machine and the duplicates
+//
+// The server of the ProviderID is kept if it is among them, otherwise the oldest ACTIVE server,
+// otherwise the oldest server. Ties are broken by ID, so every call keeps the same server.
+func selectServer(servers []*client.Server, preferredServerID string) (*client.Server, []*client.Server) {
+	rank := func(server *client.Server) int {
+		switch {
+		case preferredServerID != "" && server.ID == preferredServerID:
+			return 0
+		case server.Status == serverStatusActive:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	sorted := slices.Clone(servers)
+	slices.SortFunc(sorted, func(a, b *client.Server) int {
+		if c := cmp.Compare(rank(a), rank(b)); c != 0 {
+			return c
+		}
+		if c := compareCreatedAt(a, b); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return sorted[0], sorted[1:]
+}
+
+// compareCreatedAt orders servers by creation time, servers without creation time come last
+func compareCreatedAt(a, b *client.Server) int {
+	switch {
+	case a.CreatedAt == nil && b.CreatedAt == nil:
+		return 0
+	case a.CreatedAt == nil:
+		return 1
+	case b.CreatedAt == nil:
+		return -1
+	}
+	return a.CreatedAt.Compare(*b.CreatedAt)
+}
+
+// deleteDuplicateServers deletes the servers that lost a create race for the machine
+// Failed deletions are only logged, the duplicates are found again on the next lookup
+func deleteDuplicateServers(ctx context.Context, c client.StackitClient, projectID, region, machineName string, kept *client.Server, duplicates []*client.Server) {
+	if len(duplicates) == 0 {
+		return
+	}
+
+	klog.Warningf("Found %d duplicate servers for machine %q, keeping server %q", len(duplicates), machineName, kept.ID)
+	for _, server := range duplicates {
+		if err := c.DeleteServer(ctx, projectID, region, server.ID); err != nil {
+			if !errors.Is(err, client.ErrNotFound) {
+				klog.Errorf("Failed to delete duplicate server %q for machine %q: %v", server.ID, machineName, err)
+			}
+			continue
+		}
+		duplicateServersDeleted.Inc()
+		klog.Warningf("Deleted duplicate server %q (status %s) for machine %q", server.ID, server.Status, machineName)
+	}
+}
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
+)
+
+var _ = Describe("Duplicate servers", func() {
+	const (
+		projectID = "11111111-2222-3333-4444-555555555555"
+		region    = "eu01"
+	)
+
+	var (
+		ctx        context.Context
+		mockClient *mock.StackitClient
+		created    time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockClient = &mock.StackitClient{}
+		created = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	server := func(id, status string, age time.Duration) *client.Server {
+		createdAt := created.Add(-age)
+		return &client.Server{ID: id, Status: status, CreatedAt: &createdAt}
+	}
+
+	ids := func(servers []*client.Server) []string {
+		result := make([]string, 0, len(servers))
+		for _, server := range servers {
+			result = append(result, server.ID)
+		}
+		return result
+	}
+
+	Describe("selectServer", func() {
+		It("should keep the oldest ACTIVE server", func() {
+			kept, duplicates := selectServer([]*client.Server{
+				server("creating", "CREATING", 3*time.Hour),
+				server("active-new", "ACTIVE", time.Minute),
+				server("active-old", "ACTIVE", time.Hour),
+			}, "")
+
+			Expect(kept.ID).To(Equal("active-old"))
+			Expect(ids(duplicates)).To(Equal([]string{"active-new", "creating"}))
+		})
+
+		It("should keep the server of the ProviderID", func() {
+			kept, duplicates := selectServer([]*client.Server{
+				server("active-old", "ACTIVE", time.Hour),
+				server("provider-id", "CREATING", time.Minute),
+			}, "provider-id")
+
+			Expect(kept.ID).To(Equal("provider-id"))
+			Expect(ids(duplicates)).To(Equal([]string{"active-old"}))
+		})
+
+		It("should keep the oldest server if none is ACTIVE and break ties by ID", func() {
+			servers := []*client.Server{
+				server("b", "CREATING", time.Hour),
+				{ID: "no-creation-time", Status: "CREATING"},
+				server("a", "CREATING", time.Hour),
+			}
+
+			kept, duplicates := selectServer(servers, "unknown")
+
+			Expect(kept.ID).To(Equal("a"))
+			Expect(ids(duplicates)).To(Equal([]string{"b", "no-creation-time"}))
+			Expect(ids(servers)).To(Equal([]string{"b", "no-creation-time", "a"}))
+		})
+	})
+
+	Describe("getServerByName", func() {
+		It("should return the kept server and delete the duplicates", func() {
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.Server, error) {
+				Expect(labelSelector).To(Equal(map[string]string{StackitMachineLabel: "machine-1"}))
+				return []*client.Server{
+					server("active-new", "ACTIVE", time.Minute),
+					server("active-old", "ACTIVE", time.Hour),
+				}, nil
+			}
+			var deleted []string
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				deleted = append(deleted, serverID)
+				return nil
+			}
+			deletedCount := testutil.ToFloat64(duplicateServersDeleted)
+
+			kept, err := getServerByName(ctx, mockClient, projectID, region, "machine-1", "", "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kept.ID).To(Equal("active-old"))
+			Expect(deleted).To(Equal([]string{"active-new"}))
+			Expect(testutil.ToFloat64(duplicateServersDeleted)).To(Equal(deletedCount + 1))
+		})
+
+		It("should return the kept server when a duplicate cannot be deleted", func() {
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{
+					server("active-new", "ACTIVE", time.Minute),
+					server("active-old", "ACTIVE", time.Hour),
+				}, nil
+			}
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrConflict, StatusCode: 409}
+			}
+			deletedCount := testutil.ToFloat64(duplicateServersDeleted)
+
+			kept, err := getServerByName(ctx, mockClient, projectID, region, "machine-1", "", "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kept.ID).To(Equal("active-old"))
+			Expect(testutil.ToFloat64(duplicateServersDeleted)).To(Equal(deletedCount))
+		})
+
+		It("should ignore servers of an earlier machine with the same name", func() {
+			stale := server("stale", "ACTIVE", time.Hour)
+			stale.Labels = map[string]string{StackitMachineUIDLabel: "old-uid"}
+			current := server("current", "CREATING", time.Minute)
+			current.Labels = map[string]string{StackitMachineUIDLabel: "new-uid"}
+			legacy := server("legacy", "CREATING", 2*time.Minute)
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{stale, current, legacy}, nil
+			}
+			var deleted []string
+			mockClient.DeleteServerFunc = func(_ context.Context, _, _, serverID string) error {
+				deleted = append(deleted, serverID)
+				return nil
+			}
+
+			kept, err := getServerByName(ctx, mockClient, projectID, region, "machine-1", "new-uid", "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kept.ID).To(Equal("legacy"))
+			Expect(deleted).To(Equal([]string{"current"}))
+		})
+
+		It("should return nil if only servers of an earlier machine exist", func() {
+			stale := server("stale", "ACTIVE", time.Hour)
+			stale.Labels = map[string]string{StackitMachineUIDLabel: "old-uid"}
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{stale}, nil
+			}
+
+			kept, err := getServerByName(ctx, mockClient, projectID, region, "machine-1", "new-uid", "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kept).To(BeNil())
+		})
+
+		It("should return nil without servers", func() {
+			kept, err := getServerByName(ctx, mockClient, projectID, region, "machine-1", "", "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kept).To(BeNil())
+		})
+	})
+})
@@ -1,29 +1,117 @@
 package provider
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
 	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/install"
+	apiv1alpha1 "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/v1alpha1"
 	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	sigsjson "sigs.k8s.io/json"
 )
 
+// providerSpecScheme knows all versions of the ProviderSpec API
+var providerSpecScheme = runtime.NewScheme()
+
+func init() {
+	install.Install(providerSpecScheme)
+}
+
 // decodeProviderSpec decodes the ProviderSpec from a MachineClass
+// The ProviderSpec is decoded as the version of its apiVersion, defaulted and converted to the internal version
 func decodeProviderSpec(machineClass *v1alpha1.MachineClass) (*api.ProviderSpec, error) {
 	if machineClass == nil {
 		return nil, fmt.Errorf("machineClass is nil")
 	}
 
-	var providerSpec *api.ProviderSpec
-	if err := json.Unmarshal(machineClass.ProviderSpec.Raw, &providerSpec); err != nil {
+	raw := machineClass.ProviderSpec.Raw
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil, nil
+	}
+	versioned, err := newVersionedProviderSpec(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, versioned); err != nil {
 		return nil, fmt.Errorf("failed to decode ProviderSpec: %w", err)
 	}
 
+	providerSpecScheme.Default(versioned)
+	providerSpec := &api.ProviderSpec{}
+	if err := providerSpecScheme.Convert(versioned, providerSpec, nil); err != nil {
+		return nil, fmt.Errorf("failed to convert ProviderSpec: %w", err)
+	}
 	return providerSpec, nil
 }
 
+// newVersionedProviderSpec returns an empty ProviderSpec of the version named by the apiVersion and kind of the raw ProviderSpec
+// ProviderSpecs without apiVersion are decoded as v1alpha1, MachineClasses created before the API was versioned have none
+func newVersionedProviderSpec(raw []byte) (runtime.Object, error) {
+	typeMeta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, typeMeta); err != nil {
+		return nil, fmt.Errorf("failed to decode ProviderSpec: %w", err)
+	}
+	if typeMeta.APIVersion == "" {
+		typeMeta.APIVersion = apiv1alpha1.SchemeGroupVersion.String()
+	}
+	if typeMeta.Kind == "" {
+		typeMeta.Kind = "ProviderSpec"
+	}
+
+	gvk := typeMeta.GroupVersionKind()
+	if gvk.Version == runtime.APIVersionInternal || !providerSpecScheme.Recognizes(gvk) {
+		return nil, fmt.Errorf("unsupported ProviderSpec apiVersion %q and kind %q", typeMeta.APIVersion, typeMeta.Kind)
+	}
+	return providerSpecScheme.New(gvk)
+}
+
+// unknownProviderSpecFields returns an error for every field of the raw ProviderSpec that is not part of its ProviderSpec version
+// decodeProviderSpec drops these fields, so a misspelled key would silently change the created servers
+// Keys are matched case-sensitively, keys that only differ in case are decoded but reported as well
+func unknownProviderSpecFields(machineClass *v1alpha1.MachineClass) field.ErrorList {
+	versioned, err := newVersionedProviderSpec(machineClass.ProviderSpec.Raw)
+	if err != nil {
+		// malformed ProviderSpecs and unsupported versions are reported by decodeProviderSpec
+		return nil
+	}
+	strictErrs, err := sigsjson.UnmarshalStrict(machineClass.ProviderSpec.Raw, versioned, sigsjson.DisallowUnknownFields)
+	if err != nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	root := field.NewPath("providerSpec")
+	for _, strictErr := range strictErrs {
+		var fieldErr sigsjson.FieldError
+		if errors.As(strictErr, &fieldErr) {
+			errs = append(errs, field.Forbidden(root.Child(fieldErr.FieldPath()), "unknown field"))
+		}
+	}
+	return errs
+}
+
+// checkUnknownFields returns the unknown fields of the ProviderSpec as errors
+// In lenient mode they are only logged as warnings
+func (p *Provider) checkUnknownFields(machineClass *v1alpha1.MachineClass) field.ErrorList {
+	unknownFields := unknownProviderSpecFields(machineClass)
+	if len(unknownFields) > 0 && p.lenientDecoding {
+		klog.Warningf("Ignoring unknown fields of machine class %q: %v", machineClass.Name, unknownFields.ToAggregate())
+		return nil
+	}
+	return unknownFields
+}
+
 // encodeProviderSpecForResponse encodes a ProviderSpec to JSON bytes
 func encodeProviderSpecForResponse(spec *api.ProviderSpec) ([]byte, error) {
 	return json.Marshal(spec)
@@ -59,7 +147,24 @@ func extractSecretCredentials(secretData map[string][]byte) (projectID, serviceA
 	return projectID, serviceAccountKey
 }
 
-func isResourceExhaustedError(err error) bool {
-	errMsg := strings.ToLower(err.Error())
-	return strings.Contains(errMsg, "no valid host") || strings.Contains(errMsg, "quota exceeded")
+// errorCode maps a STACKIT client error to the MCM error code
+// Errors that are not classified by the client fall back to the given code
+func errorCode(err error, fallback codes.Code) codes.Code {
+	switch {
+	case errors.Is(err, client.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, client.ErrQuotaExceeded), errors.Is(err, client.ErrCapacityExhausted):
+		return codes.ResourceExhausted
+	case errors.Is(err, client.ErrConflict):
+		return codes.Aborted
+	case errors.Is(err, client.ErrUnauthenticated):
+		return codes.Unauthenticated
+	case errors.Is(err, client.ErrForbidden):
+		return codes.PermissionDenied
+	case errors.Is(err, client.ErrNotImplemented):
+		return codes.Unimplemented
+	case errors.Is(err, client.ErrRateLimited), errors.Is(err, client.ErrServerError):
+		return codes.Unavailable
+	}
+	return fallback
 }
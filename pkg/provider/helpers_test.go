@@ -2,10 +2,13 @@ package provider
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
 	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -111,6 +114,35 @@ var _ = Describe("Helpers", func() {
 				Expect(spec.MachineType).To(Equal("c2i.2"))
 				Expect(spec.ImageID).To(Equal("image-123"))
 			})
+
+			It("should decode a ProviderSpec with apiVersion and kind", func() {
+				machineClass := &v1alpha1.MachineClass{
+					ProviderSpec: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"machine.stackit.cloud/v1alpha1","kind":"ProviderSpec","machineType":"c2i.2","metadata":{"env":{"stage":"prod"}}}`),
+					},
+				}
+
+				spec, err := decodeProviderSpec(machineClass)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.MachineType).To(Equal("c2i.2"))
+				Expect(spec.Metadata).To(Equal(api.Metadata{"env": map[string]any{"stage": "prod"}}))
+			})
+
+			It("should default deleteOnTermination of boot and data volumes", func() {
+				machineClass := &v1alpha1.MachineClass{
+					ProviderSpec: runtime.RawExtension{
+						Raw: []byte(`{"bootVolume":{"size":20},"dataVolumes":[{"name":"data","size":10},{"name":"wal","size":10,"deleteOnTermination":false}]}`),
+					},
+				}
+
+				spec, err := decodeProviderSpec(machineClass)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.BootVolume.DeleteOnTermination).To(Equal(new(true)))
+				Expect(spec.DataVolumes[0].DeleteOnTermination).To(Equal(new(true)))
+				Expect(spec.DataVolumes[1].DeleteOnTermination).To(Equal(new(false)))
+			})
 		})
 
 		Context("with invalid MachineClass", func() {
@@ -138,6 +170,64 @@ var _ = Describe("Helpers", func() {
 				Expect(err.Error()).To(ContainSubstring("failed to decode"))
 				Expect(spec).To(BeNil())
 			})
+
+			It("should fail when the apiVersion is not supported", func() {
+				machineClass := &v1alpha1.MachineClass{
+					ProviderSpec: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"machine.stackit.cloud/v2","kind":"ProviderSpec","machineType":"c2i.2"}`),
+					},
+				}
+
+				spec, err := decodeProviderSpec(machineClass)
+
+				Expect(err).To(MatchError(ContainSubstring(`unsupported ProviderSpec apiVersion "machine.stackit.cloud/v2"`)))
+				Expect(spec).To(BeNil())
+			})
+
+			It("should fail for the internal version", func() {
+				machineClass := &v1alpha1.MachineClass{
+					ProviderSpec: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"machine.stackit.cloud/__internal","machineType":"c2i.2"}`),
+					},
+				}
+
+				_, err := decodeProviderSpec(machineClass)
+
+				Expect(err).To(MatchError(ContainSubstring("unsupported ProviderSpec")))
+			})
+		})
+	})
+
+	Describe("unknownProviderSpecFields", func() {
+		It("should report the paths of all unknown fields", func() {
+			machineClass := &v1alpha1.MachineClass{
+				ProviderSpec: runtime.RawExtension{
+					Raw: []byte(`{"machineType":"c2i.2","securityGroup":["sg"],"bootvolume":{"size":20},"dataVolumes":[{"name":"data","sizeGB":10}],"metadata":{"any":"key"}}`),
+				},
+			}
+
+			errs := unknownProviderSpecFields(machineClass)
+
+			Expect(errs).To(HaveLen(3))
+			Expect(errs.ToAggregate().Error()).To(ContainSubstring("providerSpec.securityGroup: Forbidden: unknown field"))
+			Expect(errs.ToAggregate().Error()).To(ContainSubstring("providerSpec.bootvolume: Forbidden: unknown field"))
+			Expect(errs.ToAggregate().Error()).To(ContainSubstring("providerSpec.dataVolumes[0].sizeGB: Forbidden: unknown field"))
+		})
+
+		It("should not report anything for a known ProviderSpec", func() {
+			machineClass := &v1alpha1.MachineClass{
+				ProviderSpec: runtime.RawExtension{Raw: []byte(`{"machineType":"c2i.2","securityGroups":["sg"]}`)},
+			}
+
+			Expect(unknownProviderSpecFields(machineClass)).To(BeEmpty())
+		})
+
+		It("should accept apiVersion and kind", func() {
+			machineClass := &v1alpha1.MachineClass{
+				ProviderSpec: runtime.RawExtension{Raw: []byte(`{"apiVersion":"machine.stackit.cloud/v1alpha1","kind":"ProviderSpec","machineType":"c2i.2"}`)},
+			}
+
+			Expect(unknownProviderSpecFields(machineClass)).To(BeEmpty())
 		})
 	})
 
@@ -172,4 +262,28 @@ var _ = Describe("Helpers", func() {
 			})
 		})
 	})
+
+	Describe("errorCode", func() {
+		DescribeTable("should map error classes to MCM codes",
+			func(class error, expected codes.Code) {
+				err := fmt.Errorf("SDK call failed: %w", &client.APIError{Class: class, StatusCode: 400})
+
+				Expect(errorCode(err, codes.Internal)).To(Equal(expected))
+			},
+			Entry("not found", client.ErrNotFound, codes.NotFound),
+			Entry("conflict", client.ErrConflict, codes.Aborted),
+			Entry("quota exceeded", client.ErrQuotaExceeded, codes.ResourceExhausted),
+			Entry("capacity exhausted", client.ErrCapacityExhausted, codes.ResourceExhausted),
+			Entry("unauthenticated", client.ErrUnauthenticated, codes.Unauthenticated),
+			Entry("forbidden", client.ErrForbidden, codes.PermissionDenied),
+			Entry("not implemented", client.ErrNotImplemented, codes.Unimplemented),
+			Entry("rate limited", client.ErrRateLimited, codes.Unavailable),
+			Entry("server error", client.ErrServerError, codes.Unavailable),
+		)
+
+		It("should fall back to the given code for unclassified errors", func() {
+			Expect(errorCode(fmt.Errorf("connection reset"), codes.Unavailable)).To(Equal(codes.Unavailable))
+			Expect(errorCode(&client.APIError{StatusCode: 400}, codes.Internal)).To(Equal(codes.Internal))
+		})
+	})
 })
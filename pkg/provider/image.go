@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	"k8s.io/klog/v2"
+)
+
+// DefaultImageCacheTTL is how long an image resolved from an image selector is reused
+// New image releases are picked up by new machines once the entry expires
+const DefaultImageCacheTTL = 10 * time.Minute
+
+// imageStatusAvailable is the status of images that servers can be created from
+const imageStatusAvailable = "AVAILABLE"
+
+// errNoMatchingImage is returned when no available image matches the image selector
+var errNoMatchingImage = errors.New("no matching image found")
+
+// imageCacheEntry is a resolved image ID and the time it expires
+type imageCacheEntry struct {
+	imageID string
+	expires time.Time
+}
+
+// imageCache holds resolved image IDs keyed by MachineClass, project, region and selector
+//
+// Design: Resolve once per MachineClass
+// - All machines of a MachineClass use the same image until the entry expires
+// - A changed selector results in a different key, so it is resolved right away
+// - A nil cache resolves on every call
+type imageCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]imageCacheEntry
+	now     func() time.Time // injectable for tests
+}
+
+// newImageCache returns an empty imageCache whose entries expire after ttl
+func newImageCache(ttl time.Duration) *imageCache {
+	return &imageCache{
+		ttl:     ttl,
+		entries: make(map[string]imageCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// resolve returns the ID of the image selected for the MachineClass, listing images if the cache has no valid entry
+// Thread-safe: the mutex is not held while images are listed, concurrent misses resolve independently
+func (ic *imageCache) resolve(ctx context.Context, c client.StackitClient, projectID, region, machineClassName string, selector *api.ImageSelectorSpec) (string, error) {
+	if ic == nil {
+		return resolveImage(ctx, c, projectID, region, selector)
+	}
+
+	key := imageCacheKey(projectID, region, machineClassName, selector)
+
+	ic.mu.Lock()
+	entry, ok := ic.entries[key]
+	ic.mu.Unlock()
+	if ok && ic.now().Before(entry.expires) {
+		return entry.imageID, nil
+	}
+
+	imageID, err := resolveImage(ctx, c, projectID, region, selector)
+	if err != nil {
+		return "", err
+	}
+
+	ic.mu.Lock()
+	ic.entries[key] = imageCacheEntry{imageID: imageID, expires: ic.now().Add(ic.ttl)}
+	ic.mu.Unlock()
+
+	return imageID, nil
+}
+
+// imageCacheKey returns the cache key of an image selector of a MachineClass
+func imageCacheKey(projectID, region, machineClassName string, selector *api.ImageSelectorSpec) string {
+	return strings.Join([]string{
+		projectID, region, machineClassName,
+		selector.Name, selector.Version, selector.Distro, selector.Architecture, selector.Scope,
+	}, "\x00")
+}
+
+// resolveImage returns the ID of the best available image matching the selector
+// The image with the highest OS version wins, ties are broken by the newest creation time
+func resolveImage(ctx context.Context, c client.StackitClient, projectID, region string, selector *api.ImageSelectorSpec) (string, error) {
+	images, err := c.ListImages(ctx, projectID, region)
+	if err != nil {
+		return "", fmt.Errorf("failed to list images: %w", err)
+	}
+
+	var constraint *semver.Constraints
+	if selector.Version != "" {
+		constraint, err = semver.NewConstraint(selector.Version)
+		if err != nil {
+			return "", fmt.Errorf("invalid image version constraint %q: %w", selector.Version, err)
+		}
+	}
+
+	var best *client.Image
+	var bestVersion *semver.Version
+	for _, image := range images {
+		if !imageMatches(image, selector) {
+			continue
+		}
+
+		version, _ := semver.NewVersion(image.OSVersion)
+		if constraint != nil && (version == nil || !constraint.Check(version)) {
+			continue
+		}
+
+		if best == nil || compareImages(image, version, best, bestVersion) > 0 {
+			best, bestVersion = image, version
+		}
+	}
+
+	if best == nil {
+		return "", fmt.Errorf("%w for image selector %s", errNoMatchingImage, describeImageSelector(selector))
+	}
+
+	klog.V(2).Infof("Resolved image selector %s to image %q (version %q)", describeImageSelector(selector), best.ID, best.OSVersion)
+	return best.ID, nil
+}
+
+// imageMatches returns true if the image is available and matches the name and filters of the selector
+func imageMatches(image *client.Image, selector *api.ImageSelectorSpec) bool {
+	if image.Status != imageStatusAvailable || image.Name != selector.Name {
+		return false
+	}
+	if selector.Distro != "" && !strings.EqualFold(image.OSDistro, selector.Distro) {
+		return false
+	}
+	if selector.Architecture != "" && !strings.EqualFold(image.Architecture, selector.Architecture) {
+		return false
+	}
+	switch selector.Scope {
+	case api.ImageScopePublic:
+		return image.Scope == api.ImageScopePublic
+	case api.ImageScopeProject:
+		return image.Scope != api.ImageScopePublic
+	}
+	return true
+}
+
+// compareImages orders images by OS version, then by creation time
+// Images with a parseable OS version rank above images without one
+func compareImages(a *client.Image, aVersion *semver.Version, b *client.Image, bVersion *semver.Version) int {
+	switch {
+	case aVersion != nil && bVersion == nil:
+		return 1
+	case aVersion == nil && bVersion != nil:
+		return -1
+	case aVersion != nil && bVersion != nil:
+		if c := aVersion.Compare(bVersion); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(createdAtUnix(a), createdAtUnix(b))
+}
+
+// createdAtUnix returns the creation time of the image in nanoseconds, or 0 if unknown
+func createdAtUnix(image *client.Image) int64 {
+	if image.CreatedAt == nil {
+		return 0
+	}
+	return image.CreatedAt.UnixNano()
+}
+
+// describeImageSelector returns a human readable description of the image selector for errors and logs
+func describeImageSelector(selector *api.ImageSelectorSpec) string {
+	parts := []string{fmt.Sprintf("name=%q", selector.Name)}
+	for _, filter := range [][2]string{
+		{"version", selector.Version},
+		{"distro", selector.Distro},
+		{"architecture", selector.Architecture},
+		{"scope", selector.Scope},
+	} {
+		if filter[1] != "" {
+			parts = append(parts, fmt.Sprintf("%s=%q", filter[0], filter[1]))
+		}
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
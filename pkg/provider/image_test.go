@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/status"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Image selector", func() {
+	const projectID = "11111111-2222-3333-4444-555555555555"
+
+	var (
+		ctx        context.Context
+		mockClient *mock.StackitClient
+		selector   *api.ImageSelectorSpec
+		images     []*client.Image
+		listCalls  int
+	)
+
+	image := func(id, version, createdAt string) *client.Image {
+		created, _ := time.Parse(time.RFC3339, createdAt)
+		return &client.Image{
+			ID:           id,
+			Name:         "ubuntu",
+			Status:       "AVAILABLE",
+			Scope:        "public",
+			OSDistro:     "ubuntu",
+			OSVersion:    version,
+			Architecture: "x86",
+			CreatedAt:    &created,
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		selector = &api.ImageSelectorSpec{Name: "ubuntu"}
+		images = []*client.Image{
+			image("image-2004", "20.04", "2024-01-01T00:00:00Z"),
+			image("image-2204-old", "22.04", "2024-01-01T00:00:00Z"),
+			image("image-2204-new", "22.04", "2024-06-01T00:00:00Z"),
+			image("image-2404", "24.04", "2024-05-01T00:00:00Z"),
+		}
+		listCalls = 0
+		mockClient = &mock.StackitClient{
+			ListImagesFunc: func(_ context.Context, _, _ string) ([]*client.Image, error) {
+				listCalls++
+				return images, nil
+			},
+		}
+	})
+
+	Describe("resolveImage", func() {
+		It("should pick the highest OS version", func() {
+			imageID, err := resolveImage(ctx, mockClient, projectID, "eu01", selector)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imageID).To(Equal("image-2404"))
+		})
+
+		It("should pick the newest image within the version constraint", func() {
+			selector.Version = ">= 22.04, < 24"
+
+			imageID, err := resolveImage(ctx, mockClient, projectID, "eu01", selector)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imageID).To(Equal("image-2204-new"))
+		})
+
+		It("should skip images that are not available or do not match the filters", func() {
+			images[3].Status = "CREATING"
+			images[2].Architecture = "arm64"
+			images[1].Scope = "local"
+			selector.Architecture = "x86"
+			selector.Scope = api.ImageScopePublic
+
+			imageID, err := resolveImage(ctx, mockClient, projectID, "eu01", selector)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imageID).To(Equal("image-2004"))
+		})
+
+		It("should fail when no image matches", func() {
+			selector.Distro = "debian"
+
+			_, err := resolveImage(ctx, mockClient, projectID, "eu01", selector)
+
+			Expect(err).To(MatchError(errNoMatchingImage))
+			Expect(err.Error()).To(ContainSubstring(`distro="debian"`))
+		})
+	})
+
+	Describe("imageCache", func() {
+		var (
+			cache *imageCache
+			now   time.Time
+		)
+
+		BeforeEach(func() {
+			now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+			cache = newImageCache(10 * time.Minute)
+			cache.now = func() time.Time { return now }
+		})
+
+		It("should reuse the resolved image until the entry expires", func() {
+			imageID, err := cache.resolve(ctx, mockClient, projectID, "eu01", "class-1", selector)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imageID).To(Equal("image-2404"))
+
+			images = images[:3]
+			imageID, err = cache.resolve(ctx, mockClient, projectID, "eu01", "class-1", selector)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imageID).To(Equal("image-2404"))
+			Expect(listCalls).To(Equal(1))
+
+			now = now.Add(11 * time.Minute)
+			imageID, err = cache.resolve(ctx, mockClient, projectID, "eu01", "class-1", selector)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imageID).To(Equal("image-2204-new"))
+			Expect(listCalls).To(Equal(2))
+		})
+
+		It("should resolve each MachineClass and selector separately", func() {
+			_, err := cache.resolve(ctx, mockClient, projectID, "eu01", "class-1", selector)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = cache.resolve(ctx, mockClient, projectID, "eu01", "class-2", selector)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = cache.resolve(ctx, mockClient, projectID, "eu01", "class-1", &api.ImageSelectorSpec{Name: "ubuntu", Version: "< 24"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(listCalls).To(Equal(3))
+		})
+	})
+
+	Describe("CreateMachine", func() {
+		var req *driver.CreateMachineRequest
+
+		BeforeEach(func() {
+			providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{
+				MachineType: "c2i.2",
+				Image:       selector,
+				Region:      "eu01",
+				Networking:  &api.NetworkingSpec{NetworkID: "770e8400-e29b-41d4-a716-446655440000"},
+			})
+			req = &driver.CreateMachineRequest{
+				Machine: &v1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}},
+				MachineClass: &v1alpha1.MachineClass{
+					ObjectMeta:   metav1.ObjectMeta{Name: "test-machine-class"},
+					Provider:     "stackit",
+					ProviderSpec: runtime.RawExtension{Raw: providerSpecRaw},
+				},
+				Secret: &corev1.Secret{Data: map[string][]byte{
+					"project-id":          []byte(projectID),
+					"serviceaccount.json": []byte(`{"credentials":{"iss":"test"}}`),
+				}},
+			}
+		})
+
+		It("should create the server from the resolved image and record it in the labels", func() {
+			var capturedReq *client.CreateServerRequest
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
+				capturedReq = req
+				return &client.Server{ID: "server-1", Name: req.Name}, nil
+			}
+
+			_, err := (&Provider{client: mockClient}).CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(capturedReq.ImageID).To(Equal("image-2404"))
+			Expect(capturedReq.Labels).To(HaveKeyWithValue(StackitImageIDLabel, "image-2404"))
+		})
+
+		It("should not resolve the image for an existing server", func() {
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				return []*client.Server{{ID: "server-1", Name: "test-machine", Status: "ACTIVE"}}, nil
+			}
+
+			_, err := (&Provider{client: mockClient}).CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listCalls).To(BeZero())
+		})
+
+		It("should return InvalidArgument when no image matches", func() {
+			images = nil
+
+			_, err := (&Provider{client: mockClient}).CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.InvalidArgument))
+		})
+	})
+})
@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/status"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// InitializeMachine finishes the setup of a server created by CreateMachine
+//
+// MCM calls this method right after CreateMachine, and again whenever GetMachineStatus
+// reports codes.Uninitialized. All steps are idempotent:
+//  1. Wait until the server reaches ACTIVE state
+//  2. Add the ProviderSpec allowedAddresses to the server NICs
+//  3. Label the boot volume and the NICs created along with the server for the machine
+//  4. Attach ProviderSpec volumes that are not attached yet
+//  5. Create the ProviderSpec data volumes of the machine and attach them
+//  6. Associate the public IP of the machine with the primary NIC
+//  7. Collect the IP addresses of the server NICs
+//
+// Returns:
+//   - ProviderID: The machine's ProviderID
+//   - NodeName: Name that the VM will register with in Kubernetes (matches Machine name)
+//   - Addresses: Internal IP addresses of the server's NICs (NodeInternalIP) and its public IP (NodeExternalIP)
+//
+// Error codes (MCM retries all of them after a short period):
+//   - InvalidArgument: Invalid ProviderSpec fields, missing required values or invalid ProviderID
+//   - Internal: Malformed ProviderSpec JSON or failed to initialize STACKIT client
+//   - NotFound: Server does not exist
+//   - DeadlineExceeded: Server did not reach ACTIVE state within the polling timeout
+//   - Unavailable: Transient API failure (get server, get NICs, patch NIC, label boot volume or NIC, create or attach volume, public IP), rate limiting or STACKIT server errors
+//   - FailedPrecondition: A volume of the ProviderSpec or a data volume is attached to another server
+//   - ResourceExhausted: Server went into ERROR state because no capacity was available, or the public IP pool is exhausted
+//   - Unauthenticated / PermissionDenied: Service account credentials rejected or missing permissions
+func (p *Provider) InitializeMachine(ctx context.Context, req *driver.InitializeMachineRequest) (*driver.InitializeMachineResponse, error) {
+	// Log messages to track request
+	klog.V(2).Infof("Machine initialization request has been received for %q", req.Machine.Name)
+	defer klog.V(2).Infof("Machine initialization request has been processed for %q", req.Machine.Name)
+
+	// Check if incoming provider in the MachineClass is a provider we support
+	if req.MachineClass.Provider != StackitProviderName {
+		err := fmt.Errorf("requested for Provider '%s', we only support '%s'", req.MachineClass.Provider, StackitProviderName)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Decode ProviderSpec from MachineClass
+	providerSpec, err := decodeProviderSpec(req.MachineClass)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Validate ProviderSpec and Secret
+	validationErrs := validation.ValidateProviderSpecNSecret(providerSpec, req.Secret)
+	if len(validationErrs) > 0 {
+		return nil, status.Error(codes.InvalidArgument, validationErrs.ToAggregate().Error())
+	}
+
+	// Extract credentials from Secret
+	projectIDFromSecret, serviceAccountKey := extractSecretCredentials(req.Secret.Data)
+
+	// Resolve client for the Secret credentials (created on first use or after rotation)
+	c, err := p.getClient(projectIDFromSecret, serviceAccountKey)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to initialize STACKIT client: %v", err))
+	}
+
+	projectID, serverID, err := initializeTarget(ctx, c, req, projectIDFromSecret, providerSpec.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := p.WaitUntilServerRunning(ctx, c, projectID, providerSpec.Region, serverID)
+	if err != nil {
+		klog.Errorf("Failed waiting for server %q to reach ACTIVE state: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.DeadlineExceeded), fmt.Sprintf("failed waiting for server to be ACTIVE: %v", err))
+	}
+
+	nics, err := patchNetworkInterfaces(ctx, c, projectID, serverID, providerSpec)
+	if err != nil {
+		klog.Errorf("Failed to patch NICs for server %q: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to patch NICs for server: %v", err))
+	}
+
+	if err := labelServerResources(ctx, c, projectID, req.Machine.Name, req.MachineClass.Name, string(req.Machine.UID), server, nics, providerSpec); err != nil {
+		klog.Errorf("Failed to label resources of server %q: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to label resources of server: %v", err))
+	}
+
+	if err := attachVolumes(ctx, c, projectID, server, providerSpec); err != nil {
+		klog.Errorf("Failed to attach volumes to server %q: %v", req.Machine.Name, err)
+		return nil, status.Error(volumeErrorCode(err), fmt.Sprintf("failed to attach volumes to server: %v", err))
+	}
+
+	if err := p.ensureDataVolumes(ctx, c, projectID, req.Machine.Name, req.MachineClass.Name, string(req.Machine.UID), server, providerSpec); err != nil {
+		klog.Errorf("Failed to set up data volumes of server %q: %v", req.Machine.Name, err)
+		return nil, status.Error(volumeErrorCode(err), fmt.Sprintf("failed to set up data volumes: %v", err))
+	}
+
+	publicIP, err := ensurePublicIP(ctx, c, projectID, req.Machine.Name, req.MachineClass.Name, string(req.Machine.UID), providerSpec)
+	if err != nil {
+		klog.Errorf("Failed to set up public IP of server %q: %v", req.Machine.Name, err)
+		return nil, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to set up public IP: %v", err))
+	}
+	if publicIP != nil {
+		nics, err = associatePublicIP(ctx, c, projectID, publicIP, nics, providerSpec)
+		if err != nil {
+			klog.Errorf("Failed to associate public IP with server %q: %v", req.Machine.Name, err)
+			return nil, status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to associate public IP: %v", err))
+		}
+	}
+
+	klog.V(2).Infof("Successfully initialized server %q for machine %q", serverID, req.Machine.Name)
+
+	return &driver.InitializeMachineResponse{
+		ProviderID: fmt.Sprintf("%s://%s/%s", StackitProviderName, projectID, serverID),
+		NodeName:   req.Machine.Name,
+		Addresses:  nicAddresses(nics),
+	}, nil
+}
+
+// initializeTarget returns the project and server ID of the server to initialize
+// The ProviderID is set by MCM after CreateMachine, the server is looked up by name if it is missing
+func initializeTarget(ctx context.Context, c client.StackitClient, req *driver.InitializeMachineRequest, projectIDFromSecret, region string) (string, string, error) {
+	if req.Machine.Spec.ProviderID != "" {
+		projectID, serverID, err := parseProviderID(req.Machine.Spec.ProviderID)
+		if err != nil {
+			return "", "", status.Error(codes.InvalidArgument, fmt.Sprintf("invalid ProviderID format: %v", err))
+		}
+		if projectID == "" {
+			projectID = projectIDFromSecret
+		}
+		return projectID, serverID, nil
+	}
+
+	server, err := getServerByName(ctx, c, projectIDFromSecret, region, req.Machine.Name, string(req.Machine.UID), "")
+	if err != nil {
+		klog.Errorf("Failed to fetch server for machine %q: %v", req.Machine.Name, err)
+		return "", "", status.Error(errorCode(err, codes.Unavailable), fmt.Sprintf("failed to fetch server: %v", err))
+	}
+	if server == nil {
+		return "", "", status.Error(codes.NotFound, fmt.Sprintf("no server found for machine %q", req.Machine.Name))
+	}
+	return projectIDFromSecret, server.ID, nil
+}
+
+// needsInitialization returns true if InitializeMachine still has work to do for the server
+// Servers in other states than CREATING or ACTIVE are left to GetMachineStatus,
+// NIC allowed addresses can only be checked if the server reports its NICs,
+// dataVolumeIDs are the IDs of the data volumes created for the machine
+func needsInitialization(server *client.Server, providerSpec *api.ProviderSpec, dataVolumeIDs []string) bool {
+	if server.Status == serverStatusCreating {
+		return true
+	}
+	if server.Status != serverStatusActive {
+		return false
+	}
+
+	for _, volumeID := range providerSpec.Volumes {
+		if !slices.Contains(server.VolumeIDs, volumeID) {
+			return true
+		}
+	}
+
+	if missingDataVolumes(server, providerSpec, dataVolumeIDs) {
+		return true
+	}
+
+	if missingPublicIP(server, providerSpec) {
+		return true
+	}
+
+	for _, nic := range server.NICs {
+		if !nicInConfiguredNetwork(nic, providerSpec) {
+			continue
+		}
+		for _, allowedAddress := range providerSpec.AllowedAddresses {
+			if !slices.Contains(nic.AllowedAddresses, allowedAddress) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// nicInConfiguredNetwork returns true if the NIC belongs to the network configured in the ProviderSpec
+func nicInConfiguredNetwork(nic *client.NIC, providerSpec *api.ProviderSpec) bool {
+	// if networking is not set, server is inside the default network
+	// just patch the interface since the server should only have one
+	if providerSpec.Networking == nil {
+		return true
+	}
+	// only process interfaces that are either in the configured network (NetworkID) or are defined in NICIDs
+	return providerSpec.Networking.NetworkID == nic.NetworkID || slices.Contains(providerSpec.Networking.NICIDs, nic.ID)
+}
+
+func patchNetworkInterfaces(ctx context.Context, c client.StackitClient, projectID, serverID string, providerSpec *api.ProviderSpec) ([]*client.NIC, error) {
+	nics, err := c.GetNICsForServer(ctx, projectID, providerSpec.Region, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NICs for server %q: %w", serverID, err)
+	}
+
+	if len(nics) == 0 {
+		return nil, fmt.Errorf("no NICs found for server %q", serverID)
+	}
+
+	if len(providerSpec.AllowedAddresses) == 0 {
+		return nics, nil
+	}
+
+	result := make([]*client.NIC, 0, len(nics))
+	for _, nic := range nics {
+		if !nicInConfiguredNetwork(nic, providerSpec) {
+			result = append(result, nic)
+			continue
+		}
+
+		updateNic := false
+		// check if every cidr in providerspec.allowedAddresses is inside the nic allowedAddresses
+		for _, allowedAddress := range providerSpec.AllowedAddresses {
+			if !slices.Contains(nic.AllowedAddresses, allowedAddress) {
+				nic.AllowedAddresses = append(nic.AllowedAddresses, allowedAddress)
+				updateNic = true
+			}
+		}
+
+		if !updateNic {
+			result = append(result, nic)
+			continue
+		}
+
+		updatedNic, err := c.UpdateNIC(ctx, projectID, providerSpec.Region, nic.NetworkID, nic.ID, nic.AllowedAddresses)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update allowed addresses for NIC %s: %w", nic.ID, err)
+		}
+
+		klog.V(2).Infof("Updated allowed addresses for NIC %s to %v", nic.ID, nic.AllowedAddresses)
+		result = append(result, updatedNic)
+	}
+
+	return result, nil
+}
+
+// volumeErrorCode maps errors of attaching volumes, volumes attached to another server need manual action
+func volumeErrorCode(err error) codes.Code {
+	if errors.Is(err, errVolumeInUse) {
+		return codes.FailedPrecondition
+	}
+	return errorCode(err, codes.Unavailable)
+}
+
+// attachVolumes attaches the ProviderSpec volumes that are not yet attached to the server
+// Volumes are normally attached during server creation, this repairs attachments that did not happen
+func attachVolumes(ctx context.Context, c client.StackitClient, projectID string, server *client.Server, providerSpec *api.ProviderSpec) error {
+	for _, volumeID := range providerSpec.Volumes {
+		if slices.Contains(server.VolumeIDs, volumeID) {
+			continue
+		}
+
+		if err := attachVolume(ctx, c, projectID, providerSpec.Region, server.ID, volumeID); err != nil {
+			return fmt.Errorf("failed to attach volume %q: %w", volumeID, err)
+		}
+	}
+
+	return nil
+}
+
+// WaitUntilServerRunning polls the server until it reaches ACTIVE state and returns it
+func (p *Provider) WaitUntilServerRunning(ctx context.Context, c client.StackitClient, projectID, region, serverID string) (*client.Server, error) {
+	var server *client.Server
+	err := wait.PollUntilContextTimeout(ctx, p.pollingInterval, p.pollingTimeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		server, err = c.GetServer(ctx, projectID, region, serverID)
+		if err != nil {
+			return false, err
+		}
+
+		switch server.Status {
+		case serverStatusActive:
+			klog.V(2).Infof("Server %q reached ACTIVE state", serverID)
+			return true, nil
+		case serverStatusError:
+			// classify the error message so e.g. capacity problems surface as ResourceExhausted
+			if class := client.ClassifyServerErrorMessage(server.ErrorMessage); class != nil {
+				return false, fmt.Errorf("%w: server in ERROR state: %q", class, server.ErrorMessage)
+			}
+			return false, fmt.Errorf("server in ERROR state: %q", server.ErrorMessage)
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return server, nil
+}
@@ -0,0 +1,665 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/status"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("InitializeMachine", func() {
+	var (
+		ctx          context.Context
+		provider     *Provider
+		mockClient   *mock.StackitClient
+		req          *driver.InitializeMachineRequest
+		secret       *corev1.Secret
+		machineClass *v1alpha1.MachineClass
+		machine      *v1alpha1.Machine
+		providerSpec *api.ProviderSpec
+	)
+
+	const (
+		serverID = "550e8400-e29b-41d4-a716-446655440000"
+		volume1  = "660e8400-e29b-41d4-a716-446655440001"
+		volume2  = "660e8400-e29b-41d4-a716-446655440002"
+	)
+
+	setProviderSpec := func(spec *api.ProviderSpec) {
+		providerSpecRaw, _ := mock.EncodeProviderSpec(spec)
+		machineClass.ProviderSpec = runtime.RawExtension{Raw: providerSpecRaw}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockClient = &mock.StackitClient{}
+		provider = &Provider{
+			client:          mockClient,
+			pollingInterval: 10 * time.Millisecond,
+			pollingTimeout:  5 * time.Second,
+		}
+
+		secret = &corev1.Secret{
+			Data: map[string][]byte{
+				"project-id":          []byte("11111111-2222-3333-4444-555555555555"),
+				"serviceaccount.json": []byte(`{"credentials":{"iss":"test"}}`),
+			},
+		}
+
+		providerSpec = &api.ProviderSpec{
+			MachineType: "c2i.2",
+			ImageID:     "12345678-1234-1234-1234-123456789abc",
+			Region:      "eu01",
+			Networking: &api.NetworkingSpec{
+				NetworkID: "770e8400-e29b-41d4-a716-446655440000",
+			},
+		}
+
+		machineClass = &v1alpha1.MachineClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-machine-class",
+			},
+			Provider: "stackit",
+		}
+		setProviderSpec(providerSpec)
+
+		machine = &v1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-machine",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.MachineSpec{
+				ProviderID: "stackit://11111111-2222-3333-4444-555555555555/" + serverID,
+			},
+		}
+
+		req = &driver.InitializeMachineRequest{
+			Machine:      machine,
+			MachineClass: machineClass,
+			Secret:       secret,
+		}
+	})
+
+	Context("with valid inputs", func() {
+		It("should return ProviderID, NodeName and internal IPs", func() {
+			mockClient.GetNICsFunc = func(_ context.Context, _, _, _ string) ([]*client.NIC, error) {
+				return []*client.NIC{
+					{ID: "nic-1", NetworkID: "net-1", IPv4: "10.0.0.5", IPv6: "fd00::1"},
+					{ID: "nic-2", NetworkID: "net-2", IPv4: "10.0.1.5"},
+				}, nil
+			}
+
+			resp, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.ProviderID).To(Equal(machine.Spec.ProviderID))
+			Expect(resp.NodeName).To(Equal("test-machine"))
+			Expect(resp.Addresses).To(ConsistOf(
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.5"},
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "fd00::1"},
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.1.5"},
+			))
+		})
+
+		It("should poll GetServer until server is ACTIVE", func() {
+			getServerCallCount := 0
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				getServerCallCount++
+				// First call returns CREATING, second call returns ACTIVE
+				if getServerCallCount == 1 {
+					return &client.Server{ID: serverID, Name: "test-machine", Status: "CREATING"}, nil
+				}
+				return &client.Server{ID: serverID, Name: "test-machine", Status: "ACTIVE"}, nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getServerCallCount).To(BeNumerically(">=", 2))
+		})
+
+		It("should look up the server by name when the ProviderID is not set yet", func() {
+			machine.Spec.ProviderID = ""
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.Server, error) {
+				Expect(labelSelector).To(HaveKeyWithValue(StackitMachineLabel, "test-machine"))
+				return []*client.Server{{ID: serverID, Name: "test-machine", Status: "ACTIVE"}}, nil
+			}
+
+			resp, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.ProviderID).To(Equal("stackit://11111111-2222-3333-4444-555555555555/" + serverID))
+		})
+	})
+
+	Context("with resources created along with the server", func() {
+		const (
+			bootVolume = "660e8400-e29b-41d4-a716-446655440003"
+			nic1       = "880e8400-e29b-41d4-a716-446655440001"
+			nic2       = "880e8400-e29b-41d4-a716-446655440002"
+		)
+
+		var (
+			volumeLabels map[string]map[string]string
+			nicLabels    map[string]map[string]string
+		)
+
+		BeforeEach(func() {
+			// the IaaS API creates the boot volume and the NICs of a server without labels
+			volumeLabels = map[string]map[string]string{bootVolume: {"team": "edge"}}
+			nicLabels = map[string]map[string]string{nic1: nil, nic2: nil}
+
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Name: "test-machine", Status: "ACTIVE", BootVolumeID: bootVolume, VolumeIDs: []string{bootVolume}}, nil
+			}
+			mockClient.GetNICsFunc = func(_ context.Context, _, _, _ string) ([]*client.NIC, error) {
+				return []*client.NIC{
+					{ID: nic1, NetworkID: "770e8400-e29b-41d4-a716-446655440000", IPv4: "10.0.0.5"},
+					{ID: nic2, NetworkID: "770e8400-e29b-41d4-a716-446655440000", IPv4: "10.0.1.5"},
+				}, nil
+			}
+			mockClient.GetVolumeFunc = func(_ context.Context, _, _, volumeID string) (*client.Volume, error) {
+				return &client.Volume{ID: volumeID, Labels: volumeLabels[volumeID]}, nil
+			}
+			mockClient.UpdateVolumeFunc = func(_ context.Context, _, _, volumeID string, req *client.UpdateVolumeRequest) (*client.Volume, error) {
+				volumeLabels[volumeID] = req.Labels
+				return &client.Volume{ID: volumeID, Labels: req.Labels}, nil
+			}
+			mockClient.GetNICFunc = func(_ context.Context, _, _, nicID string) (*client.NIC, error) {
+				return &client.NIC{ID: nicID, Labels: nicLabels[nicID]}, nil
+			}
+			mockClient.UpdateNICLabelsFunc = func(_ context.Context, _, _, _, nicID string, labels map[string]string) (*client.NIC, error) {
+				nicLabels[nicID] = labels
+				return &client.NIC{ID: nicID, Labels: labels}, nil
+			}
+		})
+
+		It("should label the boot volume and the NICs for the machine", func() {
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(volumeLabels[bootVolume]).To(Equal(map[string]string{
+				"team":                   "edge",
+				StackitMachineLabel:      "test-machine",
+				StackitMachineClassLabel: "test-machine-class",
+			}))
+			for _, nicID := range []string{nic1, nic2} {
+				Expect(nicLabels[nicID]).To(Equal(map[string]string{
+					StackitMachineLabel:      "test-machine",
+					StackitMachineClassLabel: "test-machine-class",
+				}))
+			}
+		})
+
+		It("should label the boot volume and the NICs with the UID of the machine", func() {
+			machine.UID = "machine-uid"
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(volumeLabels[bootVolume]).To(HaveKeyWithValue(StackitMachineUIDLabel, "machine-uid"))
+			for _, nicID := range []string{nic1, nic2} {
+				Expect(nicLabels[nicID]).To(HaveKeyWithValue(StackitMachineUIDLabel, "machine-uid"))
+			}
+		})
+
+		It("should not update resources that are already labelled", func() {
+			_, err := provider.InitializeMachine(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			mockClient.UpdateVolumeFunc = func(_ context.Context, _, _, _ string, _ *client.UpdateVolumeRequest) (*client.Volume, error) {
+				Fail("UpdateVolume must not be called")
+				return nil, nil
+			}
+			mockClient.UpdateNICLabelsFunc = func(_ context.Context, _, _, _, _ string, _ map[string]string) (*client.NIC, error) {
+				Fail("UpdateNICLabels must not be called")
+				return nil, nil
+			}
+
+			_, err = provider.InitializeMachine(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not mark a boot volume of deleteOnTermination false as kept", func() {
+			providerSpec.BootVolume = &api.BootVolumeSpec{DeleteOnTermination: new(false), Size: 50}
+			setProviderSpec(providerSpec)
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(volumeLabels[bootVolume]).To(HaveKeyWithValue(StackitMachineLabel, "test-machine"))
+			Expect(volumeLabels[bootVolume]).NotTo(HaveKey(StackitDeleteOnTerminationLabel))
+		})
+
+		It("should not label NICs of networking.nicIds and boot volumes of source type volume", func() {
+			providerSpec.Networking = &api.NetworkingSpec{NICIDs: []string{nic1}}
+			providerSpec.BootVolume = &api.BootVolumeSpec{Source: &api.BootVolumeSourceSpec{Type: "volume", ID: bootVolume}}
+			providerSpec.ImageID = ""
+			setProviderSpec(providerSpec)
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(volumeLabels[bootVolume]).To(Equal(map[string]string{"team": "edge"}))
+			Expect(nicLabels[nic1]).To(BeNil())
+			Expect(nicLabels[nic2]).To(HaveKeyWithValue(StackitMachineLabel, "test-machine"))
+		})
+
+		It("should return Unavailable when labelling fails", func() {
+			mockClient.UpdateNICLabelsFunc = func(_ context.Context, _, _, _, _ string, _ map[string]string) (*client.NIC, error) {
+				return nil, &client.APIError{Class: client.ErrServerError, StatusCode: 503}
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Unavailable))
+		})
+	})
+
+	Context("with volumes in ProviderSpec", func() {
+		BeforeEach(func() {
+			providerSpec.Volumes = []string{volume1, volume2}
+			setProviderSpec(providerSpec)
+		})
+
+		It("should attach volumes that are not attached yet", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Status: "ACTIVE", VolumeIDs: []string{"boot-volume", volume1}}, nil
+			}
+			var attached []string
+			mockClient.AttachVolumeFunc = func(_ context.Context, _, _, id, volumeID string) error {
+				Expect(id).To(Equal(serverID))
+				attached = append(attached, volumeID)
+				return nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attached).To(Equal([]string{volume2}))
+		})
+
+		It("should ignore volumes that got attached concurrently", func() {
+			mockClient.AttachVolumeFunc = func(_ context.Context, _, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrConflict, StatusCode: 409}
+			}
+			mockClient.GetVolumeFunc = func(_ context.Context, _, _, volumeID string) (*client.Volume, error) {
+				return &client.Volume{ID: volumeID, Status: "ATTACHED", ServerID: serverID}, nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return FailedPrecondition when a volume is attached to another server", func() {
+			mockClient.AttachVolumeFunc = func(_ context.Context, _, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrConflict, StatusCode: 409}
+			}
+			mockClient.GetVolumeFunc = func(_ context.Context, _, _, volumeID string) (*client.Volume, error) {
+				return &client.Volume{ID: volumeID, Status: "ATTACHED", ServerID: "other-server"}, nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.FailedPrecondition))
+			Expect(err.Error()).To(ContainSubstring("other-server"))
+		})
+
+		It("should return Aborted when attaching conflicts for another reason", func() {
+			mockClient.AttachVolumeFunc = func(_ context.Context, _, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrConflict, StatusCode: 409}
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Aborted))
+		})
+
+		It("should return Unavailable when attaching fails", func() {
+			mockClient.AttachVolumeFunc = func(_ context.Context, _, _, _, _ string) error {
+				return &client.APIError{Class: client.ErrServerError, StatusCode: 503}
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Unavailable))
+			Expect(err.Error()).To(ContainSubstring(volume1))
+		})
+	})
+
+	Context("with data volumes in ProviderSpec", func() {
+		BeforeEach(func() {
+			providerSpec.Labels = map[string]string{"team": "db"}
+			providerSpec.DataVolumes = []api.DataVolumeSpec{
+				{Name: "data", Size: 100, PerformanceClass: "storage_premium_perf4"},
+				{Name: "wal", Size: 20, SnapshotID: volume2, DeleteOnTermination: new(false)},
+			}
+			setProviderSpec(providerSpec)
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Status: "ACTIVE", AvailabilityZone: "eu01-2"}, nil
+			}
+		})
+
+		It("should create labelled data volumes in the server's zone and attach them", func() {
+			var created []*client.CreateVolumeRequest
+			mockClient.CreateVolumeFunc = func(_ context.Context, _, _ string, req *client.CreateVolumeRequest) (*client.Volume, error) {
+				created = append(created, req)
+				return &client.Volume{ID: fmt.Sprintf("volume-%d", len(created)), Status: "CREATING"}, nil
+			}
+			var attached []string
+			mockClient.AttachVolumeFunc = func(_ context.Context, _, _, id, volumeID string) error {
+				Expect(id).To(Equal(serverID))
+				attached = append(attached, volumeID)
+				return nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(HaveLen(2))
+			Expect(created[0].Name).To(Equal("test-machine-data"))
+			Expect(created[0].AvailabilityZone).To(Equal("eu01-2"))
+			Expect(created[0].Size).To(Equal(100))
+			Expect(created[0].PerformanceClass).To(Equal("storage_premium_perf4"))
+			Expect(created[0].Source).To(BeNil())
+			Expect(created[0].Labels).To(Equal(map[string]string{
+				"team":                                "db",
+				"kubernetes.io/machine":               "test-machine",
+				"kubernetes.io/machineclass":          "test-machine-class",
+				"kubernetes.io/data-volume":           "data",
+				"kubernetes.io/delete-on-termination": "true",
+			}))
+			Expect(created[1].Name).To(Equal("test-machine-wal"))
+			Expect(created[1].Source).To(Equal(&client.VolumeSourceRequest{Type: "snapshot", ID: volume2}))
+			Expect(created[1].Labels).To(HaveKeyWithValue("kubernetes.io/delete-on-termination", "false"))
+			Expect(attached).To(Equal([]string{"volume-1", "volume-2"}))
+		})
+
+		It("should reuse existing data volumes and skip attached ones", func() {
+			mockClient.ListVolumesFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.Volume, error) {
+				Expect(labelSelector).To(Equal(map[string]string{"kubernetes.io/machine": "test-machine"}))
+				return []*client.Volume{
+					{ID: volume1, Status: "ATTACHED", ServerID: serverID, Labels: map[string]string{"kubernetes.io/data-volume": "data"}},
+					{ID: volume2, Status: "AVAILABLE", Labels: map[string]string{"kubernetes.io/data-volume": "wal"}},
+				}, nil
+			}
+			mockClient.CreateVolumeFunc = func(_ context.Context, _, _ string, _ *client.CreateVolumeRequest) (*client.Volume, error) {
+				Fail("no data volume should be created")
+				return nil, nil
+			}
+			var attached []string
+			mockClient.AttachVolumeFunc = func(_ context.Context, _, _, _, volumeID string) error {
+				attached = append(attached, volumeID)
+				return nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attached).To(Equal([]string{volume2}))
+		})
+
+		It("should create data volumes instead of reusing those of an earlier machine with the same name", func() {
+			machine.UID = "new-uid"
+			mockClient.ListVolumesFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Volume, error) {
+				return []*client.Volume{
+					{ID: volume1, Status: "AVAILABLE", Labels: map[string]string{"kubernetes.io/data-volume": "data", StackitMachineUIDLabel: "old-uid"}},
+					{ID: volume2, Status: "AVAILABLE", Labels: map[string]string{"kubernetes.io/data-volume": "wal", StackitMachineUIDLabel: "new-uid"}},
+				}, nil
+			}
+			var created []*client.CreateVolumeRequest
+			mockClient.CreateVolumeFunc = func(_ context.Context, _, _ string, req *client.CreateVolumeRequest) (*client.Volume, error) {
+				created = append(created, req)
+				return &client.Volume{ID: "volume-new", Status: "AVAILABLE"}, nil
+			}
+			var attached []string
+			mockClient.AttachVolumeFunc = func(_ context.Context, _, _, _, volumeID string) error {
+				attached = append(attached, volumeID)
+				return nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(HaveLen(1))
+			Expect(created[0].Name).To(Equal("test-machine-data"))
+			Expect(created[0].Labels).To(HaveKeyWithValue(StackitMachineUIDLabel, "new-uid"))
+			Expect(attached).To(Equal([]string{"volume-new", volume2}))
+		})
+
+		It("should return Unavailable when a data volume goes into ERROR state", func() {
+			mockClient.GetVolumeFunc = func(_ context.Context, _, _, volumeID string) (*client.Volume, error) {
+				return &client.Volume{ID: volumeID, Status: "ERROR"}, nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Unavailable))
+			Expect(err.Error()).To(ContainSubstring("data"))
+		})
+
+		It("should return FailedPrecondition right away when a data volume is attached to another server", func() {
+			provider.pollingTimeout = time.Minute
+			mockClient.GetVolumeFunc = func(_ context.Context, _, _, volumeID string) (*client.Volume, error) {
+				return &client.Volume{ID: volumeID, Status: "ATTACHED", ServerID: "other-server"}, nil
+			}
+			mockClient.AttachVolumeFunc = func(_ context.Context, _, _, _, _ string) error {
+				Fail("a volume attached to another server must not be attached")
+				return nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.FailedPrecondition))
+		})
+
+		It("should return ResourceExhausted when the volume quota is exceeded", func() {
+			mockClient.CreateVolumeFunc = func(_ context.Context, _, _ string, _ *client.CreateVolumeRequest) (*client.Volume, error) {
+				return nil, &client.APIError{Class: client.ErrQuotaExceeded, StatusCode: 400}
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.ResourceExhausted))
+		})
+	})
+
+	Context("when the server does not become ACTIVE", func() {
+		It("should return ResourceExhausted when server enters ERROR state with 'no valid host'", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return &client.Server{
+					ID:           serverID,
+					Name:         "test-machine",
+					Status:       "ERROR",
+					ErrorMessage: "No valid host was found. There are not enough hosts available.",
+				}, nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.ResourceExhausted))
+			Expect(err.Error()).To(ContainSubstring("No valid host"))
+		})
+
+		It("should return DeadlineExceeded when the polling timeout is reached", func() {
+			provider.pollingTimeout = 50 * time.Millisecond
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Name: "test-machine", Status: "CREATING"}, nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.DeadlineExceeded))
+		})
+
+		It("should return NotFound when the server does not exist", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, _ string) (*client.Server, error) {
+				return nil, fmt.Errorf("SDK GetServer failed: %w", &client.APIError{Class: client.ErrNotFound, StatusCode: 404})
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.NotFound))
+		})
+	})
+
+	Context("when server has no NICs", func() {
+		It("should return Unavailable error", func() {
+			mockClient.GetNICsFunc = func(_ context.Context, _, _, _ string) ([]*client.NIC, error) {
+				return []*client.NIC{}, nil
+			}
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Unavailable))
+			Expect(err.Error()).To(ContainSubstring("no NICs found"))
+		})
+	})
+
+	Context("with invalid inputs", func() {
+		It("should fail when Provider is wrong", func() {
+			machineClass.Provider = "aws"
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should return InvalidArgument when ProviderID has invalid format", func() {
+			machine.Spec.ProviderID = "invalid-provider-id"
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should return NotFound when no ProviderID is set and no server exists", func() {
+			machine.Spec.ProviderID = ""
+
+			_, err := provider.InitializeMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.NotFound))
+		})
+	})
+})
+
+var _ = Describe("needsInitialization", func() {
+	var providerSpec *api.ProviderSpec
+
+	BeforeEach(func() {
+		providerSpec = &api.ProviderSpec{
+			Networking:       &api.NetworkingSpec{NetworkID: "net-1"},
+			AllowedAddresses: []string{"10.96.0.0/12"},
+			Volumes:          []string{"volume-1"},
+		}
+	})
+
+	It("should be true while the server is CREATING", func() {
+		Expect(needsInitialization(&client.Server{Status: "CREATING"}, providerSpec, nil)).To(BeTrue())
+	})
+
+	It("should be false for servers in other states", func() {
+		Expect(needsInitialization(&client.Server{Status: "ERROR"}, providerSpec, nil)).To(BeFalse())
+	})
+
+	It("should be true when a volume is not attached", func() {
+		server := &client.Server{Status: "ACTIVE"}
+		Expect(needsInitialization(server, providerSpec, nil)).To(BeTrue())
+	})
+
+	It("should be true when a NIC lacks allowed addresses", func() {
+		server := &client.Server{
+			Status:    "ACTIVE",
+			VolumeIDs: []string{"volume-1"},
+			NICs:      []*client.NIC{{ID: "nic-1", NetworkID: "net-1"}},
+		}
+		Expect(needsInitialization(server, providerSpec, nil)).To(BeTrue())
+	})
+
+	It("should ignore NICs in other networks", func() {
+		server := &client.Server{
+			Status:    "ACTIVE",
+			VolumeIDs: []string{"volume-1"},
+			NICs:      []*client.NIC{{ID: "nic-2", NetworkID: "net-2"}},
+		}
+		Expect(needsInitialization(server, providerSpec, nil)).To(BeFalse())
+	})
+
+	It("should be true when a data volume is not attached", func() {
+		providerSpec.DataVolumes = []api.DataVolumeSpec{{Name: "data", Size: 10}}
+		server := &client.Server{
+			Status:       "ACTIVE",
+			BootVolumeID: "boot-volume",
+			VolumeIDs:    []string{"boot-volume", "volume-1", "csi-volume"},
+			NICs:         []*client.NIC{{ID: "nic-1", NetworkID: "net-1", AllowedAddresses: []string{"10.96.0.0/12"}}},
+		}
+		Expect(needsInitialization(server, providerSpec, []string{"data-volume"})).To(BeTrue())
+
+		server.VolumeIDs = append(server.VolumeIDs, "data-volume")
+		Expect(needsInitialization(server, providerSpec, []string{"data-volume"})).To(BeFalse())
+	})
+
+	It("should be false once the server is configured", func() {
+		server := &client.Server{
+			Status:    "ACTIVE",
+			VolumeIDs: []string{"volume-1"},
+			NICs:      []*client.NIC{{ID: "nic-1", NetworkID: "net-1", AllowedAddresses: []string{"10.96.0.0/12"}}},
+		}
+		Expect(needsInitialization(server, providerSpec, nil)).To(BeFalse())
+	})
+})
@@ -20,6 +20,8 @@ import (
 //   - MachineList: Map of ProviderID to MachineName for all servers matching the MachineClass
 //
 // Error codes:
+//   - Unauthenticated / PermissionDenied: Service account credentials rejected or missing permissions
+//   - Unavailable: Rate limiting or STACKIT server errors
 //   - Internal: Failed to list servers or communicate with STACKIT API
 func (p *Provider) ListMachines(ctx context.Context, req *driver.ListMachinesRequest) (*driver.ListMachinesResponse, error) {
 	// Log messages to track start and end of request
@@ -29,8 +31,9 @@ func (p *Provider) ListMachines(ctx context.Context, req *driver.ListMachinesReq
 	// Extract credentials from Secret
 	projectID, serviceAccountKey := extractSecretCredentials(req.Secret.Data)
 
-	// Initialize client on first use (lazy initialization)
-	if err := p.ensureClient(serviceAccountKey); err != nil {
+	// Resolve client for the Secret credentials (created on first use or after rotation)
+	c, err := p.getClient(projectID, serviceAccountKey)
+	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to initialize STACKIT client: %v", err))
 	}
 
@@ -40,14 +43,17 @@ func (p *Provider) ListMachines(ctx context.Context, req *driver.ListMachinesReq
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	// Collect the volumes, NICs and public IPs of this MachineClass from now on
+	p.orphans.register(c, projectID, providerSpec.Region, req.MachineClass.Name)
+
 	// Call STACKIT API to list all servers
 	labelSelector := map[string]string{
 		StackitMachineClassLabel: req.MachineClass.Name,
 	}
-	servers, err := p.client.ListServers(ctx, projectID, providerSpec.Region, labelSelector)
+	servers, err := c.ListServers(ctx, projectID, providerSpec.Region, labelSelector)
 	if err != nil {
 		klog.Errorf("Failed to list servers for MachineClass %q: %v", req.MachineClass.Name, err)
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list servers: %v", err))
+		return nil, status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to list servers: %v", err))
 	}
 
 	// Filter servers by MachineClass label
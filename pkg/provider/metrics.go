@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The metrics are registered with the default registry, MCM serves them on its metrics endpoint
+const (
+	metricsNamespace = "mcm"
+	metricsSubsystem = "stackit"
+)
+
+// Resource types of the orphan collector metrics
+const (
+	resourceVolume   = "volume"
+	resourceNIC      = "nic"
+	resourcePublicIP = "public_ip"
+)
+
+var (
+	// orphanResourcesFound counts resources found without a server of their machine
+	orphanResourcesFound = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "orphan_resources_found_total",
+		Help:      "Number of IaaS resources found without a server of their machine, partitioned by resource type.",
+	}, []string{"resource"})
+
+	// orphanResourcesDeleted counts orphaned resources deleted by the orphan collector
+	orphanResourcesDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "orphan_resources_deleted_total",
+		Help:      "Number of orphaned IaaS resources deleted by the orphan collector, partitioned by resource type.",
+	}, []string{"resource"})
+
+	// duplicateServersDeleted counts servers deleted because another server of their machine was kept
+	duplicateServersDeleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "duplicate_servers_deleted_total",
+		Help:      "Number of duplicate servers of a machine deleted after concurrent creates.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(orphanResourcesFound, orphanResourcesDeleted, duplicateServersDeleted)
+}
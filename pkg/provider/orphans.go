@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultOrphanCollectionInterval is the interval between two runs of the orphan collector, 0 disables it
+	// The orphan collector deletes resources, so it has to be enabled explicitly
+	DefaultOrphanCollectionInterval time.Duration = 0
+	// DefaultOrphanGracePeriod is how long a resource must be orphaned before the orphan collector deletes it
+	DefaultOrphanGracePeriod = 30 * time.Minute
+	// orphanScopeTTL drops MachineClasses MCM no longer lists machines for, e.g. because they were deleted
+	// MCM lists the machines of every MachineClass at least every 15 minutes for its own orphan detection
+	orphanScopeTTL = time.Hour
+)
+
+// orphanScope is a MachineClass whose resources are collected
+type orphanScope struct {
+	client       client.StackitClient
+	projectID    string
+	region       string
+	machineClass string
+	lastSeen     time.Time
+}
+
+// orphanCollector deletes volumes, NICs and public IPs of machines whose server is gone
+//
+// Design: Collect the resources ListMachines cannot report
+// - MCM's safety controller only sees servers, leftover volumes, NICs and public IPs eat the project quota
+// - The provider has no credentials of its own, a MachineClass is collected once ListMachines was called for it
+// - A resource is orphaned if it carries the MachineClass label, is not attached and no server of its machine exists
+// - Orphans are deleted after they were seen orphaned for the grace period, this covers machines being created
+// - A resource carrying a machine UID label is only backed by a server of that UID, not by a recreated Machine
+// - Resources without machine label and data volumes with deleteOnTermination false are never deleted
+// - Public IPs taken from a pool are returned to the pool instead of being deleted
+// - In dry-run mode orphans are only logged and counted
+// - A nil collector collects nothing
+type orphanCollector struct {
+	interval    time.Duration
+	gracePeriod time.Duration
+	dryRun      bool
+	now         func() time.Time // injectable for tests
+
+	mu     sync.Mutex
+	scopes map[string]*orphanScope // MachineClass key -> scope
+
+	// orphanedSince is when a resource was first seen orphaned, keyed by resource type and ID
+	// Only accessed by collect, which never runs concurrently
+	orphanedSince map[string]time.Time
+}
+
+// newOrphanCollector returns a collector without MachineClasses
+func newOrphanCollector(interval, gracePeriod time.Duration, dryRun bool) *orphanCollector {
+	return &orphanCollector{
+		interval:      interval,
+		gracePeriod:   gracePeriod,
+		dryRun:        dryRun,
+		now:           time.Now,
+		scopes:        make(map[string]*orphanScope),
+		orphanedSince: make(map[string]time.Time),
+	}
+}
+
+// register adds the MachineClass to the collected MachineClasses or refreshes its client
+func (oc *orphanCollector) register(c client.StackitClient, projectID, region, machineClass string) {
+	if oc == nil {
+		return
+	}
+
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+	oc.scopes[projectID+"/"+region+"/"+machineClass] = &orphanScope{
+		client:       c,
+		projectID:    projectID,
+		region:       region,
+		machineClass: machineClass,
+		lastSeen:     oc.now(),
+	}
+}
+
+// run collects orphans every interval until the context is done
+func (oc *orphanCollector) run(ctx context.Context) {
+	klog.Infof("Collecting orphaned resources every %s with a grace period of %s (dry run: %t)", oc.interval, oc.gracePeriod, oc.dryRun)
+	wait.UntilWithContext(ctx, oc.collect, oc.interval)
+}
+
+// collect deletes the orphaned resources of all registered MachineClasses
+func (oc *orphanCollector) collect(ctx context.Context) {
+	orphans := make(map[string]bool)
+	for _, scope := range oc.activeScopes() {
+		if err := oc.collectScope(ctx, scope, orphans); err != nil {
+			klog.Errorf("Failed to collect orphaned resources of machine class %q: %v", scope.machineClass, err)
+		}
+	}
+
+	// resources that are gone or no longer orphaned start a new grace period if they become orphaned again
+	for key := range oc.orphanedSince {
+		if !orphans[key] {
+			delete(oc.orphanedSince, key)
+		}
+	}
+}
+
+// activeScopes returns the registered MachineClasses and drops the expired ones
+func (oc *orphanCollector) activeScopes() []*orphanScope {
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+
+	scopes := make([]*orphanScope, 0, len(oc.scopes))
+	for key, scope := range oc.scopes {
+		if oc.now().Sub(scope.lastSeen) > orphanScopeTTL {
+			klog.V(2).Infof("No longer collecting orphaned resources of machine class %q", scope.machineClass)
+			delete(oc.scopes, key)
+			continue
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+// collectScope deletes the orphaned resources of a MachineClass, the keys of all orphans are added to orphans
+func (oc *orphanCollector) collectScope(ctx context.Context, scope *orphanScope, orphans map[string]bool) error {
+	c := scope.client
+	selector := map[string]string{StackitMachineClassLabel: scope.machineClass}
+
+	servers, err := c.ListServers(ctx, scope.projectID, scope.region, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+	// machine name -> UIDs of its servers, servers without UID label are recorded with an empty UID
+	machines := make(map[string][]string, len(servers))
+	for _, server := range servers {
+		machineName := server.Name
+		if machineLabel, ok := server.Labels[StackitMachineLabel]; ok {
+			machineName = machineLabel
+		}
+		machines[machineName] = append(machines[machineName], server.Labels[StackitMachineUIDLabel])
+	}
+	orphaned := func(labels map[string]string) bool {
+		machineName := labels[StackitMachineLabel]
+		return machineName != "" && !slices.ContainsFunc(machines[machineName], func(uid string) bool {
+			return matchesMachineUID(labels, uid)
+		})
+	}
+
+	volumes, err := c.ListVolumes(ctx, scope.projectID, scope.region, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list volumes: %w", err)
+	}
+	for _, volume := range volumes {
+		if volume.ServerID != "" || keptDataVolume(volume) || !orphaned(volume.Labels) {
+			continue
+		}
+		oc.handleOrphan(ctx, resourceVolume, volume.ID, volume.Labels, orphans, func(ctx context.Context) error {
+			return c.DeleteVolume(ctx, scope.projectID, scope.region, volume.ID)
+		})
+	}
+
+	nics, err := c.ListNICs(ctx, scope.projectID, scope.region, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list NICs: %w", err)
+	}
+	for _, nic := range nics {
+		if nic.ServerID != "" || !orphaned(nic.Labels) {
+			continue
+		}
+		oc.handleOrphan(ctx, resourceNIC, nic.ID, nic.Labels, orphans, func(ctx context.Context) error {
+			return c.DeleteNIC(ctx, scope.projectID, scope.region, nic.NetworkID, nic.ID)
+		})
+	}
+
+	publicIPs, err := c.ListPublicIPs(ctx, scope.projectID, scope.region, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list public IPs: %w", err)
+	}
+	for _, publicIP := range publicIPs {
+		if publicIP.NICID != "" || !orphaned(publicIP.Labels) {
+			continue
+		}
+		oc.handleOrphan(ctx, resourcePublicIP, publicIP.ID, publicIP.Labels, orphans, func(ctx context.Context) error {
+			return releasePublicIP(ctx, c, scope.projectID, scope.region, publicIP)
+		})
+	}
+
+	return nil
+}
+
+// keptDataVolume returns true for data volumes created with deleteOnTermination false
+func keptDataVolume(volume *client.Volume) bool {
+	_, ok := volume.Labels[StackitDataVolumeLabel]
+	return ok && volume.Labels[StackitDeleteOnTerminationLabel] == "false"
+}
+
+// handleOrphan records the orphaned resource and deletes it once the grace period is over
+func (oc *orphanCollector) handleOrphan(ctx context.Context, resource, id string, labels map[string]string, orphans map[string]bool, remove func(context.Context) error) {
+	key := resource + "/" + id
+	orphans[key] = true
+	machineName := labels[StackitMachineLabel]
+
+	since, ok := oc.orphanedSince[key]
+	if !ok {
+		since = oc.now()
+		oc.orphanedSince[key] = since
+		orphanResourcesFound.WithLabelValues(resource).Inc()
+		klog.Infof("Found orphaned %s %q of machine %q", resource, id, machineName)
+	}
+	if oc.now().Sub(since) < oc.gracePeriod {
+		return
+	}
+
+	if oc.dryRun {
+		klog.Infof("Dry run: would remove orphaned %s %q of machine %q", resource, id, machineName)
+		return
+	}
+	if err := remove(ctx); err != nil && !errors.Is(err, client.ErrNotFound) {
+		klog.Errorf("Failed to remove orphaned %s %q of machine %q: %v", resource, id, machineName, err)
+		return
+	}
+
+	delete(oc.orphanedSince, key)
+	orphanResourcesDeleted.WithLabelValues(resource).Inc()
+	klog.Infof("Removed orphaned %s %q of machine %q", resource, id, machineName)
+}
@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Orphan collection", func() {
+	const (
+		projectID    = "11111111-2222-3333-4444-555555555555"
+		region       = "eu01"
+		machineClass = "test-machine-class"
+	)
+
+	var (
+		ctx        context.Context
+		mockClient *mock.StackitClient
+		collector  *orphanCollector
+		now        time.Time
+
+		servers   []*client.Server
+		volumes   []*client.Volume
+		nics      []*client.NIC
+		publicIPs []*client.PublicIP
+
+		deletedVolumes   []string
+		deletedNICs      []string
+		deletedPublicIPs []string
+		updatedPublicIPs map[string]*client.UpdatePublicIPRequest
+	)
+
+	machineLabels := func(machineName string) map[string]string {
+		return map[string]string{StackitMachineLabel: machineName, StackitMachineClassLabel: machineClass}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockClient = &mock.StackitClient{}
+		now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		collector = newOrphanCollector(10*time.Minute, 30*time.Minute, false)
+		collector.now = func() time.Time { return now }
+		collector.register(mockClient, projectID, region, machineClass)
+
+		servers = []*client.Server{{ID: "server-1", Name: "machine-1", Labels: machineLabels("machine-1")}}
+		volumes = []*client.Volume{
+			{ID: "volume-attached", Labels: machineLabels("machine-1"), ServerID: "server-1"},
+			{ID: "volume-live", Labels: machineLabels("machine-1")},
+			{ID: "volume-orphan", Labels: machineLabels("machine-2")},
+			{ID: "volume-kept", Labels: map[string]string{
+				StackitMachineLabel: "machine-2", StackitMachineClassLabel: machineClass,
+				StackitDataVolumeLabel: "data", StackitDeleteOnTerminationLabel: "false",
+			}},
+			{ID: "volume-unlabelled", Labels: map[string]string{StackitMachineClassLabel: machineClass}},
+		}
+		nics = []*client.NIC{
+			{ID: "nic-orphan", NetworkID: "network-1", Labels: machineLabels("machine-2")},
+			{ID: "nic-attached", NetworkID: "network-1", Labels: machineLabels("machine-2"), ServerID: "server-3"},
+		}
+		publicIPs = []*client.PublicIP{
+			{ID: "ip-orphan", Labels: machineLabels("machine-2")},
+			{ID: "ip-pool", Labels: map[string]string{
+				StackitMachineLabel: "machine-2", StackitMachineClassLabel: machineClass, StackitPublicIPPoolLabel: "true",
+			}},
+			{ID: "ip-associated", Labels: machineLabels("machine-2"), NICID: "nic-attached"},
+		}
+
+		deletedVolumes, deletedNICs, deletedPublicIPs = nil, nil, nil
+		updatedPublicIPs = make(map[string]*client.UpdatePublicIPRequest)
+
+		mockClient.ListServersFunc = func(_ context.Context, _, _ string, selector map[string]string) ([]*client.Server, error) {
+			Expect(selector).To(Equal(map[string]string{StackitMachineClassLabel: machineClass}))
+			return servers, nil
+		}
+		mockClient.ListVolumesFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Volume, error) {
+			return volumes, nil
+		}
+		mockClient.ListNICsFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.NIC, error) {
+			return nics, nil
+		}
+		mockClient.ListPublicIPsFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.PublicIP, error) {
+			return publicIPs, nil
+		}
+		mockClient.DeleteVolumeFunc = func(_ context.Context, _, _, volumeID string) error {
+			deletedVolumes = append(deletedVolumes, volumeID)
+			return nil
+		}
+		mockClient.DeleteNICFunc = func(_ context.Context, _, _, networkID, nicID string) error {
+			Expect(networkID).To(Equal("network-1"))
+			deletedNICs = append(deletedNICs, nicID)
+			return nil
+		}
+		mockClient.DeletePublicIPFunc = func(_ context.Context, _, _, publicIPID string) error {
+			deletedPublicIPs = append(deletedPublicIPs, publicIPID)
+			return nil
+		}
+		mockClient.UpdatePublicIPFunc = func(_ context.Context, _, _, publicIPID string, req *client.UpdatePublicIPRequest) (*client.PublicIP, error) {
+			updatedPublicIPs[publicIPID] = req
+			return &client.PublicIP{ID: publicIPID}, nil
+		}
+	})
+
+	It("should delete orphaned resources once the grace period is over", func() {
+		foundVolumes := testutil.ToFloat64(orphanResourcesFound.WithLabelValues(resourceVolume))
+		deletedVolumeCount := testutil.ToFloat64(orphanResourcesDeleted.WithLabelValues(resourceVolume))
+
+		collector.collect(ctx)
+		Expect(deletedVolumes).To(BeEmpty())
+		Expect(deletedNICs).To(BeEmpty())
+		Expect(deletedPublicIPs).To(BeEmpty())
+		Expect(testutil.ToFloat64(orphanResourcesFound.WithLabelValues(resourceVolume))).To(Equal(foundVolumes + 1))
+
+		now = now.Add(30 * time.Minute)
+		collector.collect(ctx)
+
+		Expect(deletedVolumes).To(ConsistOf("volume-orphan"))
+		Expect(deletedNICs).To(ConsistOf("nic-orphan"))
+		Expect(deletedPublicIPs).To(ConsistOf("ip-orphan"))
+		Expect(testutil.ToFloat64(orphanResourcesFound.WithLabelValues(resourceVolume))).To(Equal(foundVolumes + 1))
+		Expect(testutil.ToFloat64(orphanResourcesDeleted.WithLabelValues(resourceVolume))).To(Equal(deletedVolumeCount + 1))
+	})
+
+	It("should return orphaned pool IPs to their pool", func() {
+		collector.gracePeriod = 0
+
+		collector.collect(ctx)
+
+		Expect(deletedPublicIPs).NotTo(ContainElement("ip-pool"))
+		Expect(updatedPublicIPs).To(HaveKey("ip-pool"))
+		Expect(updatedPublicIPs["ip-pool"].Labels).NotTo(HaveKey(StackitMachineLabel))
+		Expect(updatedPublicIPs["ip-pool"].DissociateNIC).To(BeTrue())
+	})
+
+	It("should collect the boot volume and the NICs of a server once they are labelled", func() {
+		collector.gracePeriod = 0
+		// the IaaS API creates the boot volume and the NICs of a server without labels
+		bootVolume := &client.Volume{ID: "volume-boot"}
+		serverNIC := &client.NIC{ID: "nic-server", NetworkID: "network-1"}
+		volumes = append(volumes, bootVolume)
+		nics = append(nics, serverNIC)
+		matches := func(labels, selector map[string]string) bool {
+			for key, value := range selector {
+				if labels[key] != value {
+					return false
+				}
+			}
+			return true
+		}
+		mockClient.ListVolumesFunc = func(_ context.Context, _, _ string, selector map[string]string) ([]*client.Volume, error) {
+			return slices.DeleteFunc(slices.Clone(volumes), func(v *client.Volume) bool { return !matches(v.Labels, selector) }), nil
+		}
+		mockClient.ListNICsFunc = func(_ context.Context, _, _ string, selector map[string]string) ([]*client.NIC, error) {
+			return slices.DeleteFunc(slices.Clone(nics), func(n *client.NIC) bool { return !matches(n.Labels, selector) }), nil
+		}
+
+		collector.collect(ctx)
+		Expect(deletedVolumes).NotTo(ContainElement("volume-boot"))
+		Expect(deletedNICs).NotTo(ContainElement("nic-server"))
+
+		mockClient.GetVolumeFunc = func(_ context.Context, _, _, _ string) (*client.Volume, error) {
+			return bootVolume, nil
+		}
+		mockClient.UpdateVolumeFunc = func(_ context.Context, _, _, _ string, req *client.UpdateVolumeRequest) (*client.Volume, error) {
+			bootVolume.Labels = req.Labels
+			return bootVolume, nil
+		}
+		mockClient.GetNICFunc = func(_ context.Context, _, _, _ string) (*client.NIC, error) {
+			return serverNIC, nil
+		}
+		mockClient.UpdateNICLabelsFunc = func(_ context.Context, _, _, _, _ string, labels map[string]string) (*client.NIC, error) {
+			serverNIC.Labels = labels
+			return serverNIC, nil
+		}
+		server := &client.Server{ID: "server-3", BootVolumeID: "volume-boot"}
+		err := labelServerResources(ctx, mockClient, projectID, "machine-3", machineClass, "", server, []*client.NIC{{ID: "nic-server", NetworkID: "network-1"}}, &api.ProviderSpec{Region: region})
+		Expect(err).NotTo(HaveOccurred())
+
+		collector.collect(ctx)
+		Expect(deletedVolumes).To(ContainElement("volume-boot"))
+		Expect(deletedNICs).To(ContainElement("nic-server"))
+	})
+
+	It("should collect the boot volume of a machine class with deleteOnTermination false", func() {
+		collector.gracePeriod = 0
+		bootVolume := &client.Volume{ID: "volume-boot"}
+		mockClient.GetVolumeFunc = func(_ context.Context, _, _, _ string) (*client.Volume, error) {
+			return bootVolume, nil
+		}
+		mockClient.UpdateVolumeFunc = func(_ context.Context, _, _, _ string, req *client.UpdateVolumeRequest) (*client.Volume, error) {
+			bootVolume.Labels = req.Labels
+			return bootVolume, nil
+		}
+		server := &client.Server{ID: "server-3", BootVolumeID: "volume-boot"}
+		providerSpec := &api.ProviderSpec{Region: region, BootVolume: &api.BootVolumeSpec{DeleteOnTermination: new(false), Size: 50}}
+		Expect(labelServerResources(ctx, mockClient, projectID, "machine-3", machineClass, "", server, nil, providerSpec)).To(Succeed())
+		// the server of machine-3 is gone, its boot volume was kept and is detached
+		volumes = append(volumes, bootVolume)
+
+		collector.collect(ctx)
+
+		Expect(deletedVolumes).To(ContainElement("volume-boot"))
+		Expect(deletedVolumes).NotTo(ContainElement("volume-kept"))
+	})
+
+	It("should collect resources of an earlier machine with the same name", func() {
+		collector.gracePeriod = 0
+		servers = []*client.Server{{ID: "server-1", Name: "machine-1", Labels: map[string]string{
+			StackitMachineLabel: "machine-1", StackitMachineClassLabel: machineClass, StackitMachineUIDLabel: "new-uid",
+		}}}
+		volumes = []*client.Volume{
+			{ID: "volume-old", Labels: map[string]string{
+				StackitMachineLabel: "machine-1", StackitMachineClassLabel: machineClass, StackitMachineUIDLabel: "old-uid",
+			}},
+			{ID: "volume-new", Labels: map[string]string{
+				StackitMachineLabel: "machine-1", StackitMachineClassLabel: machineClass, StackitMachineUIDLabel: "new-uid",
+			}},
+			{ID: "volume-legacy", Labels: machineLabels("machine-1")},
+		}
+
+		collector.collect(ctx)
+
+		Expect(deletedVolumes).To(ConsistOf("volume-old"))
+	})
+
+	It("should only log orphans in dry-run mode", func() {
+		collector.gracePeriod = 0
+		collector.dryRun = true
+
+		collector.collect(ctx)
+
+		Expect(deletedVolumes).To(BeEmpty())
+		Expect(deletedNICs).To(BeEmpty())
+		Expect(deletedPublicIPs).To(BeEmpty())
+		Expect(updatedPublicIPs).To(BeEmpty())
+	})
+
+	It("should restart the grace period of resources that were no longer orphaned", func() {
+		collector.collect(ctx)
+
+		now = now.Add(10 * time.Minute)
+		servers = append(servers, &client.Server{ID: "server-2", Name: "machine-2", Labels: machineLabels("machine-2")})
+		collector.collect(ctx)
+
+		now = now.Add(10 * time.Minute)
+		servers = servers[:1]
+		collector.collect(ctx)
+
+		now = now.Add(20 * time.Minute)
+		collector.collect(ctx)
+		Expect(deletedVolumes).To(BeEmpty())
+
+		now = now.Add(10 * time.Minute)
+		collector.collect(ctx)
+		Expect(deletedVolumes).To(ConsistOf("volume-orphan"))
+	})
+
+	It("should retry failed deletions in the next run", func() {
+		collector.gracePeriod = 0
+		mockClient.DeleteVolumeFunc = func(_ context.Context, _, _, _ string) error {
+			return &client.APIError{Class: client.ErrConflict, StatusCode: 409}
+		}
+
+		collector.collect(ctx)
+		Expect(collector.orphanedSince).To(HaveKey("volume/volume-orphan"))
+
+		mockClient.DeleteVolumeFunc = func(_ context.Context, _, _, volumeID string) error {
+			deletedVolumes = append(deletedVolumes, volumeID)
+			return nil
+		}
+		collector.collect(ctx)
+		Expect(deletedVolumes).To(ConsistOf("volume-orphan"))
+		Expect(collector.orphanedSince).NotTo(HaveKey("volume/volume-orphan"))
+	})
+
+	It("should not delete anything if the servers cannot be listed", func() {
+		collector.gracePeriod = 0
+		mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+			return nil, &client.APIError{Class: client.ErrServerError, StatusCode: 503}
+		}
+
+		collector.collect(ctx)
+
+		Expect(deletedVolumes).To(BeEmpty())
+		Expect(deletedNICs).To(BeEmpty())
+		Expect(deletedPublicIPs).To(BeEmpty())
+	})
+
+	It("should stop collecting MachineClasses that are no longer listed", func() {
+		collector.gracePeriod = 0
+		now = now.Add(orphanScopeTTL + time.Minute)
+
+		collector.collect(ctx)
+
+		Expect(deletedVolumes).To(BeEmpty())
+		Expect(collector.scopes).To(BeEmpty())
+	})
+
+	It("should collect the MachineClasses listed by ListMachines", func() {
+		provider := &Provider{client: mockClient, orphans: newOrphanCollector(10*time.Minute, 0, false)}
+		providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{MachineType: "c2i.2", ImageID: "image-uuid-123", Region: region})
+
+		_, err := provider.ListMachines(ctx, &driver.ListMachinesRequest{
+			MachineClass: &v1alpha1.MachineClass{
+				ObjectMeta:   metav1.ObjectMeta{Name: machineClass},
+				ProviderSpec: runtime.RawExtension{Raw: providerSpecRaw},
+			},
+			Secret: &corev1.Secret{Data: map[string][]byte{
+				"project-id":          []byte(projectID),
+				"serviceaccount.json": []byte(`{"credentials":{"iss":"test"}}`),
+			}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		provider.orphans.collect(ctx)
+		Expect(deletedVolumes).To(ConsistOf("volume-orphan"))
+	})
+})
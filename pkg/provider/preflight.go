@@ -0,0 +1,302 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+)
+
+// preflightFailureTTL is how long a failed preflight is reused before the resources are checked again
+// Missing resources are often created after the MachineClass, so failures are not kept for the whole generation
+const preflightFailureTTL = time.Minute
+
+// preflightResult is the outcome of a preflight for one generation of a MachineClass
+type preflightResult struct {
+	generation int64
+	projectID  string
+	errs       field.ErrorList
+	checkedAt  time.Time
+}
+
+// preflightCache holds the preflight results keyed by MachineClass
+//
+// Design: Check once per MachineClass generation
+// - Any change of the MachineClass spec bumps its generation and triggers a new preflight
+// - Only the latest generation of a MachineClass is kept, so the cache is bounded by the number of MachineClasses
+// - Successful results are kept for the whole generation, failures for preflightFailureTTL
+// - A nil cache disables the preflight
+type preflightCache struct {
+	mu      sync.Mutex
+	results map[string]preflightResult // namespace/name -> result
+	now     func() time.Time           // injectable for tests
+}
+
+// newPreflightCache returns an empty preflightCache
+func newPreflightCache() *preflightCache {
+	return &preflightCache{
+		results: make(map[string]preflightResult),
+		now:     time.Now,
+	}
+}
+
+// check returns the field errors of the preflight for the MachineClass, running it if no valid result is cached
+// Errors other than missing or unfit resources are returned as error and not cached
+// Thread-safe: the mutex is not held while the IaaS API is called
+func (pc *preflightCache) check(ctx context.Context, c client.StackitClient, projectID string, machineClass *v1alpha1.MachineClass, providerSpec *api.ProviderSpec) (field.ErrorList, error) {
+	if pc == nil {
+		return nil, nil
+	}
+
+	key := machineClass.Namespace + "/" + machineClass.Name
+
+	pc.mu.Lock()
+	result, ok := pc.results[key]
+	pc.mu.Unlock()
+	if ok && result.generation == machineClass.Generation && result.projectID == projectID &&
+		(len(result.errs) == 0 || pc.now().Before(result.checkedAt.Add(preflightFailureTTL))) {
+		return result.errs, nil
+	}
+
+	errs, err := preflightProviderSpec(ctx, c, projectID, providerSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	pc.mu.Lock()
+	pc.results[key] = preflightResult{generation: machineClass.Generation, projectID: projectID, errs: errs, checkedAt: pc.now()}
+	pc.mu.Unlock()
+
+	if len(errs) > 0 {
+		klog.Warningf("Preflight of machine class %q generation %d failed: %v", key, machineClass.Generation, errs.ToAggregate())
+	} else {
+		klog.V(2).Infof("Preflight of machine class %q generation %d succeeded", key, machineClass.Generation)
+	}
+	return errs, nil
+}
+
+// preflightProviderSpec checks through the IaaS API that the resources referenced by the ProviderSpec exist and fit together
+//
+// Checked resources:
+//   - imageId, the image the image selector resolves to and bootVolume.source of type image or volume
+//   - machineType and machineTypes
+//   - networking.networkId and networking.nicIds
+//   - securityGroups, affinityGroup, keypairName and volumes
+//
+// Volumes must be in availabilityZone if it is set, otherwise all volumes must be in the same zone.
+//
+// Limitations, the IaaS API reports neither of them:
+//   - machine types are checked for the region, not for availabilityZone or availabilityZones
+//   - networks are checked to exist, not to be usable in every zone of availabilityZones
+func preflightProviderSpec(ctx context.Context, c client.StackitClient, projectID string, providerSpec *api.ProviderSpec) (field.ErrorList, error) {
+	p := &preflight{ctx: ctx, c: c, projectID: projectID, region: providerSpec.Region}
+	root := field.NewPath("providerSpec")
+
+	var image *client.Image
+	switch {
+	case providerSpec.ImageID != "":
+		image = p.image(root.Child("imageId"), providerSpec.ImageID)
+	case providerSpec.Image != nil:
+		image = p.imageSelector(root.Child("image"), providerSpec.Image)
+	}
+	if image != nil && providerSpec.BootVolume != nil && providerSpec.BootVolume.Size > 0 && providerSpec.BootVolume.Size < image.MinDiskSize {
+		p.errs = append(p.errs, field.Invalid(root.Child("bootVolume", "size"), providerSpec.BootVolume.Size,
+			fmt.Sprintf("must be at least the minimum disk size of the image (%d GB)", image.MinDiskSize)))
+	}
+
+	var volumes []*zonedVolume
+	if providerSpec.BootVolume != nil && providerSpec.BootVolume.Source != nil {
+		path := root.Child("bootVolume", "source", "id")
+		switch source := providerSpec.BootVolume.Source; source.Type {
+		case "image":
+			p.image(path, source.ID)
+		case "volume":
+			if volume := p.volume(path, source.ID); volume != nil {
+				volumes = append(volumes, &zonedVolume{path: path, volume: volume})
+			}
+		}
+	}
+
+	if len(providerSpec.MachineTypes) > 0 {
+		for i, machineType := range providerSpec.MachineTypes {
+			p.machineType(root.Child("machineTypes").Index(i), machineType)
+		}
+	} else if providerSpec.MachineType != "" {
+		p.machineType(root.Child("machineType"), providerSpec.MachineType)
+	}
+
+	if providerSpec.Networking != nil {
+		if providerSpec.Networking.NetworkID != "" {
+			p.get(root.Child("networking", "networkId"), "network", providerSpec.Networking.NetworkID, func() error {
+				_, err := c.GetNetwork(ctx, projectID, p.region, providerSpec.Networking.NetworkID)
+				return err
+			})
+		}
+		for i, nicID := range providerSpec.Networking.NICIDs {
+			p.get(root.Child("networking", "nicIds").Index(i), "NIC", nicID, func() error {
+				_, err := c.GetNIC(ctx, projectID, p.region, nicID)
+				return err
+			})
+		}
+	}
+
+	for i, securityGroupID := range providerSpec.SecurityGroups {
+		p.get(root.Child("securityGroups").Index(i), "security group", securityGroupID, func() error {
+			_, err := c.GetSecurityGroup(ctx, projectID, p.region, securityGroupID)
+			return err
+		})
+	}
+
+	if providerSpec.AffinityGroup != "" {
+		p.get(root.Child("affinityGroup"), "affinity group", providerSpec.AffinityGroup, func() error {
+			_, err := c.GetAffinityGroup(ctx, projectID, p.region, providerSpec.AffinityGroup)
+			return err
+		})
+	}
+
+	if providerSpec.KeypairName != "" {
+		p.get(root.Child("keypairName"), "keypair", providerSpec.KeypairName, func() error {
+			_, err := c.GetKeypair(ctx, providerSpec.KeypairName)
+			return err
+		})
+	}
+
+	for i, volumeID := range providerSpec.Volumes {
+		path := root.Child("volumes").Index(i)
+		if volume := p.volume(path, volumeID); volume != nil {
+			volumes = append(volumes, &zonedVolume{path: path, volume: volume})
+		}
+	}
+	p.errs = append(p.errs, volumeZoneErrors(providerSpec.AvailabilityZone, volumes)...)
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.errs, nil
+}
+
+// preflight collects the results of the preflight checks
+// The first error other than NotFound stops further API calls
+type preflight struct {
+	ctx       context.Context
+	c         client.StackitClient
+	projectID string
+	region    string
+	errs      field.ErrorList
+	err       error
+}
+
+// get runs the lookup of a resource and records a NotFound field error if it does not exist
+// Lookups the service account may not run or the API does not support skip the check with a warning,
+// the server create reports the resource if it is unfit.
+// Returns false if the resource could not be retrieved
+func (p *preflight) get(path *field.Path, kind, value string, lookup func() error) bool {
+	if p.err != nil {
+		return false
+	}
+
+	err := lookup()
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, client.ErrNotFound):
+		p.errs = append(p.errs, field.NotFound(path, value))
+	case errors.Is(err, client.ErrForbidden), errors.Is(err, client.ErrNotImplemented):
+		klog.Warningf("Skipping preflight check of %s %q (%s): %v", kind, value, path, err)
+	default:
+		p.err = fmt.Errorf("failed to get %s %q: %w", kind, value, err)
+	}
+	return false
+}
+
+// image checks that the image exists and is AVAILABLE
+func (p *preflight) image(path *field.Path, imageID string) *client.Image {
+	var image *client.Image
+	if !p.get(path, "image", imageID, func() (err error) {
+		image, err = p.c.GetImage(p.ctx, p.projectID, p.region, imageID)
+		return err
+	}) {
+		return nil
+	}
+
+	if image.Status != imageStatusAvailable {
+		p.errs = append(p.errs, field.Invalid(path, imageID, fmt.Sprintf("image is %s, servers can only be created from AVAILABLE images", image.Status)))
+	}
+	return image
+}
+
+// imageSelector checks that an image matches the image selector and is usable
+// The selector is resolved the same way CreateMachine resolves it, without the image cache
+func (p *preflight) imageSelector(path *field.Path, selector *api.ImageSelectorSpec) *client.Image {
+	var imageID string
+	var resolveErr error
+	if !p.get(path, "image selector", describeImageSelector(selector), func() error {
+		imageID, resolveErr = resolveImage(p.ctx, p.c, p.projectID, p.region, selector)
+		if errors.Is(resolveErr, errNoMatchingImage) {
+			return nil
+		}
+		return resolveErr
+	}) {
+		return nil
+	}
+
+	if resolveErr != nil {
+		p.errs = append(p.errs, field.Invalid(path, describeImageSelector(selector), "no AVAILABLE image matches the selector"))
+		return nil
+	}
+	return p.image(path, imageID)
+}
+
+// machineType checks that the machine type exists in the region
+// Availability in the zones is not checked, the API reports machine types per region only
+func (p *preflight) machineType(path *field.Path, machineType string) {
+	p.get(path, "machine type", machineType, func() error {
+		_, err := p.c.GetMachineType(p.ctx, p.projectID, p.region, machineType)
+		return err
+	})
+}
+
+// volume checks that the volume exists
+func (p *preflight) volume(path *field.Path, volumeID string) *client.Volume {
+	var volume *client.Volume
+	if !p.get(path, "volume", volumeID, func() (err error) {
+		volume, err = p.c.GetVolume(p.ctx, p.projectID, p.region, volumeID)
+		return err
+	}) {
+		return nil
+	}
+	return volume
+}
+
+// zonedVolume is a volume referenced by the ProviderSpec
+type zonedVolume struct {
+	path   *field.Path
+	volume *client.Volume
+}
+
+// volumeZoneErrors checks that the volumes can be attached to the same server
+// Volumes must be in the availability zone if it is set, otherwise in the zone of the first volume
+func volumeZoneErrors(availabilityZone string, volumes []*zonedVolume) field.ErrorList {
+	var errs field.ErrorList
+
+	zone := availabilityZone
+	for _, v := range volumes {
+		if zone == "" {
+			zone = v.volume.AvailabilityZone
+			continue
+		}
+		if v.volume.AvailabilityZone != zone {
+			errs = append(errs, field.Invalid(v.path, v.volume.ID,
+				fmt.Sprintf("volume is in availability zone %q, the server is created in %q", v.volume.AvailabilityZone, zone)))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,295 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/status"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Preflight", func() {
+	const (
+		projectID = "11111111-2222-3333-4444-555555555555"
+		networkID = "770e8400-e29b-41d4-a716-446655440000"
+	)
+
+	var (
+		ctx          context.Context
+		mockClient   *mock.StackitClient
+		providerSpec *api.ProviderSpec
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockClient = &mock.StackitClient{}
+		providerSpec = &api.ProviderSpec{
+			MachineType:    "c2i.2",
+			ImageID:        "12345678-1234-1234-1234-123456789abc",
+			Region:         "eu01",
+			Networking:     &api.NetworkingSpec{NetworkID: networkID},
+			SecurityGroups: []string{"880e8400-e29b-41d4-a716-446655440001", "880e8400-e29b-41d4-a716-446655440002"},
+			KeypairName:    "my-key",
+		}
+	})
+
+	Describe("preflightProviderSpec", func() {
+		It("should succeed when all referenced resources exist", func() {
+			errs, err := preflightProviderSpec(ctx, mockClient, projectID, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("should report missing resources with their field", func() {
+			mockClient.GetNetworkFunc = func(_ context.Context, _, _, _ string) (*client.Network, error) {
+				return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+			}
+			mockClient.GetSecurityGroupFunc = func(_ context.Context, _, _, securityGroupID string) (*client.SecurityGroup, error) {
+				if securityGroupID == "880e8400-e29b-41d4-a716-446655440002" {
+					return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+				}
+				return &client.SecurityGroup{ID: securityGroupID}, nil
+			}
+
+			errs, err := preflightProviderSpec(ctx, mockClient, projectID, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Field).To(Equal("providerSpec.networking.networkId"))
+			Expect(errs[1].Field).To(Equal("providerSpec.securityGroups[1]"))
+		})
+
+		It("should check every machine type of the fallback list", func() {
+			providerSpec.MachineType = ""
+			providerSpec.MachineTypes = []string{"c3i.2", "c9z.2"}
+			mockClient.GetMachineTypeFunc = func(_ context.Context, _, _, machineType string) (*client.MachineType, error) {
+				if machineType == "c9z.2" {
+					return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+				}
+				return &client.MachineType{Name: machineType}, nil
+			}
+
+			errs, err := preflightProviderSpec(ctx, mockClient, projectID, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("providerSpec.machineTypes[1]"))
+		})
+
+		It("should report images that are not available or too large for the boot volume", func() {
+			providerSpec.BootVolume = &api.BootVolumeSpec{Size: 10}
+			mockClient.GetImageFunc = func(_ context.Context, _, _, imageID string) (*client.Image, error) {
+				return &client.Image{ID: imageID, Status: "CREATING", MinDiskSize: 20}, nil
+			}
+
+			errs, err := preflightProviderSpec(ctx, mockClient, projectID, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Field).To(Equal("providerSpec.imageId"))
+			Expect(errs[1].Field).To(Equal("providerSpec.bootVolume.size"))
+		})
+
+		It("should report volumes outside the availability zone", func() {
+			providerSpec.AvailabilityZone = "eu01-1"
+			providerSpec.Volumes = []string{"volume-1", "volume-2"}
+			mockClient.GetVolumeFunc = func(_ context.Context, _, _, volumeID string) (*client.Volume, error) {
+				zone := "eu01-1"
+				if volumeID == "volume-2" {
+					zone = "eu01-2"
+				}
+				return &client.Volume{ID: volumeID, AvailabilityZone: zone}, nil
+			}
+
+			errs, err := preflightProviderSpec(ctx, mockClient, projectID, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("providerSpec.volumes[1]"))
+			Expect(errs[0].Detail).To(ContainSubstring(`"eu01-2"`))
+		})
+
+		It("should check the image the image selector resolves to", func() {
+			providerSpec.ImageID = ""
+			providerSpec.Image = &api.ImageSelectorSpec{Name: "ubuntu"}
+			providerSpec.BootVolume = &api.BootVolumeSpec{Size: 10}
+			mockClient.ListImagesFunc = func(_ context.Context, _, _ string) ([]*client.Image, error) {
+				return []*client.Image{{ID: "image-1", Name: "ubuntu", Status: "AVAILABLE"}}, nil
+			}
+			mockClient.GetImageFunc = func(_ context.Context, _, _, imageID string) (*client.Image, error) {
+				Expect(imageID).To(Equal("image-1"))
+				return &client.Image{ID: imageID, Status: "AVAILABLE", MinDiskSize: 20}, nil
+			}
+
+			errs, err := preflightProviderSpec(ctx, mockClient, projectID, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("providerSpec.bootVolume.size"))
+		})
+
+		It("should report image selectors no image matches", func() {
+			providerSpec.ImageID = ""
+			providerSpec.Image = &api.ImageSelectorSpec{Name: "ubuntu"}
+			mockClient.ListImagesFunc = func(_ context.Context, _, _ string) ([]*client.Image, error) {
+				return []*client.Image{{ID: "image-1", Name: "debian", Status: "AVAILABLE"}}, nil
+			}
+
+			errs, err := preflightProviderSpec(ctx, mockClient, projectID, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("providerSpec.image"))
+			Expect(errs[0].Detail).To(ContainSubstring("no AVAILABLE image matches"))
+		})
+
+		It("should skip checks of lookups that are forbidden or not implemented", func() {
+			providerSpec.AffinityGroup = "990e8400-e29b-41d4-a716-446655440000"
+			providerSpec.Volumes = []string{"aa0e8400-e29b-41d4-a716-446655440000"}
+			mockClient.GetKeypairFunc = func(_ context.Context, _ string) (*client.Keypair, error) {
+				return nil, &client.APIError{Class: client.ErrForbidden, StatusCode: 403}
+			}
+			mockClient.GetAffinityGroupFunc = func(_ context.Context, _, _, _ string) (*client.AffinityGroup, error) {
+				return nil, &client.APIError{Class: client.ErrNotImplemented, StatusCode: 501}
+			}
+			volumeCalled := false
+			mockClient.GetVolumeFunc = func(_ context.Context, _, _, volumeID string) (*client.Volume, error) {
+				volumeCalled = true
+				return &client.Volume{ID: volumeID}, nil
+			}
+
+			errs, err := preflightProviderSpec(ctx, mockClient, projectID, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(BeEmpty())
+			Expect(volumeCalled).To(BeTrue())
+		})
+
+		It("should return other API errors instead of field errors", func() {
+			mockClient.GetKeypairFunc = func(_ context.Context, _ string) (*client.Keypair, error) {
+				return nil, &client.APIError{Class: client.ErrServerError, StatusCode: 500}
+			}
+
+			_, err := preflightProviderSpec(ctx, mockClient, projectID, providerSpec)
+
+			Expect(err).To(MatchError(client.ErrServerError))
+		})
+	})
+
+	Describe("preflightCache", func() {
+		var (
+			cache        *preflightCache
+			machineClass *v1alpha1.MachineClass
+			networkCalls int
+			networkErr   error
+			now          time.Time
+		)
+
+		BeforeEach(func() {
+			now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+			cache = newPreflightCache()
+			cache.now = func() time.Time { return now }
+			machineClass = &v1alpha1.MachineClass{ObjectMeta: metav1.ObjectMeta{Name: "test-machine-class", Namespace: "default", Generation: 1}}
+			networkCalls = 0
+			networkErr = nil
+			mockClient.GetNetworkFunc = func(_ context.Context, _, _, networkID string) (*client.Network, error) {
+				networkCalls++
+				if networkErr != nil {
+					return nil, networkErr
+				}
+				return &client.Network{ID: networkID}, nil
+			}
+		})
+
+		It("should check each generation of a MachineClass once", func() {
+			for range 3 {
+				errs, err := cache.check(ctx, mockClient, projectID, machineClass, providerSpec)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(errs).To(BeEmpty())
+			}
+			Expect(networkCalls).To(Equal(1))
+
+			machineClass.Generation = 2
+			_, err := cache.check(ctx, mockClient, projectID, machineClass, providerSpec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(networkCalls).To(Equal(2))
+		})
+
+		It("should check failed generations again after the failure TTL", func() {
+			networkErr = &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+
+			errs, err := cache.check(ctx, mockClient, projectID, machineClass, providerSpec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(HaveLen(1))
+
+			networkErr = nil
+			errs, _ = cache.check(ctx, mockClient, projectID, machineClass, providerSpec)
+			Expect(errs).To(HaveLen(1))
+			Expect(networkCalls).To(Equal(1))
+
+			now = now.Add(preflightFailureTTL)
+			errs, _ = cache.check(ctx, mockClient, projectID, machineClass, providerSpec)
+			Expect(errs).To(BeEmpty())
+			Expect(networkCalls).To(Equal(2))
+		})
+
+		It("should not cache transient errors", func() {
+			networkErr = &client.APIError{Class: client.ErrServerError, StatusCode: 503}
+
+			_, err := cache.check(ctx, mockClient, projectID, machineClass, providerSpec)
+			Expect(err).To(MatchError(client.ErrServerError))
+
+			networkErr = nil
+			errs, err := cache.check(ctx, mockClient, projectID, machineClass, providerSpec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(BeEmpty())
+			Expect(networkCalls).To(Equal(2))
+		})
+	})
+
+	Describe("CreateMachine", func() {
+		It("should return InvalidArgument with the offending field", func() {
+			providerSpecRaw, _ := mock.EncodeProviderSpec(providerSpec)
+			req := &driver.CreateMachineRequest{
+				Machine: &v1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}},
+				MachineClass: &v1alpha1.MachineClass{
+					ObjectMeta:   metav1.ObjectMeta{Name: "test-machine-class"},
+					Provider:     "stackit",
+					ProviderSpec: runtime.RawExtension{Raw: providerSpecRaw},
+				},
+				Secret: &corev1.Secret{Data: map[string][]byte{
+					"project-id":          []byte(projectID),
+					"serviceaccount.json": []byte(`{"credentials":{"iss":"test"}}`),
+				}},
+			}
+			mockClient.GetKeypairFunc = func(_ context.Context, _ string) (*client.Keypair, error) {
+				return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+			}
+			createCalled := false
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, _ *client.CreateServerRequest) (*client.Server, error) {
+				createCalled = true
+				return &client.Server{ID: "server-1"}, nil
+			}
+
+			_, err := (&Provider{client: mockClient, preflight: newPreflightCache()}).CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.InvalidArgument))
+			Expect(statusErr.Message()).To(ContainSubstring(`providerSpec.keypairName: Not found: "my-key"`))
+			Expect(createCalled).To(BeFalse())
+		})
+	})
+})
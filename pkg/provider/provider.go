@@ -1,74 +1,165 @@
 package provider
 
 import (
-	"fmt"
-	"sync"
+	"context"
 	"time"
 
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
 	client2 "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
 	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/spi"
-	"k8s.io/klog/v2"
 )
 
 // Provider is the struct that implements the driver interface
 // It is used to implement the basic driver functionalities
 //
-// Architecture: Single-tenant design
-// - Each provider instance is deployed per Gardener shoot (cluster)
-// - The STACKIT IaaS client is initialized lazily on first request using credentials from the Secret
-// - All subsequent requests reuse the same client (SDK handles token refresh automatically)
-// - Credential rotation requires pod restart (standard Kubernetes pattern)
+// Architecture: Multi-tenant design
+// - A single provider instance can serve MachineClasses bound to different STACKIT projects and service accounts
+// - The STACKIT IaaS client is resolved per request from the credentials in req.Secret
+// - Clients are cached per credential and reused across requests (SDK handles token refresh automatically)
+// - Credential rotation is picked up on the next request, no pod restart required
+//
+// The caches and the orphan collector are optional, their methods are no-ops on a nil receiver,
+// so tests can construct a bare Provider with only a client.
 type Provider struct {
-	SPI                 spi.SessionProviderInterface
-	client              client2.StackitClient // STACKIT API client (can be mocked for testing)
-	clientOnce          sync.Once             // Ensures client is initialized exactly once
-	clientErr           error                 // Stores initialization error if any
-	capturedCredentials string                // Service account key used for initialization (for defensive checks)
+	SPI     spi.SessionProviderInterface
+	client  client2.StackitClient // Static STACKIT API client, bypasses the cache when set (used to inject mocks in tests)
+	clients *clientCache          // Clients keyed by credential hash
+	// retryConfig configures the retries of the clients in the cache
+	retryConfig client2.RetryConfig
 	// intervals need to be configurable to speed up tests
 	pollingInterval time.Duration // Interval between polling attempts
 	pollingTimeout  time.Duration // Maximum time to wait during polling
+	// schedulingTimeout is how long CreateMachine waits for a new server to leave CREATING state, 0 disables the wait
+	schedulingTimeout time.Duration
+	// maxRecreateAttempts caps how often a server in ERROR state is replaced by CreateMachine
+	maxRecreateAttempts int
+	// csiDriverNames are the CSI drivers whose volumes are STACKIT block storage volumes
+	csiDriverNames []string
+	// images caches the images resolved from image selectors per MachineClass
+	images *imageCache
+	// exhaustedZones remembers availability zones that recently had no capacity for a machine type
+	exhaustedZones *zoneCache
+	// preflight caches the online checks of the resources referenced by a MachineClass, nil disables them
+	preflight *preflightCache
+	// lenientDecoding logs unknown ProviderSpec fields instead of rejecting the MachineClass
+	lenientDecoding bool
+	// orphans collects volumes, NICs and public IPs left behind by deleted machines, nil disables it
+	orphans *orphanCollector
 }
 
-// NewProvider returns an empty provider object
-func NewProvider(i spi.SessionProviderInterface) driver.Driver {
-	return &Provider{
-		SPI:             i,
-		pollingInterval: 5 * time.Second,
-		pollingTimeout:  10 * time.Minute,
+// Option configures optional Provider settings
+type Option func(*Provider)
+
+// WithRetryConfig sets how transient failures of IaaS calls are retried
+// Defaults to client.DefaultRetryConfig, which does not retry mutating calls
+func WithRetryConfig(config client2.RetryConfig) Option {
+	return func(p *Provider) {
+		p.retryConfig = config
 	}
 }
 
-// ensureClient initializes the STACKIT client on first use (lazy initialization)
-// This is called by all methods that need to interact with STACKIT API
-// Thread-safe via sync.Once
-//
-// Design: Single-credential lifecycle
-// - The serviceAccountKey parameter is captured and used ONLY on the first call
-// - Subsequent calls reuse the same client regardless of the serviceAccountKey passed
-// - Credential rotation requires pod restart (standard Kubernetes pattern)
-// - If a client is already set (e.g., mock client in tests), initialization is skipped
-func (p *Provider) ensureClient(serviceAccountKey string) error {
-	// If client is already set (e.g., mock client in tests), skip initialization
-	if p.client != nil {
-		return nil
+// WithSchedulingTimeout sets how long CreateMachine waits for a new server to leave CREATING state
+// Servers going into ERROR state meanwhile are replaced, 0 returns as soon as the server is accepted
+// Defaults to DefaultSchedulingTimeout
+func WithSchedulingTimeout(timeout time.Duration) Option {
+	return func(p *Provider) {
+		p.schedulingTimeout = timeout
+	}
+}
+
+// WithCSIDriverNames sets the CSI driver names recognised by GetVolumeIDs
+// Defaults to DefaultCSIDriverNames
+func WithCSIDriverNames(names ...string) Option {
+	return func(p *Provider) {
+		p.csiDriverNames = names
+	}
+}
+
+// WithImageCacheTTL sets how long an image resolved from an image selector is reused
+// Defaults to DefaultImageCacheTTL
+func WithImageCacheTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.images = newImageCache(ttl)
+	}
+}
+
+// WithExhaustedZoneTTL sets how long an availability zone without capacity is tried last
+// Defaults to DefaultExhaustedZoneTTL
+func WithExhaustedZoneTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.exhaustedZones = newZoneCache(ttl)
+	}
+}
+
+// WithPreflightValidation enables or disables the online check of the resources referenced by a MachineClass
+// Enabled by default
+func WithPreflightValidation(enabled bool) Option {
+	return func(p *Provider) {
+		p.preflight = nil
+		if enabled {
+			p.preflight = newPreflightCache()
+		}
 	}
+}
 
-	p.clientOnce.Do(func() {
-		client, err := client2.NewStackitClient(serviceAccountKey)
-		if err != nil {
-			p.clientErr = fmt.Errorf("failed to initialize STACKIT client: %w", err)
-			return
+// WithLenientDecoding logs unknown ProviderSpec fields as warnings instead of rejecting the MachineClass
+// Disabled by default
+func WithLenientDecoding(lenient bool) Option {
+	return func(p *Provider) {
+		p.lenientDecoding = lenient
+	}
+}
+
+// WithOrphanCollection enables the periodic deletion of volumes, NICs and public IPs left behind by deleted machines
+// Orphans are deleted after the grace period, in dry-run mode they are only logged and counted
+// Disabled by default or if interval is not positive
+func WithOrphanCollection(interval, gracePeriod time.Duration, dryRun bool) Option {
+	return func(p *Provider) {
+		p.orphans = nil
+		if interval > 0 {
+			p.orphans = newOrphanCollector(interval, gracePeriod, dryRun)
 		}
-		p.client = client
-		p.capturedCredentials = serviceAccountKey
-	})
+	}
+}
 
-	// Defensive check: warn if credentials changed after initialization
-	// This indicates the Secret was updated, which requires pod restart
-	if p.clientErr == nil && p.capturedCredentials != serviceAccountKey {
-		klog.Warning("Service account credentials changed after client initialization. Credential rotation requires pod restart. Continuing with original credentials.")
+// NewProvider returns an empty provider object
+func NewProvider(i spi.SessionProviderInterface, opts ...Option) driver.Driver {
+	p := &Provider{
+		SPI:                 i,
+		retryConfig:         client2.DefaultRetryConfig(),
+		pollingInterval:     5 * time.Second,
+		pollingTimeout:      10 * time.Minute,
+		schedulingTimeout:   DefaultSchedulingTimeout,
+		maxRecreateAttempts: 3,
+		csiDriverNames:      DefaultCSIDriverNames,
+		images:              newImageCache(DefaultImageCacheTTL),
+		exhaustedZones:      newZoneCache(DefaultExhaustedZoneTTL),
+		preflight:           newPreflightCache(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.clients = newClientCache(newSdkClientFactory(p.retryConfig))
+	if p.orphans != nil {
+		// runs for the lifetime of the process, same as the driver
+		go p.orphans.run(context.Background())
+	}
+	return p
+}
+
+// getClient returns the STACKIT client for the given credentials (lazy initialization)
+// This is called by all methods that need to interact with STACKIT API
+// Thread-safe via the client cache
+//
+// Design: Per-request client resolution
+// - Every driver call resolves its client from the Secret it was called with
+// - Clients are cached by a hash of projectID and serviceAccountKey
+// - If the Secret content changes, a new client is built, the stale one is evicted once it is idle
+// - If a static client is set (e.g., mock client in tests), it is returned for all credentials
+func (p *Provider) getClient(projectID, serviceAccountKey string) (client2.StackitClient, error) {
+	if p.client != nil {
+		return p.client, nil
 	}
 
-	return p.clientErr
+	return p.clients.get(projectID, serviceAccountKey)
 }
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// ensurePublicIP returns the public IP of a machine, allocating one or claiming one from the pool if needed
+//
+// Public IPs are looked up by the machine label, so an IP obtained by an earlier attempt is reused.
+// IPs left by an earlier Machine with the same name are not reused.
+// The IP is not associated here, the NIC of the server is only known once the server is ACTIVE.
+// Returns nil if the ProviderSpec does not request a public IP.
+func ensurePublicIP(ctx context.Context, c client.StackitClient, projectID, machineName, machineClassName, machineUID string, providerSpec *api.ProviderSpec) (*client.PublicIP, error) {
+	if providerSpec.PublicIP == nil {
+		return nil, nil
+	}
+
+	publicIPs, err := c.ListPublicIPs(ctx, projectID, providerSpec.Region, map[string]string{StackitMachineLabel: machineName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public IPs: %w", err)
+	}
+	for _, publicIP := range publicIPs {
+		if matchesMachineUID(publicIP.Labels, machineUID) {
+			return publicIP, nil
+		}
+	}
+
+	if len(providerSpec.PublicIP.PoolLabels) > 0 {
+		return claimPoolPublicIP(ctx, c, projectID, machineName, machineClassName, machineUID, providerSpec)
+	}
+
+	labels := make(map[string]string)
+	maps.Copy(labels, providerSpec.Labels)
+	maps.Copy(labels, machineLabels(machineName, machineClassName, machineUID))
+
+	publicIP, err := c.CreatePublicIP(ctx, projectID, providerSpec.Region, &client.CreatePublicIPRequest{Labels: labels})
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate public IP: %w", err)
+	}
+
+	klog.V(2).Infof("Allocated public IP %q (%q) for machine %q", publicIP.IP, publicIP.ID, machineName)
+	return publicIP, nil
+}
+
+// claimPoolPublicIP labels a free public IP of the pool with the machine labels
+// A pool IP is free if it is neither associated with a NIC nor claimed by another machine
+// The claim is read back, a candidate claimed concurrently by another machine is skipped.
+func claimPoolPublicIP(ctx context.Context, c client.StackitClient, projectID, machineName, machineClassName, machineUID string, providerSpec *api.ProviderSpec) (*client.PublicIP, error) {
+	pool, err := c.ListPublicIPs(ctx, projectID, providerSpec.Region, providerSpec.PublicIP.PoolLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public IP pool: %w", err)
+	}
+
+	for _, candidate := range pool {
+		if candidate.NICID != "" || candidate.Labels[StackitMachineLabel] != "" {
+			continue
+		}
+
+		labels := make(map[string]string, len(candidate.Labels)+4)
+		maps.Copy(labels, candidate.Labels)
+		maps.Copy(labels, machineLabels(machineName, machineClassName, machineUID))
+		labels[StackitPublicIPPoolLabel] = "true"
+
+		if _, err := c.UpdatePublicIP(ctx, projectID, providerSpec.Region, candidate.ID, &client.UpdatePublicIPRequest{Labels: labels}); err != nil {
+			return nil, fmt.Errorf("failed to claim public IP %q: %w", candidate.ID, err)
+		}
+
+		// the update does not check the labels it overwrites, another machine claiming the same
+		// IP concurrently wins if its update lands last
+		publicIP, err := c.GetPublicIP(ctx, projectID, providerSpec.Region, candidate.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify claim of public IP %q: %w", candidate.ID, err)
+		}
+		if owner := publicIP.Labels[StackitMachineLabel]; owner != machineName {
+			klog.V(2).Infof("Public IP %q (%q) of pool was claimed by machine %q, trying next candidate for machine %q", publicIP.IP, publicIP.ID, owner, machineName)
+			continue
+		}
+
+		klog.V(2).Infof("Claimed public IP %q (%q) from pool for machine %q", publicIP.IP, publicIP.ID, machineName)
+		return publicIP, nil
+	}
+
+	return nil, fmt.Errorf("%w: no free public IP in pool %v", client.ErrCapacityExhausted, providerSpec.PublicIP.PoolLabels)
+}
+
+// associatePublicIP associates the public IP with the primary NIC of the server
+// The primary NIC is the first NIC in the configured network, the returned NICs report the public IP
+func associatePublicIP(ctx context.Context, c client.StackitClient, projectID string, publicIP *client.PublicIP, nics []*client.NIC, providerSpec *api.ProviderSpec) ([]*client.NIC, error) {
+	index := slices.IndexFunc(nics, func(nic *client.NIC) bool {
+		return nicInConfiguredNetwork(nic, providerSpec)
+	})
+	if index < 0 {
+		return nil, fmt.Errorf("no NIC found to associate public IP %q with", publicIP.IP)
+	}
+	primary := nics[index]
+
+	if publicIP.NICID != primary.ID {
+		if _, err := c.UpdatePublicIP(ctx, projectID, providerSpec.Region, publicIP.ID, &client.UpdatePublicIPRequest{NICID: &primary.ID}); err != nil {
+			return nil, fmt.Errorf("failed to associate public IP %q with NIC %s: %w", publicIP.IP, primary.ID, err)
+		}
+		klog.V(2).Infof("Associated public IP %q with NIC %s", publicIP.IP, primary.ID)
+	}
+
+	primary.PublicIP = publicIP.IP
+	return nics, nil
+}
+
+// missingPublicIP returns true if a public IP is requested but not reported on the primary NIC of the server
+func missingPublicIP(server *client.Server, providerSpec *api.ProviderSpec) bool {
+	if providerSpec.PublicIP == nil {
+		return false
+	}
+	index := slices.IndexFunc(server.NICs, func(nic *client.NIC) bool {
+		return nicInConfiguredNetwork(nic, providerSpec)
+	})
+	return index < 0 || server.NICs[index].PublicIP == ""
+}
+
+// releasePublicIPs deletes the public IPs allocated for a machine and returns pool IPs to their pool
+// IPs of an earlier Machine with the same name are kept
+func releasePublicIPs(ctx context.Context, c client.StackitClient, projectID, region, machineName, machineUID string) error {
+	publicIPs, err := c.ListPublicIPs(ctx, projectID, region, map[string]string{StackitMachineLabel: machineName})
+	if err != nil {
+		return fmt.Errorf("failed to list public IPs: %w", err)
+	}
+
+	for _, publicIP := range publicIPs {
+		if !matchesMachineUID(publicIP.Labels, machineUID) {
+			klog.V(2).Infof("Keeping public IP %q (%q) of an earlier machine %q with UID %q", publicIP.IP, publicIP.ID, machineName, publicIP.Labels[StackitMachineUIDLabel])
+			continue
+		}
+		if err := releasePublicIP(ctx, c, projectID, region, publicIP); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releasePublicIP deletes a public IP allocated for a machine or returns it to its pool if it was taken from one
+func releasePublicIP(ctx context.Context, c client.StackitClient, projectID, region string, publicIP *client.PublicIP) error {
+	machineName := publicIP.Labels[StackitMachineLabel]
+	if publicIP.Labels[StackitPublicIPPoolLabel] != "true" {
+		if err := c.DeletePublicIP(ctx, projectID, region, publicIP.ID); err != nil && !errors.Is(err, client.ErrNotFound) {
+			return fmt.Errorf("failed to delete public IP %q: %w", publicIP.IP, err)
+		}
+		klog.V(2).Infof("Deleted public IP %q (%q) of machine %q", publicIP.IP, publicIP.ID, machineName)
+		return nil
+	}
+
+	// the labels of the update replace those of the public IP, so the machine labels are removed
+	labels := maps.Clone(publicIP.Labels)
+	delete(labels, StackitMachineLabel)
+	delete(labels, StackitMachineClassLabel)
+	delete(labels, StackitMachineUIDLabel)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	_, err := c.UpdatePublicIP(ctx, projectID, region, publicIP.ID, &client.UpdatePublicIPRequest{Labels: labels, DissociateNIC: true})
+	if err != nil && !errors.Is(err, client.ErrNotFound) {
+		return fmt.Errorf("failed to return public IP %q to its pool: %w", publicIP.IP, err)
+	}
+	klog.V(2).Infof("Returned public IP %q (%q) of machine %q to its pool", publicIP.IP, publicIP.ID, machineName)
+	return nil
+}
+
+// withPublicIP adds the public IP to the addresses as NodeExternalIP unless it is already reported
+func withPublicIP(addresses []corev1.NodeAddress, publicIP *client.PublicIP) []corev1.NodeAddress {
+	if publicIP == nil || publicIP.IP == "" {
+		return addresses
+	}
+	external := corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: publicIP.IP}
+	if slices.Contains(addresses, external) {
+		return addresses
+	}
+	return append(addresses, external)
+}
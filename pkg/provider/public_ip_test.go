@@ -0,0 +1,321 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/status"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Public IPs", func() {
+	const projectID = "11111111-2222-3333-4444-555555555555"
+
+	var (
+		ctx          context.Context
+		mockClient   *mock.StackitClient
+		providerSpec *api.ProviderSpec
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockClient = &mock.StackitClient{}
+		providerSpec = &api.ProviderSpec{
+			MachineType: "c2i.2",
+			ImageID:     "12345678-1234-1234-1234-123456789abc",
+			Region:      "eu01",
+			Labels:      map[string]string{"team": "edge"},
+			Networking:  &api.NetworkingSpec{NetworkID: "770e8400-e29b-41d4-a716-446655440000"},
+			PublicIP:    &api.PublicIPSpec{},
+		}
+	})
+
+	Describe("CreateMachine", func() {
+		var req *driver.CreateMachineRequest
+
+		BeforeEach(func() {
+			providerSpecRaw, _ := mock.EncodeProviderSpec(providerSpec)
+			req = &driver.CreateMachineRequest{
+				Machine: &v1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}},
+				MachineClass: &v1alpha1.MachineClass{
+					ObjectMeta:   metav1.ObjectMeta{Name: "test-machine-class"},
+					Provider:     "stackit",
+					ProviderSpec: runtime.RawExtension{Raw: providerSpecRaw},
+				},
+				Secret: &corev1.Secret{Data: map[string][]byte{
+					"project-id":          []byte(projectID),
+					"serviceaccount.json": []byte(`{"credentials":{"iss":"test"}}`),
+				}},
+			}
+		})
+
+		It("should allocate a public IP and report it as NodeExternalIP", func() {
+			var capturedReq *client.CreatePublicIPRequest
+			mockClient.CreatePublicIPFunc = func(_ context.Context, _, _ string, req *client.CreatePublicIPRequest) (*client.PublicIP, error) {
+				capturedReq = req
+				return &client.PublicIP{ID: "ip-1", IP: "192.0.2.10", Labels: req.Labels}, nil
+			}
+
+			resp, err := (&Provider{client: mockClient}).CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(capturedReq.Labels).To(HaveKeyWithValue(StackitMachineLabel, "test-machine"))
+			Expect(capturedReq.Labels).To(HaveKeyWithValue(StackitMachineClassLabel, "test-machine-class"))
+			Expect(capturedReq.Labels).To(HaveKeyWithValue("team", "edge"))
+			Expect(resp.Addresses).To(ContainElement(corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "192.0.2.10"}))
+		})
+
+		It("should return ResourceExhausted when the pool has no free public IP", func() {
+			providerSpec.PublicIP.PoolLabels = map[string]string{"pool": "edge"}
+			req.MachineClass.ProviderSpec.Raw, _ = mock.EncodeProviderSpec(providerSpec)
+			mockClient.ListPublicIPsFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.PublicIP, error) {
+				if labelSelector["pool"] == "edge" {
+					return []*client.PublicIP{{ID: "ip-1", IP: "192.0.2.10", NICID: "other-nic"}}, nil
+				}
+				return nil, nil
+			}
+			createCalled := false
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, _ *client.CreateServerRequest) (*client.Server, error) {
+				createCalled = true
+				return &client.Server{ID: "server-1"}, nil
+			}
+
+			_, err := (&Provider{client: mockClient}).CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.ResourceExhausted))
+			Expect(createCalled).To(BeFalse())
+		})
+	})
+
+	Describe("ensurePublicIP", func() {
+		It("should do nothing without publicIP in the ProviderSpec", func() {
+			providerSpec.PublicIP = nil
+
+			publicIP, err := ensurePublicIP(ctx, mockClient, projectID, "test-machine", "test-machine-class", "", providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(publicIP).To(BeNil())
+		})
+
+		It("should reuse the public IP of the machine", func() {
+			mockClient.ListPublicIPsFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.PublicIP, error) {
+				return []*client.PublicIP{{ID: "ip-1", IP: "192.0.2.10"}}, nil
+			}
+			mockClient.CreatePublicIPFunc = func(_ context.Context, _, _ string, _ *client.CreatePublicIPRequest) (*client.PublicIP, error) {
+				Fail("CreatePublicIP must not be called")
+				return nil, nil
+			}
+
+			publicIP, err := ensurePublicIP(ctx, mockClient, projectID, "test-machine", "test-machine-class", "", providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(publicIP.ID).To(Equal("ip-1"))
+		})
+
+		It("should allocate a public IP instead of reusing the one of an earlier machine with the same name", func() {
+			mockClient.ListPublicIPsFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.PublicIP, error) {
+				return []*client.PublicIP{{ID: "ip-old", IP: "192.0.2.10", Labels: map[string]string{StackitMachineUIDLabel: "old-uid"}}}, nil
+			}
+			var createdLabels map[string]string
+			mockClient.CreatePublicIPFunc = func(_ context.Context, _, _ string, req *client.CreatePublicIPRequest) (*client.PublicIP, error) {
+				createdLabels = req.Labels
+				return &client.PublicIP{ID: "ip-new", IP: "192.0.2.11", Labels: req.Labels}, nil
+			}
+
+			publicIP, err := ensurePublicIP(ctx, mockClient, projectID, "test-machine", "test-machine-class", "new-uid", providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(publicIP.ID).To(Equal("ip-new"))
+			Expect(createdLabels).To(HaveKeyWithValue(StackitMachineUIDLabel, "new-uid"))
+		})
+
+		It("should claim a free public IP from the pool", func() {
+			providerSpec.PublicIP.PoolLabels = map[string]string{"pool": "edge"}
+			mockClient.ListPublicIPsFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.PublicIP, error) {
+				if labelSelector["pool"] != "edge" {
+					return nil, nil
+				}
+				return []*client.PublicIP{
+					{ID: "ip-1", IP: "192.0.2.10", NICID: "other-nic", Labels: map[string]string{"pool": "edge"}},
+					{ID: "ip-2", IP: "192.0.2.11", Labels: map[string]string{"pool": "edge", StackitMachineLabel: "other-machine"}},
+					{ID: "ip-3", IP: "192.0.2.12", Labels: map[string]string{"pool": "edge", StackitMachineLabel: ""}},
+				}, nil
+			}
+			var claimedID string
+			var claimedLabels map[string]string
+			mockClient.UpdatePublicIPFunc = func(_ context.Context, _, _, publicIPID string, req *client.UpdatePublicIPRequest) (*client.PublicIP, error) {
+				claimedID = publicIPID
+				claimedLabels = req.Labels
+				return &client.PublicIP{ID: publicIPID, IP: "192.0.2.12", Labels: req.Labels}, nil
+			}
+			mockClient.GetPublicIPFunc = func(_ context.Context, _, _, publicIPID string) (*client.PublicIP, error) {
+				return &client.PublicIP{ID: publicIPID, IP: "192.0.2.12", Labels: claimedLabels}, nil
+			}
+
+			publicIP, err := ensurePublicIP(ctx, mockClient, projectID, "test-machine", "test-machine-class", "machine-uid", providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(publicIP.IP).To(Equal("192.0.2.12"))
+			Expect(claimedID).To(Equal("ip-3"))
+			Expect(claimedLabels).To(HaveKeyWithValue("pool", "edge"))
+			Expect(claimedLabels).To(HaveKeyWithValue(StackitMachineLabel, "test-machine"))
+			Expect(claimedLabels).To(HaveKeyWithValue(StackitMachineUIDLabel, "machine-uid"))
+			Expect(claimedLabels).To(HaveKeyWithValue(StackitPublicIPPoolLabel, "true"))
+		})
+
+		It("should move on to the next pool public IP when another machine won the claim", func() {
+			providerSpec.PublicIP.PoolLabels = map[string]string{"pool": "edge"}
+			mockClient.ListPublicIPsFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.PublicIP, error) {
+				if labelSelector["pool"] != "edge" {
+					return nil, nil
+				}
+				return []*client.PublicIP{
+					{ID: "ip-1", IP: "192.0.2.10", Labels: map[string]string{"pool": "edge"}},
+					{ID: "ip-2", IP: "192.0.2.11", Labels: map[string]string{"pool": "edge"}},
+				}, nil
+			}
+			var claimedIDs []string
+			mockClient.UpdatePublicIPFunc = func(_ context.Context, _, _, publicIPID string, req *client.UpdatePublicIPRequest) (*client.PublicIP, error) {
+				claimedIDs = append(claimedIDs, publicIPID)
+				return &client.PublicIP{ID: publicIPID, Labels: req.Labels}, nil
+			}
+			mockClient.GetPublicIPFunc = func(_ context.Context, _, _, publicIPID string) (*client.PublicIP, error) {
+				// the concurrent claim of another machine landed after ours on ip-1
+				owner := "test-machine"
+				if publicIPID == "ip-1" {
+					owner = "other-machine"
+				}
+				return &client.PublicIP{ID: publicIPID, Labels: map[string]string{"pool": "edge", StackitMachineLabel: owner}}, nil
+			}
+
+			publicIP, err := ensurePublicIP(ctx, mockClient, projectID, "test-machine", "test-machine-class", "", providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(publicIP.ID).To(Equal("ip-2"))
+			Expect(claimedIDs).To(Equal([]string{"ip-1", "ip-2"}))
+		})
+
+		It("should return ResourceExhausted when every pool public IP was claimed by another machine", func() {
+			providerSpec.PublicIP.PoolLabels = map[string]string{"pool": "edge"}
+			mockClient.ListPublicIPsFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.PublicIP, error) {
+				if labelSelector["pool"] != "edge" {
+					return nil, nil
+				}
+				return []*client.PublicIP{{ID: "ip-1", IP: "192.0.2.10", Labels: map[string]string{"pool": "edge"}}}, nil
+			}
+			mockClient.GetPublicIPFunc = func(_ context.Context, _, _, publicIPID string) (*client.PublicIP, error) {
+				return &client.PublicIP{ID: publicIPID, IP: "192.0.2.10", Labels: map[string]string{StackitMachineLabel: "other-machine"}}, nil
+			}
+
+			_, err := ensurePublicIP(ctx, mockClient, projectID, "test-machine", "test-machine-class", "", providerSpec)
+
+			Expect(err).To(MatchError(client.ErrCapacityExhausted))
+		})
+	})
+
+	Describe("associatePublicIP", func() {
+		It("should associate the public IP with the first NIC in the configured network", func() {
+			nics := []*client.NIC{
+				{ID: "nic-other", NetworkID: "other-network"},
+				{ID: "nic-1", NetworkID: "770e8400-e29b-41d4-a716-446655440000", IPv4: "10.0.0.2"},
+			}
+			var associatedNIC *string
+			mockClient.UpdatePublicIPFunc = func(_ context.Context, _, _, publicIPID string, req *client.UpdatePublicIPRequest) (*client.PublicIP, error) {
+				associatedNIC = req.NICID
+				return &client.PublicIP{ID: publicIPID}, nil
+			}
+
+			result, err := associatePublicIP(ctx, mockClient, projectID, &client.PublicIP{ID: "ip-1", IP: "192.0.2.10"}, nics, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(associatedNIC).To(HaveValue(Equal("nic-1")))
+			Expect(nicAddresses(result)).To(ConsistOf(
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.2"},
+				corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "192.0.2.10"},
+			))
+		})
+
+		It("should skip public IPs that are already associated", func() {
+			nics := []*client.NIC{{ID: "nic-1", NetworkID: "770e8400-e29b-41d4-a716-446655440000"}}
+			mockClient.UpdatePublicIPFunc = func(_ context.Context, _, _, _ string, _ *client.UpdatePublicIPRequest) (*client.PublicIP, error) {
+				Fail("UpdatePublicIP must not be called")
+				return nil, nil
+			}
+
+			_, err := associatePublicIP(ctx, mockClient, projectID, &client.PublicIP{ID: "ip-1", IP: "192.0.2.10", NICID: "nic-1"}, nics, providerSpec)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("releasePublicIPs", func() {
+		It("should delete allocated public IPs and return pool public IPs", func() {
+			mockClient.ListPublicIPsFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.PublicIP, error) {
+				return []*client.PublicIP{
+					{ID: "ip-allocated", IP: "192.0.2.10", Labels: map[string]string{StackitMachineLabel: "test-machine"}},
+					{ID: "ip-pool", IP: "192.0.2.11", NICID: "nic-1", Labels: map[string]string{"pool": "edge", StackitMachineLabel: "test-machine", StackitPublicIPPoolLabel: "true"}},
+				}, nil
+			}
+			var deleted []string
+			mockClient.DeletePublicIPFunc = func(_ context.Context, _, _, publicIPID string) error {
+				deleted = append(deleted, publicIPID)
+				return client.ErrNotFound
+			}
+			var returned *client.UpdatePublicIPRequest
+			mockClient.UpdatePublicIPFunc = func(_ context.Context, _, _, _ string, req *client.UpdatePublicIPRequest) (*client.PublicIP, error) {
+				returned = req
+				return &client.PublicIP{}, nil
+			}
+
+			Expect(releasePublicIPs(ctx, mockClient, projectID, "eu01", "test-machine", "")).To(Succeed())
+
+			Expect(deleted).To(ConsistOf("ip-allocated"))
+			Expect(returned.DissociateNIC).To(BeTrue())
+			Expect(returned.NICID).To(BeNil())
+			Expect(returned.Labels).NotTo(HaveKey(StackitMachineLabel))
+			Expect(returned.Labels).NotTo(HaveKey(StackitMachineClassLabel))
+			Expect(returned.Labels).NotTo(HaveKey(StackitMachineUIDLabel))
+			Expect(returned.Labels).To(HaveKeyWithValue("pool", "edge"))
+		})
+
+		It("should keep public IPs of an earlier machine with the same name", func() {
+			mockClient.ListPublicIPsFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.PublicIP, error) {
+				return []*client.PublicIP{
+					{ID: "ip-old", IP: "192.0.2.10", Labels: map[string]string{StackitMachineLabel: "test-machine", StackitMachineUIDLabel: "old-uid"}},
+					{ID: "ip-new", IP: "192.0.2.11", Labels: map[string]string{StackitMachineLabel: "test-machine", StackitMachineUIDLabel: "new-uid"}},
+				}, nil
+			}
+			var deleted []string
+			mockClient.DeletePublicIPFunc = func(_ context.Context, _, _, publicIPID string) error {
+				deleted = append(deleted, publicIPID)
+				return nil
+			}
+
+			Expect(releasePublicIPs(ctx, mockClient, projectID, "eu01", "test-machine", "new-uid")).To(Succeed())
+
+			Expect(deleted).To(Equal([]string{"ip-new"}))
+		})
+	})
+
+	Describe("missingPublicIP", func() {
+		It("should be true until the primary NIC reports a public IP", func() {
+			server := &client.Server{NICs: []*client.NIC{{ID: "nic-1", NetworkID: "770e8400-e29b-41d4-a716-446655440000"}}}
+			Expect(missingPublicIP(server, providerSpec)).To(BeTrue())
+
+			server.NICs[0].PublicIP = "192.0.2.10"
+			Expect(missingPublicIP(server, providerSpec)).To(BeFalse())
+		})
+	})
+})
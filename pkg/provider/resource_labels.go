@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	"k8s.io/klog/v2"
+)
+
+// labelServerResources labels the boot volume and the NICs created along with the server for the machine
+//
+// The IaaS API creates them without labels, so DeleteMachine and the orphan collector could not find them
+// once the server is gone. NICs of networking.nicIds and a boot volume of source type "volume" are not
+// owned by the machine and are not labelled. Other labels of a resource are kept.
+func labelServerResources(ctx context.Context, c client.StackitClient, projectID, machineName, machineClassName, machineUID string, server *client.Server, nics []*client.NIC, providerSpec *api.ProviderSpec) error {
+	labels := machineLabels(machineName, machineClassName, machineUID)
+
+	if server.BootVolumeID != "" && !bootVolumeFromVolume(providerSpec) {
+		volume, err := c.GetVolume(ctx, projectID, providerSpec.Region, server.BootVolumeID)
+		if err != nil {
+			return fmt.Errorf("failed to get boot volume %q: %w", server.BootVolumeID, err)
+		}
+
+		// a boot volume kept by deleteOnTermination false is left to the orphan collector, unlike data volumes
+		if merged, changed := mergeLabels(volume.Labels, labels); changed {
+			if _, err := c.UpdateVolume(ctx, projectID, providerSpec.Region, volume.ID, &client.UpdateVolumeRequest{Labels: merged}); err != nil {
+				return fmt.Errorf("failed to label boot volume %q: %w", volume.ID, err)
+			}
+			klog.V(2).Infof("Labelled boot volume %q for machine %q", volume.ID, machineName)
+		}
+	}
+
+	for _, nic := range nics {
+		if providerSpec.Networking != nil && slices.Contains(providerSpec.Networking.NICIDs, nic.ID) {
+			continue
+		}
+
+		// NICs of a server carry no labels, only the NIC itself reports them
+		current, err := c.GetNIC(ctx, projectID, providerSpec.Region, nic.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get NIC %q: %w", nic.ID, err)
+		}
+		if merged, changed := mergeLabels(current.Labels, labels); changed {
+			if _, err := c.UpdateNICLabels(ctx, projectID, providerSpec.Region, nic.NetworkID, nic.ID, merged); err != nil {
+				return fmt.Errorf("failed to label NIC %q: %w", nic.ID, err)
+			}
+			klog.V(2).Infof("Labelled NIC %q for machine %q", nic.ID, machineName)
+		}
+	}
+
+	return nil
+}
+
+// machineLabels returns the labels of the resources created or claimed for a machine
+// The UID label is only set for Machines with UID
+func machineLabels(machineName, machineClassName, machineUID string) map[string]string {
+	labels := map[string]string{
+		StackitMachineLabel:      machineName,
+		StackitMachineClassLabel: machineClassName,
+	}
+	if machineUID != "" {
+		labels[StackitMachineUIDLabel] = machineUID
+	}
+	return labels
+}
+
+// bootVolumeFromVolume returns true if the server boots from an existing volume of the ProviderSpec
+func bootVolumeFromVolume(providerSpec *api.ProviderSpec) bool {
+	return providerSpec.BootVolume != nil && providerSpec.BootVolume.Source != nil && providerSpec.BootVolume.Source.Type == "volume"
+}
+
+// mergeLabels returns the current labels with the wanted labels set
+// Returns false if the current labels already contain all wanted labels
+func mergeLabels(current, wanted map[string]string) (map[string]string, bool) {
+	changed := false
+	for key, value := range wanted {
+		if current[key] != value {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return current, false
+	}
+
+	merged := make(map[string]string, len(current)+len(wanted))
+	maps.Copy(merged, current)
+	maps.Copy(merged, wanted)
+	return merged, true
+}
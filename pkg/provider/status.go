@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
@@ -21,10 +22,20 @@ import (
 // Returns:
 //   - ProviderID: The machine's ProviderID
 //   - NodeName: Name that the VM registered with in Kubernetes
+//   - Addresses: Internal IP addresses of the server's NICs (NodeInternalIP) and their public IPs (NodeExternalIP)
+//
+// The response is also returned along with the server state error codes below.
 //
 // Error codes:
 //   - NotFound: Machine has no ProviderID yet, or server not found in STACKIT
+//   - Uninitialized: Server is still CREATING or InitializeMachine has not completed, MCM calls InitializeMachine
+//   - Internal: Server is in ERROR state, the message contains the server's error message
+//   - ResourceExhausted: Server is in ERROR state because no capacity was available, its zone is tried last for the machine type
+//   - FailedPrecondition: Server is stopped or shut off
+//   - Aborted: Server is being deleted
 //   - InvalidArgument: Invalid ProviderID format
+//   - Unauthenticated / PermissionDenied: Service account credentials rejected or missing permissions
+//   - Unavailable: Rate limiting or STACKIT server errors
 //   - Internal: Failed to get server status or communicate with STACKIT API
 func (p *Provider) GetMachineStatus(ctx context.Context, req *driver.GetMachineStatusRequest) (*driver.GetMachineStatusResponse, error) {
 	// Log messages to track start and end of request
@@ -41,8 +52,9 @@ func (p *Provider) GetMachineStatus(ctx context.Context, req *driver.GetMachineS
 	// Extract credentials from Secret
 	projectIDFromSecret, serviceAccountKey := extractSecretCredentials(req.Secret.Data)
 
-	// Initialize client on first use (lazy initialization)
-	if err := p.ensureClient(serviceAccountKey); err != nil {
+	// Resolve client for the Secret credentials (created on first use or after rotation)
+	c, err := p.getClient(projectIDFromSecret, serviceAccountKey)
+	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to initialize STACKIT client: %v", err))
 	}
 
@@ -63,22 +75,70 @@ func (p *Provider) GetMachineStatus(ctx context.Context, req *driver.GetMachineS
 	}
 
 	// Call STACKIT API to get server status
-	server, err := p.client.GetServer(ctx, projectID, providerSpec.Region, serverID)
+	server, err := c.GetServer(ctx, projectID, providerSpec.Region, serverID)
 	if err != nil {
 		// Check if server was not found (404)
-		if errors.Is(err, client.ErrServerNotFound) {
+		if errors.Is(err, client.ErrNotFound) {
 			klog.V(2).Infof("Server %q not found for machine %q", serverID, req.Machine.Name)
 			return nil, status.Error(codes.NotFound, fmt.Sprintf("server %q not found", serverID))
 		}
-		// All other errors are internal errors
+		// All other errors are mapped by their class, unclassified errors are internal errors
 		klog.Errorf("Failed to get server status for machine %q: %v", req.Machine.Name, err)
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get server status: %v", err))
+		return nil, status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to get server status: %v", err))
 	}
 
 	klog.V(2).Infof("Retrieved server status for machine %q: status=%s", req.Machine.Name, server.Status)
 
-	return &driver.GetMachineStatusResponse{
+	resp := &driver.GetMachineStatusResponse{
 		ProviderID: req.Machine.Spec.ProviderID,
 		NodeName:   req.Machine.Name,
-	}, nil
+		Addresses:  nicAddresses(server.NICs),
+	}
+
+	// broken servers are reported so MCM does not treat them as healthy
+	if err := serverStateError(server); err != nil {
+		klog.Warningf("Server %q for machine %q is unhealthy: %v", serverID, req.Machine.Name, err)
+		// MCM replaces the Machine, its new server tries the zone last
+		if server.Status == serverStatusError && client.ClassifyServerErrorMessage(server.ErrorMessage) != nil {
+			p.exhaustedZones.markExhausted(projectID, providerSpec.Region, server.AvailabilityZone, machineTypeOf(server))
+		}
+		return resp, err
+	}
+
+	// data volume IDs are only known to the volume API
+	var dataVolumeIDs []string
+	if server.Status == serverStatusActive && len(providerSpec.DataVolumes) > 0 {
+		dataVolumeIDs, err = listDataVolumeIDs(ctx, c, projectID, providerSpec.Region, req.Machine.Name, string(req.Machine.UID))
+		if err != nil {
+			klog.Errorf("Failed to get data volumes for machine %q: %v", req.Machine.Name, err)
+			return nil, status.Error(errorCode(err, codes.Internal), fmt.Sprintf("failed to get data volumes: %v", err))
+		}
+	}
+
+	// MCM reads ProviderID and NodeName from the response along with the Uninitialized code
+	if needsInitialization(server, providerSpec, dataVolumeIDs) {
+		klog.V(2).Infof("Server %q for machine %q is not initialized yet", serverID, req.Machine.Name)
+		return resp, status.Error(codes.Uninitialized, fmt.Sprintf("server %q is not initialized yet", serverID))
+	}
+
+	return resp, nil
+}
+
+// serverStateError returns a status error for servers that cannot run a node, nil otherwise
+func serverStateError(server *client.Server) error {
+	switch {
+	case server.Status == serverStatusError:
+		code := codes.Internal
+		if client.ClassifyServerErrorMessage(server.ErrorMessage) != nil {
+			code = codes.ResourceExhausted
+		}
+		return status.Error(code, fmt.Sprintf("server %q is in ERROR state: %q", server.ID, server.ErrorMessage))
+	case server.Status == serverStatusDeleting:
+		return status.Error(codes.Aborted, fmt.Sprintf("server %q is being deleted", server.ID))
+	case slices.Contains(stoppedServerStatuses, server.Status):
+		return status.Error(codes.FailedPrecondition, fmt.Sprintf("server %q is stopped (status %s)", server.ID, server.Status))
+	case slices.Contains(stoppedPowerStatuses, server.PowerStatus):
+		return status.Error(codes.FailedPrecondition, fmt.Sprintf("server %q is not running (power status %s)", server.ID, server.PowerStatus))
+	}
+	return nil
 }
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
@@ -122,6 +123,161 @@ var _ = Describe("GetMachineStatus", func() {
 		})
 	})
 
+	Context("when the server is not initialized", func() {
+		It("should return Uninitialized along with the response while the server is CREATING", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				return &client.Server{
+					ID:     serverID,
+					Name:   "test-machine",
+					Status: "CREATING",
+				}, nil
+			}
+
+			resp, err := provider.GetMachineStatus(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Uninitialized))
+			// MCM uses ProviderID and NodeName of the response to call InitializeMachine
+			Expect(resp).NotTo(BeNil())
+			Expect(resp.ProviderID).To(Equal(machine.Spec.ProviderID))
+			Expect(resp.NodeName).To(Equal("test-machine"))
+		})
+
+		It("should return Uninitialized when a volume is not attached yet", func() {
+			providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{
+				MachineType: "c2i.2",
+				ImageID:     "image-uuid-123",
+				Region:      "eu01",
+				Volumes:     []string{"volume-1"},
+			})
+			machineClass.ProviderSpec.Raw = providerSpecRaw
+
+			_, err := provider.GetMachineStatus(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Uninitialized))
+		})
+
+		It("should not count volumes attached by others as data volumes", func() {
+			providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{
+				MachineType: "c2i.2",
+				ImageID:     "image-uuid-123",
+				Region:      "eu01",
+				DataVolumes: []api.DataVolumeSpec{{Name: "data", Size: 10}},
+			})
+			machineClass.ProviderSpec.Raw = providerSpecRaw
+			attached := []string{"boot-volume", "csi-volume"}
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Status: "ACTIVE", BootVolumeID: "boot-volume", VolumeIDs: attached}, nil
+			}
+			mockClient.ListVolumesFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.Volume, error) {
+				Expect(labelSelector).To(Equal(map[string]string{"kubernetes.io/machine": "test-machine"}))
+				return []*client.Volume{
+					{ID: "data-volume", Labels: map[string]string{"kubernetes.io/data-volume": "data"}},
+				}, nil
+			}
+
+			_, err := provider.GetMachineStatus(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.Uninitialized))
+
+			attached = append(attached, "data-volume")
+			_, err = provider.GetMachineStatus(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when reporting addresses", func() {
+		It("should return the internal IP addresses of the server's NICs", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				return &client.Server{
+					ID:     serverID,
+					Name:   "test-machine",
+					Status: "ACTIVE",
+					NICs: []*client.NIC{
+						{ID: "nic-1", IPv4: "10.0.0.5"},
+						{ID: "nic-2", IPv4: "10.1.0.5"},
+					},
+				}, nil
+			}
+
+			resp, err := provider.GetMachineStatus(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Addresses).To(ConsistOf(
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.5"},
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.1.0.5"},
+			))
+		})
+	})
+
+	Context("when the server is unhealthy", func() {
+		DescribeTable("should map the server state to an error code and still return the response",
+			func(server client.Server, expectedCode codes.Code) {
+				mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+					server.ID = serverID
+					server.Name = "test-machine"
+					server.NICs = []*client.NIC{{ID: "nic-1", IPv4: "10.0.0.5"}}
+					return &server, nil
+				}
+
+				resp, err := provider.GetMachineStatus(ctx, req)
+
+				Expect(err).To(HaveOccurred())
+				statusErr, ok := status.FromError(err)
+				Expect(ok).To(BeTrue())
+				Expect(statusErr.Code()).To(Equal(expectedCode))
+				Expect(resp).NotTo(BeNil())
+				Expect(resp.ProviderID).To(Equal(machine.Spec.ProviderID))
+				Expect(resp.NodeName).To(Equal("test-machine"))
+				Expect(resp.Addresses).To(HaveLen(1))
+			},
+			Entry("ERROR", client.Server{Status: "ERROR", ErrorMessage: "Build of instance aborted"}, codes.Internal),
+			Entry("ERROR without capacity", client.Server{Status: "ERROR", ErrorMessage: "No valid host was found"}, codes.ResourceExhausted),
+			Entry("DELETING", client.Server{Status: "DELETING"}, codes.Aborted),
+			Entry("INACTIVE", client.Server{Status: "INACTIVE"}, codes.FailedPrecondition),
+			Entry("SHUTOFF", client.Server{Status: "SHUTOFF"}, codes.FailedPrecondition),
+			Entry("powered off", client.Server{Status: "ACTIVE", PowerStatus: "STOPPED"}, codes.FailedPrecondition),
+			Entry("crashed", client.Server{Status: "ACTIVE", PowerStatus: "CRASHED"}, codes.FailedPrecondition),
+		)
+
+		It("should try the zone of a server without capacity last", func() {
+			provider.exhaustedZones = newZoneCache(time.Minute)
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				return &client.Server{
+					ID:               serverID,
+					Status:           "ERROR",
+					ErrorMessage:     "No valid host was found",
+					AvailabilityZone: "eu01-1",
+					Labels:           map[string]string{StackitMachineTypeLabel: "c2i.2"},
+				}, nil
+			}
+
+			_, err := provider.GetMachineStatus(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			Expect(provider.exhaustedZones.isExhausted("11111111-2222-3333-4444-555555555555", "eu01", "eu01-1", "c2i.2")).To(BeTrue())
+		})
+
+		It("should include the server error message", func() {
+			mockClient.GetServerFunc = func(_ context.Context, _, _, serverID string) (*client.Server, error) {
+				return &client.Server{ID: serverID, Status: "ERROR", ErrorMessage: "Build of instance aborted"}, nil
+			}
+
+			_, err := provider.GetMachineStatus(ctx, req)
+
+			Expect(err).To(MatchError(ContainSubstring("Build of instance aborted")))
+		})
+	})
+
 	Context("with missing or invalid ProviderID", func() {
 		It("should return NotFound when ProviderID is missing (machine not created yet)", func() {
 			machine.Spec.ProviderID = ""
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	client2 "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis/validation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// MachineClassValidator validates STACKIT MachineClasses outside of driver calls, e.g. in an admission webhook
+//
+// Design: Same checks as CreateMachine
+// - The ProviderSpec is decoded and validated like in CreateMachine
+// - Without a Secret only the ProviderSpec is validated and the preflight is skipped
+// - Unknown ProviderSpec fields are errors, or warnings in lenient mode
+// - The preflight is not cached, MachineClasses are validated once per change
+type MachineClassValidator struct {
+	client    client2.StackitClient // Static STACKIT API client, bypasses the cache when set (used to inject mocks in tests)
+	clients   *clientCache          // Clients keyed by credential hash
+	preflight bool                  // Check the referenced resources through the IaaS API
+	lenient   bool                  // Report unknown ProviderSpec fields as warnings instead of errors
+}
+
+// NewMachineClassValidator returns a MachineClassValidator, optionally running the preflight of CreateMachine
+func NewMachineClassValidator(preflight, lenientDecoding bool) *MachineClassValidator {
+	return &MachineClassValidator{
+		clients:   newClientCache(newSdkClientFactory(client2.DefaultRetryConfig())),
+		preflight: preflight,
+		lenient:   lenientDecoding,
+	}
+}
+
+// Validate returns the field errors of the MachineClass and its Secret, and warnings that do not reject it
+// Errors other than invalid fields or missing resources, e.g. an unreachable IaaS API, are returned as error
+func (v *MachineClassValidator) Validate(ctx context.Context, machineClass *v1alpha1.MachineClass, secret *corev1.Secret) (field.ErrorList, []string, error) {
+	providerSpec, err := decodeProviderSpec(machineClass)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("providerSpec"), field.OmitValueType{}, err.Error())}, nil, nil
+	}
+	if providerSpec == nil {
+		return field.ErrorList{field.Required(field.NewPath("providerSpec"), "")}, nil, nil
+	}
+
+	var validationErrs field.ErrorList
+	var warnings []string
+	for _, unknownField := range unknownProviderSpecFields(machineClass) {
+		if v.lenient {
+			warnings = append(warnings, unknownField.Error())
+		} else {
+			validationErrs = append(validationErrs, unknownField)
+		}
+	}
+
+	if secret == nil {
+		return append(validationErrs, validation.ValidateProviderSpec(providerSpec)...), warnings, nil
+	}
+
+	validationErrs = append(validationErrs, validation.ValidateProviderSpecNSecret(providerSpec, secret)...)
+	if len(validationErrs) > 0 || !v.preflight {
+		return validationErrs, warnings, nil
+	}
+
+	projectID, serviceAccountKey := extractSecretCredentials(secret.Data)
+	c := v.client
+	if c == nil {
+		c, err = v.clients.get(projectID, serviceAccountKey)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("failed to initialize STACKIT client: %w", err)
+		}
+	}
+
+	preflightErrs, err := preflightProviderSpec(ctx, c, projectID, providerSpec)
+	return preflightErrs, warnings, err
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("MachineClassValidator", func() {
+	var (
+		ctx          context.Context
+		mockClient   *mock.StackitClient
+		validator    *MachineClassValidator
+		machineClass *v1alpha1.MachineClass
+		secret       *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockClient = &mock.StackitClient{}
+		validator = &MachineClassValidator{client: mockClient, preflight: true}
+		providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{
+			MachineType: "c2i.2",
+			ImageID:     "12345678-1234-1234-1234-123456789abc",
+			Region:      "eu01",
+			Networking:  &api.NetworkingSpec{NetworkID: "770e8400-e29b-41d4-a716-446655440000"},
+			KeypairName: "my-key",
+		})
+		machineClass = &v1alpha1.MachineClass{
+			ObjectMeta:   metav1.ObjectMeta{Name: "test-machine-class", Namespace: "default"},
+			Provider:     "stackit",
+			ProviderSpec: runtime.RawExtension{Raw: providerSpecRaw},
+		}
+		secret = &corev1.Secret{Data: map[string][]byte{
+			"project-id":          []byte("11111111-2222-3333-4444-555555555555"),
+			"serviceaccount.json": []byte(`{"credentials":{"iss":"test"}}`),
+		}}
+		mockClient.GetKeypairFunc = func(_ context.Context, _ string) (*client.Keypair, error) {
+			return nil, &client.APIError{Class: client.ErrNotFound, StatusCode: 404}
+		}
+	})
+
+	It("should run the preflight for a valid MachineClass", func() {
+		errs, _, err := validator.Validate(ctx, machineClass, secret)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("providerSpec.keypairName"))
+	})
+
+	It("should skip the preflight when it is disabled", func() {
+		validator.preflight = false
+
+		errs, _, err := validator.Validate(ctx, machineClass, secret)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(BeEmpty())
+	})
+
+	It("should validate only the ProviderSpec without a Secret", func() {
+		errs, _, err := validator.Validate(ctx, machineClass, nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(BeEmpty())
+	})
+
+	It("should report unknown fields as errors or as warnings in lenient mode", func() {
+		machineClass.ProviderSpec.Raw = []byte(`{"machineType":"c2i.2","imageId":"12345678-1234-1234-1234-123456789abc","region":"eu01",` +
+			`"networking":{"networkId":"770e8400-e29b-41d4-a716-446655440000"},"securityGroup":["sg"]}`)
+
+		errs, warnings, err := validator.Validate(ctx, machineClass, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("providerSpec.securityGroup"))
+		Expect(warnings).To(BeEmpty())
+
+		validator.lenient = true
+		errs, warnings, err = validator.Validate(ctx, machineClass, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(BeEmpty())
+		Expect(warnings).To(ConsistOf("providerSpec.securityGroup: Forbidden: unknown field"))
+	})
+
+	It("should report a ProviderSpec that cannot be decoded", func() {
+		machineClass.ProviderSpec.Raw = []byte(`{"region": 1}`)
+
+		errs, _, err := validator.Validate(ctx, machineClass, secret)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("providerSpec"))
+	})
+})
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	"k8s.io/klog/v2"
+)
+
+// DefaultExhaustedZoneTTL is how long an availability zone without capacity for a machine type is tried last
+const DefaultExhaustedZoneTTL = 5 * time.Minute
+
+// zoneCache remembers availability zones that had no capacity left for a machine type
+//
+// Design: Short-lived negative cache
+// - Zones are only reordered, never excluded, so a machine is not stuck once all zones were exhausted
+// - Entries expire after the TTL, capacity shortages are usually temporary
+// - A nil cache remembers nothing
+type zoneCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	exhausted map[string]time.Time // zone key -> expiry
+	now       func() time.Time     // injectable for tests
+}
+
+// newZoneCache returns an empty zoneCache whose entries expire after ttl
+func newZoneCache(ttl time.Duration) *zoneCache {
+	return &zoneCache{
+		ttl:       ttl,
+		exhausted: make(map[string]time.Time),
+		now:       time.Now,
+	}
+}
+
+// markExhausted records that the zone has no capacity left for the machine type
+func (zc *zoneCache) markExhausted(projectID, region, zone, machineType string) {
+	if zc == nil || zone == "" {
+		return
+	}
+
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	zc.exhausted[zoneCacheKey(projectID, region, zone, machineType)] = zc.now().Add(zc.ttl)
+}
+
+// isExhausted returns true if the zone recently had no capacity left for the machine type
+func (zc *zoneCache) isExhausted(projectID, region, zone, machineType string) bool {
+	if zc == nil || zone == "" {
+		return false
+	}
+
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	key := zoneCacheKey(projectID, region, zone, machineType)
+	expires, ok := zc.exhausted[key]
+	if !ok {
+		return false
+	}
+	if !zc.now().Before(expires) {
+		delete(zc.exhausted, key)
+		return false
+	}
+	return true
+}
+
+// order returns the zones with recently exhausted zones moved to the end, keeping the order otherwise
+func (zc *zoneCache) order(projectID, region, machineType string, zones []string) []string {
+	ordered := slices.Clone(zones)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		return cmpBool(zc.isExhausted(projectID, region, a, machineType), zc.isExhausted(projectID, region, b, machineType))
+	})
+	return ordered
+}
+
+// zoneCacheKey returns the cache key of a zone and machine type
+func zoneCacheKey(projectID, region, zone, machineType string) string {
+	return strings.Join([]string{projectID, region, zone, machineType}, "\x00")
+}
+
+// cmpBool orders false before true
+func cmpBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// availabilityZonesOf returns the availability zones to try, an empty zone lets the API choose
+func availabilityZonesOf(providerSpec *api.ProviderSpec) []string {
+	if len(providerSpec.AvailabilityZones) > 0 {
+		return providerSpec.AvailabilityZones
+	}
+	return []string{providerSpec.AvailabilityZone}
+}
+
+// spreadZones orders the availability zones by the number of servers of the MachineClass they already host
+// Zones with fewer servers come first, ties keep the order of the ProviderSpec
+func spreadZones(ctx context.Context, c client.StackitClient, projectID, region, machineClassName string, zones []string) ([]string, error) {
+	if len(zones) < 2 {
+		return zones, nil
+	}
+
+	servers, err := c.ListServers(ctx, projectID, region, map[string]string{StackitMachineClassLabel: machineClassName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers of machine class %q: %w", machineClassName, err)
+	}
+
+	counts := make(map[string]int, len(zones))
+	for _, server := range servers {
+		counts[server.AvailabilityZone]++
+	}
+
+	ordered := slices.Clone(zones)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		return cmp.Compare(counts[a], counts[b])
+	})
+
+	klog.V(4).Infof("Availability zones of machine class %q ordered by server count %v: %v", machineClassName, counts, ordered)
+	return ordered, nil
+}
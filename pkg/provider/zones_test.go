@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/status"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client"
+	"github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/client/mock"
+	api "github.com/stackitcloud/machine-controller-manager-provider-stackit/pkg/provider/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Availability zones", func() {
+	const projectID = "11111111-2222-3333-4444-555555555555"
+
+	var (
+		ctx        context.Context
+		mockClient *mock.StackitClient
+		zones      *zoneCache
+		now        time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockClient = &mock.StackitClient{}
+		now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		zones = newZoneCache(5 * time.Minute)
+		zones.now = func() time.Time { return now }
+	})
+
+	Describe("spreadZones", func() {
+		It("should order zones by the number of servers of the MachineClass", func() {
+			var selector map[string]string
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, labelSelector map[string]string) ([]*client.Server, error) {
+				selector = labelSelector
+				return []*client.Server{
+					{ID: "s1", AvailabilityZone: "eu01-1"},
+					{ID: "s2", AvailabilityZone: "eu01-1"},
+					{ID: "s3", AvailabilityZone: "eu01-3"},
+				}, nil
+			}
+
+			ordered, err := spreadZones(ctx, mockClient, projectID, "eu01", "test-machine-class", []string{"eu01-1", "eu01-2", "eu01-3"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ordered).To(Equal([]string{"eu01-2", "eu01-3", "eu01-1"}))
+			Expect(selector).To(HaveKeyWithValue(StackitMachineClassLabel, "test-machine-class"))
+		})
+
+		It("should not list servers for a single zone", func() {
+			mockClient.ListServersFunc = func(_ context.Context, _, _ string, _ map[string]string) ([]*client.Server, error) {
+				Fail("ListServers must not be called")
+				return nil, nil
+			}
+
+			ordered, err := spreadZones(ctx, mockClient, projectID, "eu01", "test-machine-class", []string{"eu01-1"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ordered).To(Equal([]string{"eu01-1"}))
+		})
+	})
+
+	Describe("zoneCache", func() {
+		It("should try exhausted zones last until the entry expires", func() {
+			zones.markExhausted(projectID, "eu01", "eu01-1", "c2i.2")
+
+			Expect(zones.order(projectID, "eu01", "c2i.2", []string{"eu01-1", "eu01-2"})).To(Equal([]string{"eu01-2", "eu01-1"}))
+			Expect(zones.order(projectID, "eu01", "c3i.2", []string{"eu01-1", "eu01-2"})).To(Equal([]string{"eu01-1", "eu01-2"}))
+
+			now = now.Add(6 * time.Minute)
+			Expect(zones.order(projectID, "eu01", "c2i.2", []string{"eu01-1", "eu01-2"})).To(Equal([]string{"eu01-1", "eu01-2"}))
+		})
+	})
+
+	Describe("CreateMachine", func() {
+		var (
+			req      *driver.CreateMachineRequest
+			provider *Provider
+			attempts []string
+		)
+
+		BeforeEach(func() {
+			providerSpecRaw, _ := mock.EncodeProviderSpec(&api.ProviderSpec{
+				MachineType:       "c2i.2",
+				ImageID:           "12345678-1234-1234-1234-123456789abc",
+				Region:            "eu01",
+				AvailabilityZones: []string{"eu01-1", "eu01-2"},
+				Networking:        &api.NetworkingSpec{NetworkID: "770e8400-e29b-41d4-a716-446655440000"},
+			})
+			req = &driver.CreateMachineRequest{
+				Machine: &v1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}},
+				MachineClass: &v1alpha1.MachineClass{
+					ObjectMeta:   metav1.ObjectMeta{Name: "test-machine-class"},
+					Provider:     "stackit",
+					ProviderSpec: runtime.RawExtension{Raw: providerSpecRaw},
+				},
+				Secret: &corev1.Secret{Data: map[string][]byte{
+					"project-id":          []byte(projectID),
+					"serviceaccount.json": []byte(`{"credentials":{"iss":"test"}}`),
+				}},
+			}
+			provider = &Provider{client: mockClient, exhaustedZones: zones}
+
+			attempts = nil
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
+				attempts = append(attempts, req.AvailabilityZone)
+				if req.AvailabilityZone == "eu01-1" {
+					return nil, &client.APIError{Class: client.ErrCapacityExhausted, StatusCode: 409, Message: "No valid host was found"}
+				}
+				return &client.Server{ID: "new-server-id", Name: req.Name, AvailabilityZone: req.AvailabilityZone}, nil
+			}
+		})
+
+		It("should fall back to the next zone and remember the exhausted zone", func() {
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal([]string{"eu01-1", "eu01-2"}))
+			Expect(zones.isExhausted(projectID, "eu01", "eu01-1", "c2i.2")).To(BeTrue())
+
+			attempts = nil
+			_, err = provider.CreateMachine(ctx, req)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal([]string{"eu01-2"}))
+		})
+
+		It("should return ResourceExhausted when no zone has capacity", func() {
+			mockClient.CreateServerFunc = func(_ context.Context, _, _ string, req *client.CreateServerRequest) (*client.Server, error) {
+				attempts = append(attempts, req.AvailabilityZone)
+				return nil, &client.APIError{Class: client.ErrCapacityExhausted, StatusCode: 409, Message: "No valid host was found"}
+			}
+
+			_, err := provider.CreateMachine(ctx, req)
+
+			Expect(err).To(HaveOccurred())
+			statusErr, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.Code()).To(Equal(codes.ResourceExhausted))
+			Expect(attempts).To(Equal([]string{"eu01-1", "eu01-2"}))
+		})
+	})
+})
@@ -0,0 +1,163 @@
+// Package webhook implements a validating admission webhook for STACKIT MachineClasses
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+)
+
+// stackitProviderName is the provider of the MachineClasses validated by the webhook
+const stackitProviderName = "stackit"
+
+// Validator validates a MachineClass and its Secret
+// Implemented by provider.MachineClassValidator
+type Validator interface {
+	Validate(ctx context.Context, machineClass *v1alpha1.MachineClass, secret *corev1.Secret) (field.ErrorList, []string, error)
+}
+
+// SecretGetter returns the referenced Secret, or nil if it does not exist
+type SecretGetter func(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+
+// Handler serves AdmissionReviews for MachineClasses
+//
+// Design: Reject invalid MachineClasses at apply time
+// - MachineClasses of other providers and deletions are always admitted
+// - The Secrets referenced by secretRef and credentialsSecretRef are merged like MCM does before calling the driver
+// - A missing Secret only skips the Secret validation and the preflight, it may be applied after the MachineClass
+// - Errors of the STACKIT API admit the MachineClass with a warning, CreateMachine checks it again
+type Handler struct {
+	validator Validator
+	getSecret SecretGetter
+}
+
+// NewHandler returns a Handler validating MachineClasses with the given validator
+func NewHandler(validator Validator, getSecret SecretGetter) *Handler {
+	return &Handler{
+		validator: validator,
+		getSecret: getSecret,
+	}
+}
+
+// ServeHTTP decodes the AdmissionReview of the request and responds with the admission decision
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	review := &admissionv1.AdmissionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode AdmissionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "AdmissionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	response := h.review(r.Context(), review.Request)
+	response.UID = review.Request.UID
+	review.Response = response
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		klog.Errorf("Failed to write AdmissionReview response: %v", err)
+	}
+}
+
+// review returns the admission decision for the request
+func (h *Handler) review(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Kind.Kind != "MachineClass" || req.Operation == admissionv1.Delete {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	machineClass := &v1alpha1.MachineClass{}
+	if err := json.Unmarshal(req.Object.Raw, machineClass); err != nil {
+		return denied(http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("failed to decode MachineClass: %v", err))
+	}
+	if machineClass.Provider != stackitProviderName {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+	if machineClass.Namespace == "" {
+		machineClass.Namespace = req.Namespace
+	}
+	key := machineClass.Namespace + "/" + machineClass.Name
+
+	var warnings []string
+	secret, err := h.secret(ctx, machineClass)
+	switch {
+	case err != nil:
+		klog.Errorf("Failed to get secret of machine class %q: %v", key, err)
+		warnings = append(warnings, fmt.Sprintf("secret could not be read, only the providerSpec was validated: %v", err))
+	case secret == nil:
+		warnings = append(warnings, "secret does not exist, only the providerSpec was validated")
+	}
+
+	errs, validationWarnings, err := h.validator.Validate(ctx, machineClass, secret)
+	warnings = append(warnings, validationWarnings...)
+	if err != nil {
+		klog.Errorf("Failed to validate machine class %q: %v", key, err)
+		warnings = append(warnings, fmt.Sprintf("referenced resources could not be checked: %v", err))
+	}
+	if len(errs) > 0 {
+		klog.V(2).Infof("Rejected machine class %q: %v", key, errs.ToAggregate())
+		response := denied(http.StatusUnprocessableEntity, metav1.StatusReasonInvalid, fmt.Sprintf("MachineClass %q is invalid: %v", key, errs.ToAggregate()))
+		response.Warnings = warnings
+		return response
+	}
+
+	return &admissionv1.AdmissionResponse{Allowed: true, Warnings: warnings}
+}
+
+// secret returns the Secret passed to the driver for the MachineClass, the data of credentialsSecretRef wins over secretRef
+// Returns nil if the MachineClass references no existing Secret
+func (h *Handler) secret(ctx context.Context, machineClass *v1alpha1.MachineClass) (*corev1.Secret, error) {
+	var merged *corev1.Secret
+	for _, ref := range []*corev1.SecretReference{machineClass.SecretRef, machineClass.CredentialsSecretRef} {
+		if ref == nil {
+			continue
+		}
+
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = machineClass.Namespace
+		}
+		secret, err := h.getSecret(ctx, namespace, ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, ref.Name, err)
+		}
+		if secret == nil {
+			continue
+		}
+
+		if merged == nil {
+			merged = &corev1.Secret{Data: make(map[string][]byte)}
+		}
+		maps.Copy(merged.Data, secret.Data)
+	}
+	return merged, nil
+}
+
+// denied returns a response rejecting the request
+func denied(code int32, reason metav1.StatusReason, message string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    code,
+			Reason:  reason,
+			Message: message,
+		},
+	}
+}
@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// fakeValidator records the Secret it was called with and returns the configured result
+type fakeValidator struct {
+	errs     field.ErrorList
+	warnings []string
+	err      error
+	secret   *corev1.Secret
+	called   bool
+}
+
+func (v *fakeValidator) Validate(_ context.Context, _ *v1alpha1.MachineClass, secret *corev1.Secret) (field.ErrorList, []string, error) {
+	v.called = true
+	v.secret = secret
+	return v.errs, v.warnings, v.err
+}
+
+var _ = Describe("Handler", func() {
+	var (
+		validator    *fakeValidator
+		secrets      map[string]*corev1.Secret
+		handler      *Handler
+		machineClass *v1alpha1.MachineClass
+		operation    admissionv1.Operation
+	)
+
+	BeforeEach(func() {
+		validator = &fakeValidator{}
+		secrets = map[string]*corev1.Secret{
+			"default/machine-class-secret": {Data: map[string][]byte{"userData": []byte("#cloud-config")}},
+			"default/credentials":          {Data: map[string][]byte{"project-id": []byte("11111111-2222-3333-4444-555555555555")}},
+		}
+		handler = NewHandler(validator, func(_ context.Context, namespace, name string) (*corev1.Secret, error) {
+			return secrets[namespace+"/"+name], nil
+		})
+		machineClass = &v1alpha1.MachineClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "test-machine-class"},
+			Provider:             "stackit",
+			ProviderSpec:         runtime.RawExtension{Raw: []byte(`{"region":"eu01"}`)},
+			SecretRef:            &corev1.SecretReference{Name: "machine-class-secret"},
+			CredentialsSecretRef: &corev1.SecretReference{Name: "credentials", Namespace: "default"},
+		}
+		operation = admissionv1.Create
+	})
+
+	admit := func() *admissionv1.AdmissionResponse {
+		raw, err := json.Marshal(machineClass)
+		Expect(err).NotTo(HaveOccurred())
+		body, err := json.Marshal(&admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request: &admissionv1.AdmissionRequest{
+				UID:       types.UID("review-1"),
+				Kind:      metav1.GroupVersionKind{Group: "machine.sapcloud.io", Version: "v1alpha1", Kind: "MachineClass"},
+				Namespace: "default",
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body)))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		review := &admissionv1.AdmissionReview{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), review)).To(Succeed())
+		Expect(review.Kind).To(Equal("AdmissionReview"))
+		Expect(review.Response).NotTo(BeNil())
+		Expect(review.Response.UID).To(Equal(types.UID("review-1")))
+		return review.Response
+	}
+
+	It("should admit a valid MachineClass and merge its Secrets", func() {
+		response := admit()
+
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(BeEmpty())
+		Expect(validator.secret.Data).To(HaveKey("userData"))
+		Expect(validator.secret.Data).To(HaveKey("project-id"))
+	})
+
+	It("should reject an invalid MachineClass with all field errors", func() {
+		validator.errs = field.ErrorList{
+			field.Required(field.NewPath("providerSpec", "machineType"), ""),
+			field.Invalid(field.NewPath("providerSpec", "imageId"), "abc", "must be a valid UUID"),
+		}
+
+		response := admit()
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Code).To(Equal(int32(http.StatusUnprocessableEntity)))
+		Expect(response.Result.Reason).To(Equal(metav1.StatusReasonInvalid))
+		Expect(response.Result.Message).To(ContainSubstring("providerSpec.machineType: Required value"))
+		Expect(response.Result.Message).To(ContainSubstring(`providerSpec.imageId: Invalid value: "abc"`))
+	})
+
+	It("should validate only the ProviderSpec when the Secret does not exist", func() {
+		secrets = map[string]*corev1.Secret{}
+
+		response := admit()
+
+		Expect(response.Allowed).To(BeTrue())
+		Expect(validator.secret).To(BeNil())
+		Expect(response.Warnings).To(ConsistOf(ContainSubstring("secret does not exist")))
+	})
+
+	It("should return the warnings of the validator", func() {
+		validator.warnings = []string{"providerSpec.securityGroup: Forbidden: unknown field"}
+
+		response := admit()
+
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(ConsistOf("providerSpec.securityGroup: Forbidden: unknown field"))
+	})
+
+	It("should admit with a warning when the STACKIT API cannot be reached", func() {
+		validator.err = errors.New("service unavailable")
+
+		response := admit()
+
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(ConsistOf(ContainSubstring("service unavailable")))
+	})
+
+	It("should admit MachineClasses of other providers without validating them", func() {
+		machineClass.Provider = "openstack"
+
+		response := admit()
+
+		Expect(response.Allowed).To(BeTrue())
+		Expect(validator.called).To(BeFalse())
+	})
+
+	It("should admit deletions without validating them", func() {
+		operation = admissionv1.Delete
+
+		response := admit()
+
+		Expect(response.Allowed).To(BeTrue())
+		Expect(validator.called).To(BeFalse())
+	})
+
+	It("should reject requests without an AdmissionReview", func() {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader([]byte(`{}`))))
+
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	})
+})